@@ -2,67 +2,393 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/auction-goexpert/configuration/config"
 	"github.com/auction-goexpert/configuration/database/mongodb"
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/grpc/auction_service"
+	"github.com/auction-goexpert/internal/infra/api/grpc/pb"
+	"github.com/auction-goexpert/internal/infra/api/web/adminauth"
 	"github.com/auction-goexpert/internal/infra/api/web/controller/auction_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/audit_controller"
 	"github.com/auction-goexpert/internal/infra/api/web/controller/bid_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/exchange_rate_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/invoice_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/notification_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/offer_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/report_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/settlement_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/v2_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/wallet_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/watchlist_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/health"
+	"github.com/auction-goexpert/internal/infra/api/web/middleware"
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/infra/api/web/ratelimit"
+	"github.com/auction-goexpert/internal/infra/api/web/userauth"
+	"github.com/auction-goexpert/internal/infra/audit"
+	"github.com/auction-goexpert/internal/infra/billing"
 	"github.com/auction-goexpert/internal/infra/database/auction"
+	audit_repository "github.com/auction-goexpert/internal/infra/database/audit"
 	"github.com/auction-goexpert/internal/infra/database/bid"
+	"github.com/auction-goexpert/internal/infra/database/event_store"
+	"github.com/auction-goexpert/internal/infra/database/exchange_rate"
+	idempotency_repository "github.com/auction-goexpert/internal/infra/database/idempotency"
+	"github.com/auction-goexpert/internal/infra/database/invoice"
+	"github.com/auction-goexpert/internal/infra/database/migration"
+	"github.com/auction-goexpert/internal/infra/database/notification"
+	"github.com/auction-goexpert/internal/infra/database/offer"
+	"github.com/auction-goexpert/internal/infra/database/rate_limit"
+	"github.com/auction-goexpert/internal/infra/database/settlement"
 	"github.com/auction-goexpert/internal/infra/database/user"
+	"github.com/auction-goexpert/internal/infra/database/wallet"
+	"github.com/auction-goexpert/internal/infra/database/watchlist"
+	"github.com/auction-goexpert/internal/infra/fees"
+	"github.com/auction-goexpert/internal/infra/fx"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/auction-goexpert/internal/infra/metrics"
+	"github.com/auction-goexpert/internal/infra/notifier"
+	"github.com/auction-goexpert/internal/infra/scheduler"
+	"github.com/auction-goexpert/internal/infra/tracing"
 	"github.com/auction-goexpert/internal/usecase/auction_usecase"
+	"github.com/auction-goexpert/internal/usecase/audit_usecase"
 	"github.com/auction-goexpert/internal/usecase/bid_usecase"
+	"github.com/auction-goexpert/internal/usecase/exchange_rate_usecase"
+	"github.com/auction-goexpert/internal/usecase/invoice_usecase"
+	"github.com/auction-goexpert/internal/usecase/notification_usecase"
+	"github.com/auction-goexpert/internal/usecase/offer_usecase"
+	"github.com/auction-goexpert/internal/usecase/report_usecase"
+	"github.com/auction-goexpert/internal/usecase/settlement_usecase"
+	"github.com/auction-goexpert/internal/usecase/wallet_usecase"
+	"github.com/auction-goexpert/internal/usecase/watchlist_usecase"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"google.golang.org/grpc"
 )
 
+// shutdownTimeout é quanto o encerramento espera as requisições e os RPCs em andamento;
+// depois disso as conexões restantes, como os streams WatchAuction, são fechadas
+const shutdownTimeout = 15 * time.Second
+
 func main() {
 	ctx := context.Background()
 
-	// Carrega variáveis de ambiente
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
+	// Carrega e valida a configuração antes de subir qualquer dependência
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Fatal(slog.Default(), "Invalid configuration", err)
 	}
 
+	// Logger estruturado injetado nos componentes; também passa a ser o padrão do slog
+	logger := logging.New(cfg.Log, os.Stdout)
+	slog.SetDefault(logger)
+
+	// Tracing precisa estar configurado antes do router e da conexão com o MongoDB
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		logging.Fatal(logger, "Failed to set up tracing", err)
+	}
+
+	// SIGINT/SIGTERM recebidos durante a inicialização também são atendidos no fim de main
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
 	// Conecta ao MongoDB
-	database, err := mongodb.NewMongoDBConnection(ctx)
+	database, err := mongodb.NewMongoDBConnection(ctx, cfg.MongoDB, logger)
 	if err != nil {
-		log.Fatal("Failed to connect to MongoDB:", err)
+		logging.Fatal(logger, "Failed to connect to MongoDB", err)
 	}
 
-	// Inicializa repositories
-	auctionRepo := auction.NewAuctionRepository(database)
+	// Aplica as migrations pendentes (índices e dados), exceto se desabilitado
+	migrationRunner := migration.NewRunner(database, migration.Migrations, logger)
+	if cfg.MongoDB.MigrateOnStartup {
+		migrationCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		applied, err := migrationRunner.Up(migrationCtx)
+		cancel()
+		if err != nil {
+			logging.Fatal(logger, "Failed to apply migrations", err)
+		}
+		logger.Info("Migrations applied", slog.Int("count", len(applied)))
+	}
+
+	// Inicializa repositories; leilões e lances são gravados como eventos no event store
+	auctionEvents := event_store.NewAuctionEventStore(database, logger)
+	auctionRepo := auction.NewAuctionRepository(database, auctionEvents, cfg.Auction.Duration, cfg.Auction.CheckInterval, logger)
 	userRepo := user.NewUserRepository(database)
-	bidRepo := bid.NewBidRepository(database, auctionRepo)
+	walletRepo := wallet.NewWalletRepository(database, logger)
+	bidRepo := bid.NewBidRepository(database, auctionEvents, walletRepo, cfg.Auction.ExtensionWindow, logger)
+
+	notificationRepo := notification.NewNotificationRepository(database, logger)
+	notificationPreferenceRepo := notification.NewNotificationPreferenceRepository(database)
+	watchlistRepo := watchlist.NewWatchlistRepository(database)
+	exchangeRateRepo := exchange_rate.NewExchangeRateRepository(database)
+	idempotencyRepo := idempotency_repository.NewIdempotencyRepository(database)
+	auditRepo := audit_repository.NewAuditRepository(database)
+	settlementRepo := settlement.NewSettlementRepository(database)
+	offerRepo := offer.NewOfferRepository(database)
+	invoiceRepo := invoice.NewInvoiceRepository(database)
+
+	// Limites de requisição: em memória por réplica, ou compartilhados pelo MongoDB
+	var rateLimiter entity.RateLimiterInterface = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Backend == "mongo" {
+		rateLimiter = rate_limit.NewRateLimitRepository(database)
+	}
+
+	// Configura as notificações de lance superado e leilão vencido
+	templates, err := notifier.NewTemplates(cfg.Notification.DefaultLocale)
+	if err != nil {
+		logging.Fatal(logger, "Failed to load notification templates", err)
+	}
+
+	channels := []notifier.Channel{
+		notifier.NewInboxChannel(notificationRepo),
+		notifier.NewLogChannel(logger),
+	}
+	if smtp := cfg.Notification.SMTP; smtp.Host != "" {
+		channels = append(channels, notifier.NewEmailChannel(smtp.Host, smtp.Port, smtp.Username, smtp.Password, smtp.From))
+	}
+
+	dispatcher := notifier.NewDispatcher(auctionRepo, bidRepo, notificationRepo, notificationPreferenceRepo, templates, logger, channels...)
+	bidRepo.AddPlacedListener(dispatcher.OnBidPlaced)
+	auctionRepo.AddClosedListener(dispatcher.OnAuctionClosed)
+
+	// Métricas de lances aceitos e do total de leilões ativos
+	bidRepo.AddPlacedListener(metrics.OnBidPlaced)
+	metrics.RegisterActiveAuctions(auctionRepo.CountActiveAuctions, cfg.Health.CheckTimeout, logger)
+
+	// Streams WatchAuction do gRPC, alimentados pelo stream de eventos de cada leilão, o que
+	// inclui o que as outras réplicas gravam
+	watchHub := auction_service.NewWatchHub(auctionEvents, cfg.GRPC.WatchPollInterval, logger)
+	watchHub.Start()
+
+	// Log de auditoria das mudanças de estado de leilões e lances
+	auditRecorder := audit.NewRecorder(auditRepo, logger)
+	auditRecorder.Start()
+	auctionRepo.AddCreatedListener(auditRecorder.OnAuctionCreated)
+	auctionRepo.AddStatusChangedListener(auditRecorder.OnAuctionStatusChanged)
+	bidRepo.AddPlacedListener(auditRecorder.OnBidPlaced)
+
+	// Lembretes de fim de leilão para quem acompanha pela watchlist
+	notifier.NewReminderScheduler(auctionRepo, bidRepo, watchlistRepo, notificationPreferenceRepo, dispatcher, cfg.Notification.ReminderCheckInterval, logger).Start()
+
+	// Acerto dos leilões encerrados: abre o pagamento do vencedor e passa o item ao
+	// próximo maior lance quando o prazo de pagamento vence
+	settlementUseCase := settlement_usecase.NewSettlementUseCase(settlementRepo, bidRepo, walletRepo, cfg.Settlement.PaymentWindow, cfg.Settlement.MaxAttempts)
+	settlementUseCase.AddOfferedListener(dispatcher.OnSettlementOffered)
+	settlementScheduler := scheduler.NewSettlementScheduler(settlementUseCase, cfg.Settlement.CheckInterval, logger)
+	auctionRepo.AddClosedListener(settlementScheduler.OnAuctionClosed)
+	settlementScheduler.Start()
+
+	// Carteiras: os lances reservam saldo (em CreateBid), quem é superado tem a reserva
+	// liberada e, no encerramento, a reserva do vencedor é capturada. O cancelamento libera
+	// todas as reservas do leilão.
+	bidRepo.AddPlacedListener(walletRepo.OnBidPlaced)
+	auctionRepo.AddClosedListener(walletRepo.OnAuctionClosed)
+	auctionRepo.AddStatusChangedListener(walletRepo.OnAuctionStatusChanged)
+
+	// Tarifas e faturas: a tabela de tarifas é validada ao iniciar e as faturas do vendedor e
+	// do comprador são emitidas no encerramento
+	feeSchedules, err := fees.Schedules(cfg.Billing.FeeScheduleFile)
+	if err != nil {
+		logging.Fatal(logger, "Failed to load fee schedule", err)
+	}
+	invoiceUseCase := invoice_usecase.NewInvoiceUseCase(invoiceRepo, auctionRepo, bidRepo, feeSchedules)
+	invoicer := billing.NewInvoicer(invoiceUseCase, logger)
+	auctionRepo.AddClosedListener(invoicer.OnAuctionClosed)
+	settlementUseCase.AddOfferedListener(invoicer.OnSettlementOffered)
+
+	// Ofertas de segunda chance, depois que as tentativas automáticas se esgotam
+	offerUseCase := offer_usecase.NewOfferUseCase(offerRepo, auctionRepo, bidRepo, settlementRepo, settlementUseCase, logger)
+	offerUseCase.AddOfferedListener(dispatcher.OnSecondChanceOffered)
 
 	// Inicializa use cases
 	createAuctionUseCase := auction_usecase.NewCreateAuctionUseCase(auctionRepo)
-	findAuctionUseCase := auction_usecase.NewFindAuctionUseCase(auctionRepo)
+	findAuctionUseCase := auction_usecase.NewFindAuctionUseCase(auctionRepo, exchangeRateRepo)
 	createBidUseCase := bid_usecase.NewCreateBidUseCase(bidRepo)
+	createBidUseCase.AddRejectedListener(metrics.OnBidRejected)
+	createBidUseCase.AddRejectedListener(auditRecorder.OnBidRejected)
 	findBidUseCase := bid_usecase.NewFindBidUseCase(bidRepo)
+	findNotificationUseCase := notification_usecase.NewFindNotificationUseCase(notificationRepo)
+	notificationPreferenceUseCase := notification_usecase.NewNotificationPreferenceUseCase(notificationPreferenceRepo)
+	watchlistUseCase := watchlist_usecase.NewWatchlistUseCase(watchlistRepo, auctionRepo, bidRepo)
+	exchangeRateUseCase := exchange_rate_usecase.NewExchangeRateUseCase(exchangeRateRepo)
+	salesReportUseCase := report_usecase.NewSalesReportUseCase(auctionRepo, exchangeRateRepo)
+	auditUseCase := audit_usecase.NewAuditUseCase(auditRepo)
+	walletUseCase := wallet_usecase.NewWalletUseCase(walletRepo)
+
+	// Carrega a tabela de cotações do arquivo local, se configurado
+	if ratesFile := cfg.FX.RatesFile; ratesFile != "" {
+		rates, err := fx.ReadRatesFile(ratesFile)
+		if err != nil {
+			logging.Fatal(logger, "Failed to read exchange rates file", err)
+		}
+		if internalErr := exchangeRateUseCase.ImportExchangeRates(ctx, rates); internalErr != nil {
+			logging.Fatal(logger, "Failed to import exchange rates", internalErr)
+		}
+		logger.Info("Exchange rates loaded", slog.Int("count", len(rates)), slog.String("file", ratesFile))
+	}
+
+	// Verificações de saúde: MongoDB acessível e todas as migrations aplicadas
+	healthHandler := health.NewHandler(cfg.Health.CheckTimeout, auctionRepo,
+		health.Check{Name: "mongodb", Run: func(ctx context.Context) error {
+			return database.Client().Ping(ctx, readpref.Primary())
+		}},
+		health.Check{Name: "migrations", Run: func(ctx context.Context) error {
+			pending, err := migrationRunner.Pending(ctx)
+			if err != nil {
+				return err
+			}
+			if pending > 0 {
+				return fmt.Errorf("%d pending migration(s)", pending)
+			}
+			return nil
+		}},
+	)
 
 	// Inicializa controllers
 	auctionController := auction_controller.NewAuctionController(createAuctionUseCase, findAuctionUseCase)
 	bidController := bid_controller.NewBidController(createBidUseCase, findBidUseCase)
+	notificationController := notification_controller.NewNotificationController(findNotificationUseCase, notificationPreferenceUseCase)
+	watchlistController := watchlist_controller.NewWatchlistController(watchlistUseCase)
+	exchangeRateController := exchange_rate_controller.NewExchangeRateController(exchangeRateUseCase)
+	reportController := report_controller.NewReportController(salesReportUseCase)
+	auditController := audit_controller.NewAuditController(auditUseCase)
+	settlementController := settlement_controller.NewSettlementController(settlementUseCase)
+	offerController := offer_controller.NewOfferController(offerUseCase)
+	walletController := wallet_controller.NewWalletController(walletUseCase)
+	invoiceController := invoice_controller.NewInvoiceController(invoiceUseCase)
 
 	// Configura rotas
-	router := gin.Default()
+	router := gin.New()
+	router.Use(middleware.RequestID(), tracing.Middleware(cfg.Tracing.ServiceName), metrics.HTTPMiddleware(), logging.Middleware(logger), gin.CustomRecovery(problem.Recovery))
+	router.NoRoute(problem.NotFound)
+
+	if len(cfg.Admin.Tokens) == 0 {
+		logger.Warn("ADMIN_TOKENS is empty, admin routes will reject every request")
+	}
+	if len(cfg.User.Tokens) == 0 {
+		logger.Warn("USER_TOKENS is empty, settlement actions will reject every request")
+	}
+
+	// Sem proxies confiáveis o IP do cliente é o da conexão, e X-Forwarded-For é ignorado
+	if err := router.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		logging.Fatal(logger, "Invalid TRUSTED_PROXIES", err)
+	}
 
-	// Rotas de leilão
-	router.POST("/auction", auctionController.CreateAuction)
-	router.GET("/auction/:auctionId", auctionController.FindAuctionById)
-	router.GET("/auction", auctionController.FindAuctions)
+	registerRoutes(router, controllers{
+		auction:      auctionController,
+		bid:          bidController,
+		notification: notificationController,
+		watchlist:    watchlistController,
+		exchangeRate: exchangeRateController,
+		report:       reportController,
+		auctionV2:    v2_controller.NewAuctionController(createAuctionUseCase, findAuctionUseCase),
+		bidV2:        v2_controller.NewBidController(createBidUseCase, findBidUseCase),
+		audit:        auditController,
+		settlement:   settlementController,
+		offer:        offerController,
+		wallet:       walletController,
+		invoice:      invoiceController,
+		health:       healthHandler,
 
-	// Rotas de lance
-	router.POST("/bid", bidController.CreateBid)
-	router.GET("/bid/auction/:auctionId", bidController.FindBidByAuctionId)
-	router.GET("/bid/auction/:auctionId/winner", bidController.FindWinningBidByAuctionId)
+		adminAuth:     adminauth.Middleware(cfg.Admin.Tokens),
+		userAuth:      userauth.Middleware(cfg.User.Tokens),
+		auditRecorder: auditRecorder,
+	}, cfg, rateLimiter, idempotencyRepo, logger)
 
-	log.Println("Server starting on port 8080...")
-	if err := router.Run(":8080"); err != nil {
-		log.Fatal("Failed to start server:", err)
+	// API gRPC para serviços internos, sobre os mesmos use cases
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+	if err != nil {
+		logging.Fatal(logger, "Failed to listen on gRPC port", err)
 	}
+	// Os RPCs de criação passam pelos mesmos limites e pela mesma idempotência da API HTTP
+	grpcGuards := auction_service.NewGuards(rateLimiter, idempotencyRepo, auction_service.GuardPolicies{
+		AuctionIP:   cfg.RateLimit.AuctionIP,
+		AuctionUser: cfg.RateLimit.AuctionUser,
+		BidIP:       cfg.RateLimit.BidIP,
+		BidUser:     cfg.RateLimit.BidUser,
+	}, cfg.Idempotency.KeyTTL, logger)
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcGuards.UnaryInterceptor()))
+	pb.RegisterAuctionServiceServer(grpcServer, auction_service.NewAuctionServer(
+		createAuctionUseCase,
+		findAuctionUseCase,
+		createBidUseCase,
+		findBidUseCase,
+		watchHub,
+	))
+	go func() {
+		logger.Info("gRPC server starting", slog.Int("port", cfg.GRPC.Port))
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logging.Fatal(logger, "Failed to start gRPC server", err)
+		}
+	}()
+
+	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTP.Port), Handler: router}
+	go func() {
+		logger.Info("HTTP server starting", slog.Int("port", cfg.HTTP.Port))
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Fatal(logger, "Failed to start HTTP server", err)
+		}
+	}()
 
 	// Mantém o userRepo para evitar warning de variável não utilizada
 	_ = userRepo
+
+	<-signals
+	logger.Info("Shutting down")
+	shutdown(httpServer, grpcServer, logger)
+
+	// Só depois que as requisições terminaram os spans delas estão completos
+	flushCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Error("Error flushing traces", logging.Err(err))
+	}
+}
+
+// shutdown para os dois servidores ao mesmo tempo e espera as requisições e os RPCs em
+// andamento terminarem, até shutdownTimeout. Os streams WatchAuction não terminam sozinhos,
+// então o servidor gRPC é parado de vez quando o prazo acaba.
+func shutdown(httpServer *http.Server, grpcServer *grpc.Server, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			logger.Error("Error shutting down HTTP server", logging.Err(err))
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			logger.Warn("gRPC server did not stop in time, closing the remaining streams")
+			grpcServer.Stop()
+			<-stopped
+		}
+	}()
+
+	wg.Wait()
 }
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/auction-goexpert/configuration/config"
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/auction_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/audit_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/bid_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/exchange_rate_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/invoice_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/notification_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/offer_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/report_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/settlement_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/v2_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/wallet_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/controller/watchlist_controller"
+	"github.com/auction-goexpert/internal/infra/api/web/health"
+	"github.com/auction-goexpert/internal/infra/api/web/idempotency"
+	"github.com/auction-goexpert/internal/infra/api/web/middleware"
+	"github.com/auction-goexpert/internal/infra/api/web/openapi"
+	"github.com/auction-goexpert/internal/infra/api/web/ratelimit"
+	"github.com/auction-goexpert/internal/infra/audit"
+	"github.com/auction-goexpert/internal/infra/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+type controllers struct {
+	auction      *auction_controller.AuctionController
+	bid          *bid_controller.BidController
+	notification *notification_controller.NotificationController
+	watchlist    *watchlist_controller.WatchlistController
+	exchangeRate *exchange_rate_controller.ExchangeRateController
+	report       *report_controller.ReportController
+	auctionV2    *v2_controller.AuctionController
+	bidV2        *v2_controller.BidController
+	audit        *audit_controller.AuditController
+	settlement   *settlement_controller.SettlementController
+	offer        *offer_controller.OfferController
+	wallet       *wallet_controller.WalletController
+	invoice      *invoice_controller.InvoiceController
+	health       *health.Handler
+
+	adminAuth     gin.HandlerFunc
+	userAuth      gin.HandlerFunc
+	auditRecorder *audit.Recorder
+}
+
+// v1DeprecatedAt é quando a v2 foi publicada e a v1 passou a ser obsoleta
+var v1DeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+// registerRoutes registra todas as rotas da API. Rotas novas também devem ser
+// descritas em internal/infra/api/web/openapi/openapi.json.
+func registerRoutes(
+	router *gin.Engine,
+	ctrl controllers,
+	cfg *config.Config,
+	rateLimiter entity.RateLimiterInterface,
+	idempotencyRepo entity.IdempotencyRepositoryInterface,
+	logger *slog.Logger,
+) {
+	// Limites e idempotência valem para todas as versões: os buckets e as chaves de
+	// idempotência são por operação ("auction", "bid"), não por rota, e são os mesmos do gRPC
+	guards := createGuards{
+		auction: []gin.HandlerFunc{
+			ratelimit.Middleware(rateLimiter, "auction:ip", cfg.RateLimit.AuctionIP, ratelimit.ClientIP, logger),
+			ratelimit.Middleware(rateLimiter, "auction:user", cfg.RateLimit.AuctionUser, ratelimit.UserFromBody("seller_id"), logger),
+			idempotency.Middleware(idempotencyRepo, "auction", "seller_id", cfg.Idempotency.KeyTTL, logger),
+		},
+		bid: []gin.HandlerFunc{
+			ratelimit.Middleware(rateLimiter, "bid:ip", cfg.RateLimit.BidIP, ratelimit.ClientIP, logger),
+			ratelimit.Middleware(rateLimiter, "bid:user", cfg.RateLimit.BidUser, ratelimit.UserFromBody("user_id"), logger),
+			idempotency.Middleware(idempotencyRepo, "bid", "user_id", cfg.Idempotency.KeyTTL, logger),
+		},
+	}
+
+	// A v1 mantém o comportamento original e os caminhos sem versão são aliases dela
+	deprecation := middleware.Deprecation(v1DeprecatedAt, cfg.HTTP.V1Sunset, "/v2")
+	registerV1Routes(router.Group("/v1", deprecation), ctrl, guards)
+	registerV1Routes(router.Group("", deprecation), ctrl, guards)
+	registerV2Routes(router.Group("/v2"), ctrl, guards)
+
+	// Documentação
+	router.GET("/openapi.json", openapi.ServeSpec)
+	router.GET("/docs", openapi.ServeDocs)
+
+	// Saúde do processo, usada pelo orquestrador e pelo monitoramento
+	router.GET("/healthz", ctrl.health.Liveness)
+	router.GET("/readyz", ctrl.health.Readiness)
+	router.GET("/status", ctrl.health.Status)
+
+	// Métricas no formato do Prometheus
+	router.GET("/metrics", metrics.Handler())
+}
+
+// createGuards são os middlewares aplicados antes da criação de leilões e lances
+type createGuards struct {
+	auction []gin.HandlerFunc
+	bid     []gin.HandlerFunc
+}
+
+func withGuards(guards []gin.HandlerFunc, handler gin.HandlerFunc) []gin.HandlerFunc {
+	return append(append([]gin.HandlerFunc{}, guards...), handler)
+}
+
+func registerV1Routes(group *gin.RouterGroup, ctrl controllers, guards createGuards) {
+	// Rotas de leilão
+	group.POST("/auction", withGuards(guards.auction, ctrl.auction.CreateAuction)...)
+	group.GET("/auction/search", ctrl.auction.SearchAuctions)
+	group.GET("/auction/:auctionId", ctrl.auction.FindAuctionById)
+	group.GET("/auction", ctrl.auction.FindAuctions)
+
+	// Rotas de lance
+	group.POST("/bid", withGuards(guards.bid, ctrl.bid.CreateBid)...)
+	group.GET("/bid/auction/:auctionId", ctrl.bid.FindBidByAuctionId)
+	group.GET("/bid/auction/:auctionId/winner", ctrl.bid.FindWinningBidByAuctionId)
+
+	registerSharedRoutes(group, ctrl)
+}
+
+// registerV2Routes usa os DTOs da v2 em leilões e lances; as demais rotas são iguais às da v1
+func registerV2Routes(group *gin.RouterGroup, ctrl controllers, guards createGuards) {
+	group.POST("/auction", withGuards(guards.auction, ctrl.auctionV2.CreateAuction)...)
+	group.GET("/auction/search", ctrl.auctionV2.SearchAuctions)
+	group.GET("/auction/:auctionId", ctrl.auctionV2.FindAuctionById)
+	group.GET("/auction", ctrl.auctionV2.FindAuctions)
+
+	group.POST("/bid", withGuards(guards.bid, ctrl.bidV2.CreateBid)...)
+	group.GET("/bid/auction/:auctionId", ctrl.bidV2.FindBidByAuctionId)
+	group.GET("/bid/auction/:auctionId/winner", ctrl.bidV2.FindWinningBidByAuctionId)
+
+	registerSharedRoutes(group, ctrl)
+}
+
+func registerSharedRoutes(group *gin.RouterGroup, ctrl controllers) {
+	// Rotas do acerto entre vencedor e vendedor depois do encerramento. As ações exigem o
+	// token do usuário, que precisa ser o user_id informado no corpo.
+	group.GET("/auction/:auctionId/settlement", ctrl.settlement.FindSettlement)
+	group.POST("/auction/:auctionId/settlement/pay", ctrl.userAuth, ctrl.settlement.Pay)
+	group.POST("/auction/:auctionId/settlement/ship", ctrl.userAuth, ctrl.settlement.Ship)
+	group.POST("/auction/:auctionId/settlement/deliver", ctrl.userAuth, ctrl.settlement.ConfirmDelivery)
+	group.POST("/auction/:auctionId/settlement/dispute", ctrl.userAuth, ctrl.settlement.Dispute)
+
+	// Ofertas de segunda chance do vendedor aos demais licitantes
+	group.GET("/auction/:auctionId/runner-ups", ctrl.offer.FindRunnerUps)
+	group.GET("/auction/:auctionId/offers", ctrl.offer.FindOffers)
+	group.POST("/auction/:auctionId/offers", ctrl.offer.CreateOffers)
+	group.POST("/auction/:auctionId/offers/:offerId/accept", ctrl.offer.AcceptOffer)
+	group.POST("/auction/:auctionId/offers/:offerId/decline", ctrl.offer.DeclineOffer)
+
+	// Rotas de notificações do usuário
+	group.GET("/user/:userId/notifications", ctrl.notification.FindNotifications)
+	group.POST("/user/:userId/notifications/read", ctrl.notification.MarkNotificationsAsRead)
+	group.GET("/user/:userId/notification-preferences", ctrl.notification.FindPreference)
+	group.PUT("/user/:userId/notification-preferences", ctrl.notification.UpdatePreference)
+
+	// Rotas da carteira: saldos e extrato do livro-razão. Os depósitos ficam nas rotas
+	// administrativas, pois creditam saldo sem um pagamento verificado.
+	group.GET("/user/:userId/wallet", ctrl.wallet.FindWallet)
+	group.GET("/user/:userId/wallet/ledger", ctrl.wallet.FindLedger)
+
+	// Rotas de watchlist
+	group.GET("/user/:userId/watchlist", ctrl.watchlist.FindWatchlist)
+	group.POST("/user/:userId/watchlist/:auctionId", ctrl.watchlist.AddToWatchlist)
+	group.DELETE("/user/:userId/watchlist/:auctionId", ctrl.watchlist.RemoveFromWatchlist)
+
+	// Rotas administrativas de cotação, depósitos, auditoria, faturas e relatórios. Exigem o
+	// token de um operador, e as alterações feitas por elas ficam no log de auditoria com o
+	// nome dele.
+	admin := group.Group("/admin", ctrl.adminAuth, ctrl.auditRecorder.AdminActions())
+	admin.GET("/fx-rates", ctrl.exchangeRate.FindExchangeRates)
+	admin.POST("/fx-rates", ctrl.exchangeRate.CreateExchangeRate)
+	admin.POST("/wallets/:userId/deposits", ctrl.wallet.Deposit)
+	admin.GET("/audit/auction/:auctionId", ctrl.audit.FindAuctionAuditTrail)
+	admin.GET("/audit/admin", ctrl.audit.FindAdminAuditTrail)
+	admin.GET("/invoices/export", ctrl.invoice.ExportInvoices)
+	admin.GET("/invoices/:invoiceId", ctrl.invoice.FindInvoice)
+	admin.GET("/reports/sales", ctrl.report.SalesReport)
+}
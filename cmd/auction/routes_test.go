@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/auction-goexpert/configuration/config"
+	"github.com/auction-goexpert/internal/infra/api/web/openapi"
+	"github.com/auction-goexpert/internal/infra/api/web/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// unversioned são as rotas fora das versões da API
+var unversioned = map[string]bool{
+	"/openapi.json": true,
+	"/docs":         true,
+	"/healthz":      true,
+	"/readyz":       true,
+	"/status":       true,
+	"/metrics":      true,
+}
+
+func TestOpenAPIDescribesEveryRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	registerRoutes(router, controllers{}, &config.Config{}, ratelimit.NewMemoryStore(), nil, slog.Default())
+
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openapi.Spec, &spec); err != nil {
+		t.Fatalf("invalid openapi.json: %v", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		// Os caminhos sem versão são aliases da v1 e ficam documentados como /v1
+		path := route.Path
+		if !strings.HasPrefix(path, "/v1/") && !strings.HasPrefix(path, "/v2/") && !unversioned[path] {
+			path = "/v1" + path
+		}
+
+		operation := route.Method + " " + pathParam.ReplaceAllString(path, "{$1}")
+		registered[operation] = true
+		assert.True(t, documented[operation], "route %s is not described in openapi.json", operation)
+	}
+
+	for operation := range documented {
+		assert.True(t, registered[operation], "openapi.json describes %s, which is not registered", operation)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/auction-goexpert/configuration/config"
+	"github.com/auction-goexpert/configuration/database/mongodb"
+	"github.com/auction-goexpert/internal/infra/database/migration"
+	"github.com/auction-goexpert/internal/infra/logging"
+)
+
+func main() {
+	if len(os.Args) != 2 || (os.Args[1] != "up" && os.Args[1] != "status") {
+		fmt.Fprintln(os.Stderr, "usage: migrate <up|status>")
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	// Carrega e valida a configuração
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Fatal(slog.Default(), "Invalid configuration", err)
+	}
+
+	// Os logs vão para stderr para não se misturar à saída do status
+	logger := logging.New(cfg.Log, os.Stderr)
+
+	database, err := mongodb.NewMongoDBConnection(ctx, cfg.MongoDB, logger)
+	if err != nil {
+		logging.Fatal(logger, "Failed to connect to MongoDB", err)
+	}
+
+	runner := migration.NewRunner(database, migration.Migrations, logger)
+
+	switch os.Args[1] {
+	case "up":
+		applied, err := runner.Up(ctx)
+		if err != nil {
+			logging.Fatal(logger, "Failed to apply migrations", err)
+		}
+		logger.Info("Migrations applied", slog.Int("count", len(applied)))
+
+	case "status":
+		status, err := runner.Status(ctx)
+		if err != nil {
+			logging.Fatal(logger, "Failed to read migration status", err)
+		}
+
+		for _, item := range status {
+			applied := "pending"
+			if item.AppliedAt != nil {
+				applied = "applied at " + item.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%4d  %-50s %s\n", item.Version, item.Description, applied)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/auction-goexpert/configuration/config"
+	"github.com/auction-goexpert/configuration/database/mongodb"
+	"github.com/auction-goexpert/internal/infra/database/event_store"
+	"github.com/auction-goexpert/internal/infra/logging"
+)
+
+// replay reconstrói as coleções auctions e bids a partir do event store. Deve ser
+// executado com a API parada.
+func main() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	// Carrega e valida a configuração
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Fatal(slog.Default(), "Invalid configuration", err)
+	}
+
+	logger := logging.New(cfg.Log, os.Stderr)
+
+	database, err := mongodb.NewMongoDBConnection(ctx, cfg.MongoDB, logger)
+	if err != nil {
+		logging.Fatal(logger, "Failed to connect to MongoDB", err)
+	}
+
+	startedAt := time.Now()
+	count, err := event_store.NewAuctionEventStore(database, logger).Replay(ctx)
+	if err != nil {
+		logging.Fatal(logger, "Failed to replay auction events", err)
+	}
+
+	logger.Info("Projections rebuilt",
+		slog.Int("events", count), slog.String("duration", time.Since(startedAt).String()))
+}
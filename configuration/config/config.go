@@ -0,0 +1,217 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/joho/godotenv"
+)
+
+// Config reúne toda a configuração da aplicação. É carregada e validada uma única
+// vez na inicialização e repassada aos construtores que precisam dela.
+type Config struct {
+	HTTP         HTTPConfig
+	Admin        AdminConfig
+	User         UserConfig
+	GRPC         GRPCConfig
+	MongoDB      MongoDBConfig
+	Auction      AuctionConfig
+	Settlement   SettlementConfig
+	Notification NotificationConfig
+	FX           FXConfig
+	Billing      BillingConfig
+	Idempotency  IdempotencyConfig
+	RateLimit    RateLimitConfig
+	Health       HealthConfig
+	Tracing      TracingConfig
+	Log          LogConfig
+}
+
+type HTTPConfig struct {
+	Port int
+	// TrustedProxies são os IPs/CIDRs cujo X-Forwarded-For é aceito
+	TrustedProxies []string
+	// V1Sunset é a data de desligamento da v1, anunciada no cabeçalho Sunset
+	V1Sunset time.Time
+}
+
+type AdminConfig struct {
+	// Tokens associa cada token das rotas /admin ao operador que ele identifica no log de
+	// auditoria; sem tokens as rotas /admin recusam todas as requisições
+	Tokens map[string]string
+}
+
+type UserConfig struct {
+	// Tokens associa cada token ao usuário que ele autentica nas ações do acerto; sem
+	// tokens essas ações recusam todas as requisições
+	Tokens map[string]string
+}
+
+type GRPCConfig struct {
+	Port int
+	// WatchPollInterval é o intervalo em que os streams WatchAuction leem os eventos novos
+	WatchPollInterval time.Duration
+}
+
+type MongoDBConfig struct {
+	URI              string
+	Database         string
+	MigrateOnStartup bool
+}
+
+type AuctionConfig struct {
+	// Duration é o tempo entre a criação e o encerramento automático de um leilão
+	Duration time.Duration
+	// CheckInterval é o intervalo da verificação de leilões expirados
+	CheckInterval time.Duration
+	// ExtensionWindow adia o fim do leilão quando um lance chega a menos desse tempo do
+	// encerramento; zero desliga a prorrogação
+	ExtensionWindow time.Duration
+}
+
+type SettlementConfig struct {
+	// PaymentWindow é o prazo que o vencedor tem para pagar depois do encerramento
+	PaymentWindow time.Duration
+	// CheckInterval é o intervalo da verificação de pagamentos vencidos
+	CheckInterval time.Duration
+	// MaxAttempts limita as tentativas abertas automaticamente (o vencedor conta como a
+	// primeira); depois disso o vendedor decide com as ofertas de segunda chance
+	MaxAttempts int
+}
+
+type NotificationConfig struct {
+	DefaultLocale         string
+	ReminderCheckInterval time.Duration
+	SMTP                  SMTPConfig
+}
+
+// SMTPConfig configura o canal de email; sem Host o canal fica desligado
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type FXConfig struct {
+	// RatesFile é o arquivo JSON de cotações importado na inicialização (opcional)
+	RatesFile string
+}
+
+type BillingConfig struct {
+	// FeeScheduleFile é o arquivo JSON com a tabela de tarifas de cada moeda (opcional);
+	// as moedas fora do arquivo usam a tabela padrão
+	FeeScheduleFile string
+}
+
+type IdempotencyConfig struct {
+	KeyTTL time.Duration
+}
+
+type RateLimitConfig struct {
+	// Backend é "memory" (por réplica) ou "mongo" (compartilhado entre réplicas)
+	Backend     string
+	BidUser     entity.RateLimitPolicy
+	BidIP       entity.RateLimitPolicy
+	AuctionUser entity.RateLimitPolicy
+	AuctionIP   entity.RateLimitPolicy
+}
+
+type HealthConfig struct {
+	// CheckTimeout é o tempo máximo de cada verificação do /readyz e do /status
+	CheckTimeout time.Duration
+}
+
+type TracingConfig struct {
+	// Exporter é "none" (desligado), "stdout" (uso local) ou "otlp"
+	Exporter    string
+	ServiceName string
+	// OTLPEndpoint é o coletor OTLP/gRPC; com "http://" a conexão é feita sem TLS
+	OTLPEndpoint string
+	// SampleRatio é a fração dos traces iniciados aqui que são gravados (0 a 1)
+	SampleRatio float64
+}
+
+type LogConfig struct {
+	Level slog.Level
+	// Format é "json" (padrão, para coletores de log) ou "text" (leitura no terminal)
+	Format string
+}
+
+// ConfigFileEnv é a variável com o caminho do arquivo YAML opcional
+const ConfigFileEnv = "CONFIG_FILE"
+
+// Load carrega a configuração do .env (se existir), do arquivo YAML indicado em
+// CONFIG_FILE (se houver) e das variáveis de ambiente. Variáveis de ambiente têm
+// prioridade sobre o arquivo, que tem prioridade sobre os valores padrão.
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil {
+		// O logger da aplicação depende desta configuração, então aqui vale o padrão
+		slog.Info("No .env file found")
+	}
+
+	fileValues := map[string]string{}
+	if path := os.Getenv(ConfigFileEnv); path != "" {
+		var err error
+		fileValues, err = readFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return load(os.LookupEnv, fileValues)
+}
+
+// load monta a configuração a partir das fontes já lidas. Todos os problemas são
+// reunidos em um único erro, para que possam ser corrigidos de uma vez.
+func load(lookupEnv func(string) (string, bool), fileValues map[string]string) (*Config, error) {
+	cfg := &Config{}
+	var problems []string
+
+	for _, f := range fields(cfg) {
+		value, source := f.defaultValue, "default"
+		if fileValue, ok := fileValues[f.env]; ok && fileValue != "" {
+			value, source = fileValue, "file key "+f.key
+		}
+		if envValue, ok := lookupEnv(f.env); ok && envValue != "" {
+			value, source = envValue, "env"
+		}
+
+		if err := f.parse(strings.TrimSpace(value)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s (%s): %v", f.env, source, err))
+		}
+	}
+
+	problems = append(problems, cfg.validate()...)
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	return cfg, nil
+}
+
+// validate confere as regras que envolvem mais de um campo
+func (cfg *Config) validate() []string {
+	var problems []string
+
+	if cfg.HTTP.Port == cfg.GRPC.Port && cfg.HTTP.Port != 0 {
+		problems = append(problems, fmt.Sprintf("HTTP_PORT and GRPC_PORT must be different, both are %d", cfg.HTTP.Port))
+	}
+
+	if smtp := cfg.Notification.SMTP; smtp.Host != "" {
+		if smtp.Port == "" {
+			problems = append(problems, "SMTP_PORT: required when SMTP_HOST is set")
+		}
+		if smtp.From == "" {
+			problems = append(problems, "SMTP_FROM: required when SMTP_HOST is set")
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(values map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+var requiredEnv = map[string]string{
+	"MONGODB_URI":      "mongodb://localhost:27017",
+	"MONGODB_DATABASE": "auctions",
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := load(env(requiredEnv), nil)
+	require.Nil(t, err)
+
+	assert.Equal(t, 8080, cfg.HTTP.Port)
+	assert.Equal(t, 50051, cfg.GRPC.Port)
+	assert.Equal(t, time.Second, cfg.GRPC.WatchPollInterval)
+	assert.Equal(t, 5*time.Minute, cfg.Auction.Duration)
+	assert.Equal(t, 10*time.Second, cfg.Auction.CheckInterval)
+	assert.Zero(t, cfg.Auction.ExtensionWindow)
+	assert.Equal(t, 48*time.Hour, cfg.Settlement.PaymentWindow)
+	assert.Equal(t, 2, cfg.Settlement.MaxAttempts)
+	assert.Empty(t, cfg.Billing.FeeScheduleFile)
+	assert.Equal(t, 24*time.Hour, cfg.Idempotency.KeyTTL)
+	assert.True(t, cfg.MongoDB.MigrateOnStartup)
+	assert.Equal(t, "memory", cfg.RateLimit.Backend)
+	assert.Equal(t, entity.RateLimitPolicy{Capacity: 20, RefillEvery: 3 * time.Second}, cfg.RateLimit.BidUser)
+	assert.Equal(t, time.Date(2027, time.April, 18, 0, 0, 0, 0, time.UTC), cfg.HTTP.V1Sunset)
+	assert.Empty(t, cfg.HTTP.TrustedProxies)
+	assert.Empty(t, cfg.Admin.Tokens)
+	assert.Empty(t, cfg.User.Tokens)
+	assert.Equal(t, slog.LevelInfo, cfg.Log.Level)
+	assert.Equal(t, "json", cfg.Log.Format)
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"300", 300 * time.Second},
+		{"5m", 5 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"250ms", 250 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		duration, err := ParseDuration(tt.value)
+		assert.Nil(t, err, tt.value)
+		assert.Equal(t, tt.expected, duration, tt.value)
+	}
+
+	for _, value := range []string{"", "invalid", "0", "-5", "-1m"} {
+		_, err := ParseDuration(value)
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	_, err := load(env(map[string]string{
+		"MONGODB_URI":            "localhost",
+		"AUCTION_DURATION":       "invalid",
+		"AUCTION_CHECK_INTERVAL": "0",
+		"HTTP_PORT":              "70000",
+		"RATE_LIMIT_BACKEND":     "redis",
+		"SMTP_HOST":              "smtp.example.com",
+		"LOG_LEVEL":              "verbose",
+	}), nil)
+	require.NotNil(t, err)
+
+	for _, expected := range []string{
+		"MONGODB_URI (env): must start with mongodb://",
+		"MONGODB_DATABASE (default): required",
+		`AUCTION_DURATION (env): invalid duration "invalid"`,
+		"AUCTION_CHECK_INTERVAL (env): must be positive",
+		"HTTP_PORT (env): invalid port",
+		"RATE_LIMIT_BACKEND (env): invalid value",
+		"SMTP_FROM: required when SMTP_HOST is set",
+		`LOG_LEVEL (env): invalid level "verbose"`,
+	} {
+		assert.Contains(t, err.Error(), expected)
+	}
+}
+
+func TestLoadFromFileWithEnvOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.Nil(t, os.WriteFile(path, []byte(`
+http:
+  port: 9090
+  trusted_proxies: [10.0.0.0/8, 192.168.0.1]
+  v1_sunset: 2027-01-31
+mongodb:
+  uri: mongodb://mongo:27017
+  database: auctions
+auction:
+  duration: 10m
+  check_interval: 5
+  extension_window: 30s
+rate_limit:
+  bid_user: off
+`), 0o600))
+
+	fileValues, err := readFile(path)
+	require.Nil(t, err)
+
+	cfg, err := load(env(map[string]string{"AUCTION_DURATION": "120"}), fileValues)
+	require.Nil(t, err)
+
+	assert.Equal(t, 9090, cfg.HTTP.Port)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.0.1"}, cfg.HTTP.TrustedProxies)
+	assert.Equal(t, time.Date(2027, time.January, 31, 0, 0, 0, 0, time.UTC), cfg.HTTP.V1Sunset)
+	assert.Equal(t, "mongodb://mongo:27017", cfg.MongoDB.URI)
+	assert.Equal(t, 2*time.Minute, cfg.Auction.Duration)
+	assert.Equal(t, 5*time.Second, cfg.Auction.CheckInterval)
+	assert.Equal(t, 30*time.Second, cfg.Auction.ExtensionWindow)
+	assert.False(t, cfg.RateLimit.BidUser.Enabled())
+}
+
+func TestAdminTokens(t *testing.T) {
+	var tokens map[string]string
+	require.NoError(t, adminTokens(&tokens)("maria:0123456789abcdef, joao:fedcba9876543210"))
+	assert.Equal(t, map[string]string{"0123456789abcdef": "maria", "fedcba9876543210": "joao"}, tokens)
+
+	assert.Error(t, adminTokens(&tokens)("maria"))
+	assert.Error(t, adminTokens(&tokens)("maria:short"))
+	assert.Error(t, adminTokens(&tokens)("system:expiration-checker:0123456789abcdef"))
+	assert.Error(t, adminTokens(&tokens)("maria:0123456789abcdef,joao:0123456789abcdef"))
+}
+
+func TestReadFileRejectsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.Nil(t, os.WriteFile(path, []byte("auction:\n  durration: 5m\n"), 0o600))
+
+	_, err := readFile(path)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "auction.durration")
+}
@@ -0,0 +1,308 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+)
+
+// field liga uma variável de ambiente à chave equivalente do arquivo YAML e ao campo de Config
+type field struct {
+	env          string
+	key          string
+	defaultValue string
+	parse        func(value string) error
+}
+
+// fields lista todas as opções aceitas, com seus valores padrão
+func fields(cfg *Config) []field {
+	return []field{
+		{"HTTP_PORT", "http.port", "8080", port(&cfg.HTTP.Port)},
+		{"TRUSTED_PROXIES", "http.trusted_proxies", "", proxies(&cfg.HTTP.TrustedProxies)},
+		{"API_V1_SUNSET", "http.v1_sunset", "2027-04-18", date(&cfg.HTTP.V1Sunset)},
+		{"ADMIN_TOKENS", "admin.tokens", "", adminTokens(&cfg.Admin.Tokens)},
+		{"USER_TOKENS", "user.tokens", "", userTokens(&cfg.User.Tokens)},
+		{"GRPC_PORT", "grpc.port", "50051", port(&cfg.GRPC.Port)},
+		{"GRPC_WATCH_POLL_INTERVAL", "grpc.watch_poll_interval", "1s", duration(&cfg.GRPC.WatchPollInterval)},
+
+		{"MONGODB_URI", "mongodb.uri", "", mongoURI(&cfg.MongoDB.URI)},
+		{"MONGODB_DATABASE", "mongodb.database", "", required(&cfg.MongoDB.Database)},
+		{"MIGRATE_ON_STARTUP", "mongodb.migrate_on_startup", "true", boolean(&cfg.MongoDB.MigrateOnStartup)},
+
+		{"AUCTION_DURATION", "auction.duration", "5m", duration(&cfg.Auction.Duration)},
+		{"AUCTION_CHECK_INTERVAL", "auction.check_interval", "10s", duration(&cfg.Auction.CheckInterval)},
+		{"AUCTION_EXTENSION_WINDOW", "auction.extension_window", "", optionalDuration(&cfg.Auction.ExtensionWindow)},
+
+		{"SETTLEMENT_PAYMENT_WINDOW", "settlement.payment_window", "48h", duration(&cfg.Settlement.PaymentWindow)},
+		{"SETTLEMENT_CHECK_INTERVAL", "settlement.check_interval", "1m", duration(&cfg.Settlement.CheckInterval)},
+		{"SETTLEMENT_MAX_ATTEMPTS", "settlement.max_attempts", "2", positiveInt(&cfg.Settlement.MaxAttempts)},
+
+		{"NOTIFICATION_DEFAULT_LOCALE", "notification.default_locale", "pt-BR", required(&cfg.Notification.DefaultLocale)},
+		{"REMINDER_CHECK_INTERVAL", "notification.reminder_check_interval", "30s", duration(&cfg.Notification.ReminderCheckInterval)},
+		{"SMTP_HOST", "notification.smtp.host", "", optional(&cfg.Notification.SMTP.Host)},
+		{"SMTP_PORT", "notification.smtp.port", "587", smtpPort(&cfg.Notification.SMTP.Port)},
+		{"SMTP_USERNAME", "notification.smtp.username", "", optional(&cfg.Notification.SMTP.Username)},
+		{"SMTP_PASSWORD", "notification.smtp.password", "", optional(&cfg.Notification.SMTP.Password)},
+		{"SMTP_FROM", "notification.smtp.from", "", optional(&cfg.Notification.SMTP.From)},
+
+		{"FX_RATES_FILE", "fx.rates_file", "", optional(&cfg.FX.RatesFile)},
+
+		{"BILLING_FEE_SCHEDULE_FILE", "billing.fee_schedule_file", "", optional(&cfg.Billing.FeeScheduleFile)},
+
+		{"IDEMPOTENCY_KEY_TTL", "idempotency.key_ttl", "24h", duration(&cfg.Idempotency.KeyTTL)},
+
+		{"RATE_LIMIT_BACKEND", "rate_limit.backend", "memory", oneOf(&cfg.RateLimit.Backend, "memory", "mongo")},
+		{"RATE_LIMIT_BID_USER", "rate_limit.bid_user", "20/1m", policy(&cfg.RateLimit.BidUser)},
+		{"RATE_LIMIT_BID_IP", "rate_limit.bid_ip", "120/1m", policy(&cfg.RateLimit.BidIP)},
+		{"RATE_LIMIT_AUCTION_USER", "rate_limit.auction_user", "5/1m", policy(&cfg.RateLimit.AuctionUser)},
+		{"RATE_LIMIT_AUCTION_IP", "rate_limit.auction_ip", "30/1m", policy(&cfg.RateLimit.AuctionIP)},
+
+		{"HEALTH_CHECK_TIMEOUT", "health.check_timeout", "2s", duration(&cfg.Health.CheckTimeout)},
+
+		{"OTEL_TRACES_EXPORTER", "tracing.exporter", "none", oneOf(&cfg.Tracing.Exporter, "none", "stdout", "otlp")},
+		{"OTEL_SERVICE_NAME", "tracing.service_name", "auction-api", required(&cfg.Tracing.ServiceName)},
+		{"OTEL_EXPORTER_OTLP_ENDPOINT", "tracing.otlp_endpoint", "http://localhost:4317", required(&cfg.Tracing.OTLPEndpoint)},
+		{"OTEL_TRACES_SAMPLER_ARG", "tracing.sample_ratio", "1", ratio(&cfg.Tracing.SampleRatio)},
+
+		{"LOG_LEVEL", "log.level", "info", level(&cfg.Log.Level)},
+		{"LOG_FORMAT", "log.format", "json", oneOf(&cfg.Log.Format, "json", "text")},
+	}
+}
+
+// ParseDuration aceita tanto segundos ("300") quanto durações do Go ("5m", "1h30m").
+// A duração precisa ser positiva.
+func ParseDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds <= 0 {
+			return 0, fmt.Errorf("must be positive, got %q", value)
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, use seconds (300) or a Go duration (5m)", value)
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("must be positive, got %q", value)
+	}
+
+	return parsed, nil
+}
+
+func duration(target *time.Duration) func(string) error {
+	return func(value string) (err error) {
+		*target, err = ParseDuration(value)
+		return err
+	}
+}
+
+// optionalDuration aceita vazio, que desliga o recurso configurado
+func optionalDuration(target *time.Duration) func(string) error {
+	return func(value string) (err error) {
+		if value == "" {
+			*target = 0
+			return nil
+		}
+		*target, err = ParseDuration(value)
+		return err
+	}
+}
+
+func port(target *int) func(string) error {
+	return func(value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 65535 {
+			return fmt.Errorf("invalid port %q, expected a number between 1 and 65535", value)
+		}
+		*target = parsed
+		return nil
+	}
+}
+
+func positiveInt(target *int) func(string) error {
+	return func(value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return fmt.Errorf("invalid value %q, expected a positive integer", value)
+		}
+		*target = parsed
+		return nil
+	}
+}
+
+// smtpPort guarda a porta como texto, que é como o canal de email a recebe
+func smtpPort(target *string) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		var parsed int
+		if err := port(&parsed)(value); err != nil {
+			return err
+		}
+		*target = value
+		return nil
+	}
+}
+
+func boolean(target *bool) func(string) error {
+	return func(value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q, expected true or false", value)
+		}
+		*target = parsed
+		return nil
+	}
+}
+
+func date(target *time.Time) func(string) error {
+	return func(value string) error {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+		}
+		*target = parsed
+		return nil
+	}
+}
+
+func ratio(target *float64) func(string) error {
+	return func(value string) error {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			return fmt.Errorf("invalid ratio %q, expected a number between 0 and 1", value)
+		}
+		*target = parsed
+		return nil
+	}
+}
+
+func level(target *slog.Level) func(string) error {
+	return func(value string) error {
+		if err := target.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid level %q, expected debug, info, warn or error", value)
+		}
+		return nil
+	}
+}
+
+func optional(target *string) func(string) error {
+	return func(value string) error {
+		*target = value
+		return nil
+	}
+}
+
+func required(target *string) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return errors.New("required")
+		}
+		*target = value
+		return nil
+	}
+}
+
+func mongoURI(target *string) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return errors.New("required")
+		}
+		if !strings.HasPrefix(value, "mongodb://") && !strings.HasPrefix(value, "mongodb+srv://") {
+			return errors.New("must start with mongodb:// or mongodb+srv://")
+		}
+		*target = value
+		return nil
+	}
+}
+
+func oneOf(target *string, allowed ...string) func(string) error {
+	return func(value string) error {
+		for _, option := range allowed {
+			if value == option {
+				*target = value
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value %q, expected one of %s", value, strings.Join(allowed, ", "))
+	}
+}
+
+func policy(target *entity.RateLimitPolicy) func(string) error {
+	return func(value string) (err error) {
+		*target, err = entity.ParseRateLimitPolicy(value)
+		return err
+	}
+}
+
+// proxies aceita uma lista de IPs ou CIDRs separados por vírgula
+func proxies(target *[]string) func(string) error {
+	return func(value string) error {
+		var list []string
+		for _, proxy := range strings.Split(value, ",") {
+			proxy = strings.TrimSpace(proxy)
+			if proxy == "" {
+				continue
+			}
+			if net.ParseIP(proxy) == nil {
+				if _, _, err := net.ParseCIDR(proxy); err != nil {
+					return fmt.Errorf("invalid proxy %q, expected an IP or CIDR", proxy)
+				}
+			}
+			list = append(list, proxy)
+		}
+		*target = list
+		return nil
+	}
+}
+
+// minTokenLength evita tokens de operadores e usuários fáceis de adivinhar
+const minTokenLength = 16
+
+// adminTokens aceita pares operador:token separados por vírgula e guarda o operador de cada
+// token. Os nomes reservados aos autores do sistema (system, anonymous) não são aceitos.
+func adminTokens(target *map[string]string) func(string) error {
+	return actorTokens("admin", target)
+}
+
+// userTokens aceita pares usuário:token, com as mesmas regras de adminTokens
+func userTokens(target *map[string]string) func(string) error {
+	return actorTokens("user", target)
+}
+
+func actorTokens(kind string, target *map[string]string) func(string) error {
+	return func(value string) error {
+		tokens := make(map[string]string)
+		for _, pair := range strings.Split(value, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			actor, token, ok := strings.Cut(pair, ":")
+			actor, token = strings.TrimSpace(actor), strings.TrimSpace(token)
+			if !ok || actor == "" || token == "" {
+				return errors.New("invalid entry, expected actor:token")
+			}
+			if actor == entity.SystemActor || actor == entity.AnonymousActor || strings.HasPrefix(actor, entity.SystemActor+":") {
+				return fmt.Errorf("invalid %s actor %q, the name is reserved", kind, actor)
+			}
+			if len(token) < minTokenLength {
+				return fmt.Errorf("%s token for %q must have at least %d characters", kind, actor, minTokenLength)
+			}
+			if _, ok := tokens[token]; ok {
+				return fmt.Errorf("%s token for %q is already used by another actor", kind, actor)
+			}
+			tokens[token] = actor
+		}
+		*target = tokens
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readFile lê o arquivo YAML e devolve os valores indexados pelo nome da variável de
+// ambiente equivalente, para que arquivo e ambiente passem pela mesma validação.
+// Chaves desconhecidas são rejeitadas, para que erros de digitação não passem em silêncio.
+func readFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var document map[string]any
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	flat := make(map[string]string)
+	flatten("", document, flat)
+
+	envByKey := make(map[string]string)
+	for _, f := range fields(&Config{}) {
+		envByKey[f.key] = f.env
+	}
+
+	values := make(map[string]string, len(flat))
+	var unknown []string
+	for key, value := range flat {
+		env, ok := envByKey[key]
+		if !ok {
+			unknown = append(unknown, key)
+			continue
+		}
+		values[env] = value
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown key(s) in config file %s: %s", path, strings.Join(unknown, ", "))
+	}
+
+	return values, nil
+}
+
+// flatten transforma as seções aninhadas em chaves como "auction.duration"
+func flatten(prefix string, node map[string]any, out map[string]string) {
+	for name, value := range node {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		switch typed := value.(type) {
+		case map[string]any:
+			flatten(key, typed, out)
+		case []any:
+			items := make([]string, 0, len(typed))
+			for _, item := range typed {
+				items = append(items, scalar(item))
+			}
+			out[key] = strings.Join(items, ",")
+		case nil:
+			out[key] = ""
+		default:
+			out[key] = scalar(typed)
+		}
+	}
+}
+
+func scalar(value any) string {
+	if date, ok := value.(time.Time); ok {
+		return date.Format("2006-01-02")
+	}
+	return fmt.Sprint(value)
+}
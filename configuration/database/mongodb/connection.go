@@ -2,18 +2,20 @@ package mongodb
 
 import (
 	"context"
-	"log"
-	"os"
+	"log/slog"
 
+	"github.com/auction-goexpert/configuration/config"
+	"github.com/auction-goexpert/internal/infra/metrics"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
-func NewMongoDBConnection(ctx context.Context) (*mongo.Database, error) {
-	mongoURI := os.Getenv("MONGODB_URI")
-	mongoDatabase := os.Getenv("MONGODB_DATABASE")
+func NewMongoDBConnection(ctx context.Context, cfg config.MongoDBConfig, logger *slog.Logger) (*mongo.Database, error) {
+	// O monitor cria um span para cada comando enviado ao MongoDB e mede a latência do driver
+	opts := options.Client().ApplyURI(cfg.URI).SetMonitor(metrics.CommandMonitor(otelmongo.NewMonitor()))
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	client, err := mongo.Connect(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -22,7 +24,7 @@ func NewMongoDBConnection(ctx context.Context) (*mongo.Database, error) {
 		return nil, err
 	}
 
-	log.Println("Connected to MongoDB successfully")
+	logger.InfoContext(ctx, "Connected to MongoDB", slog.String("database", cfg.Database))
 
-	return client.Database(mongoDatabase), nil
+	return client.Database(cfg.Database), nil
 }
@@ -12,28 +12,62 @@ type AuctionStatus int
 const (
 	Active AuctionStatus = iota
 	Completed
+	Cancelled
 )
 
+// Auction é o leilão. ReservePrice é o preço mínimo de venda do vendedor, que não é
+// divulgado; zero significa sem preço de reserva. WinningBidId é o lance vencedor, definido
+// no encerramento; fica vazio sem lances ou com a reserva não alcançada.
 type Auction struct {
-	Id          string
-	ProductName string
-	Category    string
-	Description string
-	Condition   ProductCondition
-	Status      AuctionStatus
-	Timestamp   time.Time
-	ExpiresAt   time.Time
+	Id           string
+	ProductName  string
+	Category     string
+	Description  string
+	Condition    ProductCondition
+	Status       AuctionStatus
+	Currency     Currency
+	CurrentPrice Money
+	ReservePrice Money
+	SellerId     string
+	WinningBidId string
+	Timestamp    time.Time
+	ExpiresAt    time.Time
 }
 
+// AuctionEntityMongo grava os horários em milissegundos Unix
 type AuctionEntityMongo struct {
-	Id          string           `bson:"_id"`
-	ProductName string           `bson:"product_name"`
-	Category    string           `bson:"category"`
-	Description string           `bson:"description"`
-	Condition   ProductCondition `bson:"condition"`
-	Status      AuctionStatus    `bson:"status"`
-	Timestamp   int64            `bson:"timestamp"`
-	ExpiresAt   int64            `bson:"expires_at"`
+	Id           string           `bson:"_id"`
+	ProductName  string           `bson:"product_name"`
+	Category     string           `bson:"category"`
+	Description  string           `bson:"description"`
+	Condition    ProductCondition `bson:"condition"`
+	Status       AuctionStatus    `bson:"status"`
+	Currency     Currency         `bson:"currency"`
+	CurrentPrice int64            `bson:"current_price"`
+	ReservePrice int64            `bson:"reserve_price,omitempty"`
+	SellerId     string           `bson:"seller_id,omitempty"`
+	WinningBidId string           `bson:"winning_bid_id,omitempty"`
+	Timestamp    int64            `bson:"timestamp"`
+	ExpiresAt    int64            `bson:"expires_at"`
+	// Version é a maior versão do stream de eventos aplicada pela projeção
+	Version int64 `bson:"version"`
+	// ClosingPendingAt é o horário do encerramento enquanto os listeners dele não terminam
+	// com sucesso; o verificador de leilões expirados os chama de novo
+	ClosingPendingAt int64 `bson:"closing_pending_at,omitempty"`
+}
+
+// AuctionSearchFilter reúne os critérios da busca de leilões; campos vazios não filtram
+type AuctionSearchFilter struct {
+	Text         string
+	Status       *AuctionStatus
+	Categories   []string
+	Conditions   []ProductCondition
+	SellerId     string
+	Currency     *Currency
+	MinPrice     *Money
+	MaxPrice     *Money
+	EndingAfter  *time.Time
+	EndingBefore *time.Time
 }
 
 type ProductCondition int
@@ -47,21 +81,38 @@ const (
 type AuctionRepositoryInterface interface {
 	CreateAuction(ctx context.Context, auction *Auction) error
 	FindAuctionById(ctx context.Context, id string) (*Auction, error)
-	FindAuctions(ctx context.Context, status AuctionStatus, category, productName string) ([]Auction, error)
+	FindAuctions(ctx context.Context, status AuctionStatus, category, productName string, page PageRequest) ([]Auction, string, error)
+	SearchAuctions(ctx context.Context, filter AuctionSearchFilter, page PageRequest) ([]Auction, string, error)
 	UpdateAuctionStatus(ctx context.Context, id string, status AuctionStatus) error
 	FindExpiredAuctions(ctx context.Context) ([]Auction, error)
+	FindAuctionsByIds(ctx context.Context, ids []string) ([]Auction, error)
+	FindAuctionsEndingBetween(ctx context.Context, from, to time.Time) ([]Auction, error)
 }
 
+// AuctionClosedListener é chamado sempre que um leilão é encerrado. Se algum listener
+// retornar erro, todos são chamados de novo na verificação seguinte, então eles precisam
+// poder ser repetidos.
+type AuctionClosedListener func(ctx context.Context, auction Auction) error
+
+// AuctionCreatedListener é chamado depois que um leilão é gravado
+type AuctionCreatedListener func(ctx context.Context, auction Auction)
+
+// AuctionStatusChangedListener é chamado a cada mudança de status, inclusive nos
+// encerramentos automáticos, com o leilão antes e depois da mudança
+type AuctionStatusChangedListener func(ctx context.Context, before, after Auction)
+
 func CreateAuction(productName, category, description string, condition ProductCondition, duration time.Duration) (*Auction, error) {
 	auction := &Auction{
-		Id:          uuid.New().String(),
-		ProductName: productName,
-		Category:    category,
-		Description: description,
-		Condition:   condition,
-		Status:      Active,
-		Timestamp:   time.Now(),
-		ExpiresAt:   time.Now().Add(duration),
+		Id:           uuid.New().String(),
+		ProductName:  productName,
+		Category:     category,
+		Description:  description,
+		Condition:    condition,
+		Status:       Active,
+		Currency:     DefaultCurrency,
+		CurrentPrice: NewMoney(0, DefaultCurrency),
+		Timestamp:    time.Now().Truncate(time.Millisecond),
+		ExpiresAt:    time.Now().Add(duration),
 	}
 
 	return auction, nil
@@ -70,3 +121,14 @@ func CreateAuction(productName, category, description string, condition ProductC
 func (a *Auction) IsExpired() bool {
 	return time.Now().After(a.ExpiresAt)
 }
+
+// HasReserve indica se o vendedor definiu um preço de reserva
+func (a *Auction) HasReserve() bool {
+	return a.ReservePrice.Cents > 0
+}
+
+// ReserveMet indica se o preço atual alcança o preço de reserva. Sem reserva, qualquer lance
+// vence; com reserva não alcançada, o leilão termina sem vencedor.
+func (a *Auction) ReserveMet() bool {
+	return !a.HasReserve() || a.CurrentPrice.Cents >= a.ReservePrice.Cents
+}
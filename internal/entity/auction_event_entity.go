@@ -0,0 +1,321 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuctionEventType string
+
+const (
+	AuctionCreatedEvent   AuctionEventType = "AuctionCreated"
+	AuctionStartedEvent   AuctionEventType = "AuctionStarted"
+	BidPlacedEvent        AuctionEventType = "BidPlaced"
+	AuctionExtendedEvent  AuctionEventType = "AuctionExtended"
+	AuctionClosedEvent    AuctionEventType = "AuctionClosed"
+	AuctionCancelledEvent AuctionEventType = "AuctionCancelled"
+)
+
+// ErrConcurrencyConflict indica que outro comando gravou no stream do leilão depois que
+// ele foi carregado
+var ErrConcurrencyConflict = NewDomainError(ConflictError, "concurrency_conflict", "auction was modified concurrently, retry the request")
+
+// AuctionEvent é um fato do stream de um leilão. Version é a posição no stream, a partir
+// de 1, e Data é um dos payloads abaixo, de acordo com Type.
+type AuctionEvent struct {
+	Id         string
+	AuctionId  string
+	Version    int64
+	Type       AuctionEventType
+	OccurredAt time.Time
+	Data       any
+}
+
+// AuctionEventEntityMongo grava o payload como subdocumento; o índice único em
+// (auction_id, version) garante a concorrência otimista
+type AuctionEventEntityMongo struct {
+	Id         string           `bson:"_id"`
+	AuctionId  string           `bson:"auction_id"`
+	Version    int64            `bson:"version"`
+	Type       AuctionEventType `bson:"type"`
+	OccurredAt int64            `bson:"occurred_at"`
+	Data       any              `bson:"data"`
+}
+
+// AuctionCreatedData grava o preço de reserva em centavos na moeda do leilão; ele fica
+// ausente nos leilões sem reserva
+type AuctionCreatedData struct {
+	ProductName  string           `bson:"product_name"`
+	Category     string           `bson:"category"`
+	Description  string           `bson:"description"`
+	Condition    ProductCondition `bson:"condition"`
+	Currency     Currency         `bson:"currency"`
+	ReservePrice int64            `bson:"reserve_price,omitempty"`
+	SellerId     string           `bson:"seller_id,omitempty"`
+	ExpiresAt    time.Time        `bson:"expires_at"`
+}
+
+type AuctionStartedData struct{}
+
+type BidPlacedData struct {
+	BidId    string   `bson:"bid_id"`
+	UserId   string   `bson:"user_id"`
+	Amount   int64    `bson:"amount"`
+	Currency Currency `bson:"currency"`
+}
+
+type AuctionExtendedData struct {
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+type AuctionClosedData struct {
+	WinningBidId string `bson:"winning_bid_id,omitempty"`
+}
+
+type AuctionCancelledData struct {
+	Reason string `bson:"reason,omitempty"`
+}
+
+// NewAuctionEventData retorna um ponteiro para o payload vazio do tipo, onde o event store
+// decodifica o subdocumento gravado. AuctionEvent.Data guarda o payload por valor.
+func NewAuctionEventData(eventType AuctionEventType) (any, error) {
+	switch eventType {
+	case AuctionCreatedEvent:
+		return &AuctionCreatedData{}, nil
+	case AuctionStartedEvent:
+		return &AuctionStartedData{}, nil
+	case BidPlacedEvent:
+		return &BidPlacedData{}, nil
+	case AuctionExtendedEvent:
+		return &AuctionExtendedData{}, nil
+	case AuctionClosedEvent:
+		return &AuctionClosedData{}, nil
+	case AuctionCancelledEvent:
+		return &AuctionCancelledData{}, nil
+	}
+	return nil, fmt.Errorf("unknown auction event type %q", eventType)
+}
+
+// AuctionAggregate reconstrói o leilão a partir dos eventos e valida os comandos. Cada
+// comando aceito gera eventos em Changes, que ainda precisam ser gravados no event store
+// com Version (a versão carregada) como versão esperada.
+type AuctionAggregate struct {
+	Auction    Auction
+	HighestBid *Bid
+	Started    bool
+	Version    int64
+
+	changes []AuctionEvent
+}
+
+// LoadAuctionAggregate aplica os eventos gravados, em ordem
+func LoadAuctionAggregate(events []AuctionEvent) (*AuctionAggregate, error) {
+	aggregate := &AuctionAggregate{}
+	if err := aggregate.Apply(events); err != nil {
+		return nil, err
+	}
+	return aggregate, nil
+}
+
+// Apply aplica os eventos gravados depois da versão carregada, em ordem, como quem
+// acompanha o stream
+func (a *AuctionAggregate) Apply(events []AuctionEvent) error {
+	for _, event := range events {
+		if event.Version != a.Version+1 {
+			return fmt.Errorf("auction %s: expected event version %d, found %d", event.AuctionId, a.Version+1, event.Version)
+		}
+		if err := a.apply(event); err != nil {
+			return err
+		}
+		a.Version = event.Version
+	}
+	return nil
+}
+
+// Exists indica se o stream já tem o evento de criação
+func (a *AuctionAggregate) Exists() bool {
+	return a.Auction.Id != ""
+}
+
+// Changes retorna os eventos gerados pelos comandos desde o carregamento
+func (a *AuctionAggregate) Changes() []AuctionEvent {
+	return a.changes
+}
+
+// Create abre o leilão. Os leilões começam a aceitar lances na criação, então Created e
+// Started são gerados juntos.
+func (a *AuctionAggregate) Create(auction Auction) error {
+	if a.Exists() {
+		return fmt.Errorf("auction %s already exists", auction.Id)
+	}
+
+	a.Auction.Id = auction.Id
+	if err := a.record(AuctionCreatedEvent, auction.Timestamp, AuctionCreatedData{
+		ProductName:  auction.ProductName,
+		Category:     auction.Category,
+		Description:  auction.Description,
+		Condition:    auction.Condition,
+		Currency:     auction.Currency,
+		ReservePrice: auction.ReservePrice.Cents,
+		SellerId:     auction.SellerId,
+		ExpiresAt:    auction.ExpiresAt,
+	}); err != nil {
+		return err
+	}
+	return a.record(AuctionStartedEvent, auction.Timestamp, AuctionStartedData{})
+}
+
+// PlaceBid aceita o lance se o leilão estiver ativo e ele superar o maior lance. Sem moeda
+// informada, o lance assume a do leilão.
+func (a *AuctionAggregate) PlaceBid(bid *Bid) error {
+	if !a.Exists() {
+		return ErrAuctionNotFound
+	}
+	if !a.Started || a.Auction.Status != Active {
+		return ErrAuctionNotActive
+	}
+	if bid.Timestamp.After(a.Auction.ExpiresAt) {
+		return ErrAuctionExpired
+	}
+
+	if bid.Amount.Currency == "" {
+		bid.Amount.Currency = a.Auction.Currency
+	}
+	if bid.Amount.Currency != a.Auction.Currency {
+		return ErrCurrencyMismatch.WithMessage("bid currency %s does not match auction currency %s", bid.Amount.Currency, a.Auction.Currency)
+	}
+
+	// Em caso de empate vence o lance mais antigo, então o novo lance precisa ser maior
+	if a.HighestBid != nil && !bid.Amount.GreaterThan(a.HighestBid.Amount) {
+		return ErrBidTooLow.WithMessage("bid must be greater than the current highest bid of %s %s", a.HighestBid.Amount, a.HighestBid.Amount.Currency)
+	}
+
+	return a.record(BidPlacedEvent, bid.Timestamp, BidPlacedData{
+		BidId:    bid.Id,
+		UserId:   bid.UserId,
+		Amount:   bid.Amount.Cents,
+		Currency: bid.Amount.Currency,
+	})
+}
+
+// Extend adia o fim de um leilão ativo
+func (a *AuctionAggregate) Extend(expiresAt, at time.Time) error {
+	if err := a.requireActive(); err != nil {
+		return err
+	}
+	if !expiresAt.After(a.Auction.ExpiresAt) {
+		return fmt.Errorf("auction %s: new expiration must be later than %s", a.Auction.Id, a.Auction.ExpiresAt.Format(time.RFC3339))
+	}
+	return a.record(AuctionExtendedEvent, at, AuctionExtendedData{ExpiresAt: expiresAt.Truncate(time.Millisecond)})
+}
+
+// ExtendAfterBid adia o fim do leilão para window depois do lance quando ele chega a menos
+// de window do encerramento, dando tempo para os outros licitantes responderem. Com window
+// zero, ou com o fim ainda distante, nada muda.
+func (a *AuctionAggregate) ExtendAfterBid(bid *Bid, window time.Duration) error {
+	if window <= 0 {
+		return nil
+	}
+	expiresAt := bid.Timestamp.Add(window).Truncate(time.Millisecond)
+	if !expiresAt.After(a.Auction.ExpiresAt) {
+		return nil
+	}
+	return a.Extend(expiresAt, bid.Timestamp)
+}
+
+// Close encerra o leilão ativo; o maior lance, se houver e alcançar o preço de reserva, é o
+// vencedor
+func (a *AuctionAggregate) Close(at time.Time) error {
+	if err := a.requireActive(); err != nil {
+		return err
+	}
+
+	data := AuctionClosedData{}
+	if a.HighestBid != nil && a.Auction.ReserveMet() {
+		data.WinningBidId = a.HighestBid.Id
+	}
+	return a.record(AuctionClosedEvent, at, data)
+}
+
+// Cancel encerra o leilão ativo sem vencedor
+func (a *AuctionAggregate) Cancel(reason string) error {
+	if err := a.requireActive(); err != nil {
+		return err
+	}
+	return a.record(AuctionCancelledEvent, time.Now(), AuctionCancelledData{Reason: reason})
+}
+
+func (a *AuctionAggregate) requireActive() error {
+	if !a.Exists() {
+		return ErrAuctionNotFound
+	}
+	if a.Auction.Status != Active {
+		return ErrAuctionNotActive
+	}
+	return nil
+}
+
+// record gera o próximo evento do stream e o aplica ao estado
+func (a *AuctionAggregate) record(eventType AuctionEventType, occurredAt time.Time, data any) error {
+	event := AuctionEvent{
+		Id:         uuid.New().String(),
+		AuctionId:  a.Auction.Id,
+		Version:    a.Version + int64(len(a.changes)) + 1,
+		Type:       eventType,
+		OccurredAt: occurredAt.Truncate(time.Millisecond),
+		Data:       data,
+	}
+	if err := a.apply(event); err != nil {
+		return err
+	}
+	a.changes = append(a.changes, event)
+	return nil
+}
+
+// apply muda o estado a partir de um evento, sem validar regras: os eventos já aconteceram
+func (a *AuctionAggregate) apply(event AuctionEvent) error {
+	switch data := event.Data.(type) {
+	case AuctionCreatedData:
+		a.Auction = Auction{
+			Id:           event.AuctionId,
+			ProductName:  data.ProductName,
+			Category:     data.Category,
+			Description:  data.Description,
+			Condition:    data.Condition,
+			Status:       Active,
+			Currency:     data.Currency.OrDefault(),
+			CurrentPrice: NewMoney(0, data.Currency.OrDefault()),
+			ReservePrice: NewMoney(data.ReservePrice, data.Currency.OrDefault()),
+			SellerId:     data.SellerId,
+			Timestamp:    event.OccurredAt,
+			ExpiresAt:    data.ExpiresAt,
+		}
+	case AuctionStartedData:
+		a.Started = true
+	case BidPlacedData:
+		bid := Bid{
+			Id:        data.BidId,
+			UserId:    data.UserId,
+			AuctionId: event.AuctionId,
+			Amount:    NewMoney(data.Amount, data.Currency.OrDefault()),
+			Timestamp: event.OccurredAt,
+		}
+		// Lances importados de antes do event store podem não superar o maior lance
+		if a.HighestBid == nil || bid.Amount.GreaterThan(a.HighestBid.Amount) {
+			a.HighestBid = &bid
+			a.Auction.CurrentPrice = bid.Amount
+		}
+	case AuctionExtendedData:
+		a.Auction.ExpiresAt = data.ExpiresAt
+	case AuctionClosedData:
+		a.Auction.Status = Completed
+		a.Auction.WinningBidId = data.WinningBidId
+	case AuctionCancelledData:
+		a.Auction.Status = Cancelled
+	default:
+		return fmt.Errorf("auction %s: unknown payload for event %s", event.AuctionId, event.Type)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAggregate(t *testing.T) *AuctionAggregate {
+	auction, err := CreateAuction("Notebook", "Electronics", "Notebook with 16GB of memory", Used, time.Hour)
+	require.Nil(t, err)
+
+	aggregate := &AuctionAggregate{}
+	require.Nil(t, aggregate.Create(*auction))
+	return aggregate
+}
+
+func TestAuctionAggregateCommands(t *testing.T) {
+	aggregate := newTestAggregate(t)
+	assert.True(t, aggregate.Started)
+	assert.Equal(t, Active, aggregate.Auction.Status)
+
+	first, _ := CreateBid("user-1", aggregate.Auction.Id, NewMoney(1000, ""))
+	require.Nil(t, aggregate.PlaceBid(first))
+	assert.Equal(t, BRL, first.Amount.Currency)
+
+	tie, _ := CreateBid("user-2", aggregate.Auction.Id, NewMoney(1000, BRL))
+	assert.ErrorIs(t, aggregate.PlaceBid(tie), ErrBidTooLow)
+
+	other, _ := CreateBid("user-2", aggregate.Auction.Id, NewMoney(2000, USD))
+	assert.ErrorIs(t, aggregate.PlaceBid(other), ErrCurrencyMismatch)
+
+	require.Nil(t, aggregate.Close(time.Now()))
+	assert.Equal(t, Completed, aggregate.Auction.Status)
+
+	late, _ := CreateBid("user-2", aggregate.Auction.Id, NewMoney(5000, BRL))
+	assert.ErrorIs(t, aggregate.PlaceBid(late), ErrAuctionNotActive)
+	assert.ErrorIs(t, aggregate.Cancel("duplicated"), ErrAuctionNotActive)
+
+	var types []AuctionEventType
+	for i, event := range aggregate.Changes() {
+		assert.Equal(t, int64(i+1), event.Version)
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []AuctionEventType{AuctionCreatedEvent, AuctionStartedEvent, BidPlacedEvent, AuctionClosedEvent}, types)
+	assert.Equal(t, first.Id, aggregate.Changes()[3].Data.(AuctionClosedData).WinningBidId)
+	assert.Equal(t, first.Id, aggregate.Auction.WinningBidId)
+}
+
+func TestAuctionAggregateClosesWithoutWinnerBelowReserve(t *testing.T) {
+	auction, err := CreateAuction("Notebook", "Electronics", "Notebook with 16GB of memory", Used, time.Hour)
+	require.Nil(t, err)
+	auction.ReservePrice = NewMoney(5000, BRL)
+
+	aggregate := &AuctionAggregate{}
+	require.Nil(t, aggregate.Create(*auction))
+
+	bid, _ := CreateBid("user-1", auction.Id, NewMoney(4000, BRL))
+	require.Nil(t, aggregate.PlaceBid(bid))
+	require.Nil(t, aggregate.Close(time.Now()))
+	assert.False(t, aggregate.Auction.ReserveMet())
+	assert.Empty(t, aggregate.Changes()[3].Data.(AuctionClosedData).WinningBidId)
+	assert.Empty(t, aggregate.Auction.WinningBidId)
+
+	// A reserva volta na leitura do stream
+	loaded, err := LoadAuctionAggregate(aggregate.Changes())
+	require.Nil(t, err)
+	assert.Equal(t, NewMoney(5000, BRL), loaded.Auction.ReservePrice)
+	assert.False(t, loaded.Auction.ReserveMet())
+}
+
+func TestAuctionAggregateRejectsExpiredBid(t *testing.T) {
+	aggregate := newTestAggregate(t)
+
+	bid, _ := CreateBid("user-1", aggregate.Auction.Id, NewMoney(1000, BRL))
+	bid.Timestamp = aggregate.Auction.ExpiresAt.Add(time.Millisecond)
+	assert.ErrorIs(t, aggregate.PlaceBid(bid), ErrAuctionExpired)
+
+	require.Nil(t, aggregate.Extend(aggregate.Auction.ExpiresAt.Add(time.Minute), time.Now()))
+	assert.Nil(t, aggregate.PlaceBid(bid))
+}
+
+func TestAuctionAggregateExtendsAfterLateBid(t *testing.T) {
+	aggregate := newTestAggregate(t)
+	expiresAt := aggregate.Auction.ExpiresAt
+
+	early, _ := CreateBid("user-1", aggregate.Auction.Id, NewMoney(1000, BRL))
+	early.Timestamp = expiresAt.Add(-time.Hour)
+	require.Nil(t, aggregate.PlaceBid(early))
+	require.Nil(t, aggregate.ExtendAfterBid(early, time.Minute))
+	assert.Equal(t, expiresAt, aggregate.Auction.ExpiresAt)
+
+	late, _ := CreateBid("user-2", aggregate.Auction.Id, NewMoney(2000, BRL))
+	late.Timestamp = expiresAt.Add(-10 * time.Second)
+	require.Nil(t, aggregate.PlaceBid(late))
+	require.Nil(t, aggregate.ExtendAfterBid(late, 0))
+	assert.Equal(t, expiresAt, aggregate.Auction.ExpiresAt)
+
+	require.Nil(t, aggregate.ExtendAfterBid(late, time.Minute))
+	assert.Equal(t, late.Timestamp.Add(time.Minute).Truncate(time.Millisecond), aggregate.Auction.ExpiresAt)
+
+	changes := aggregate.Changes()
+	last := changes[len(changes)-1]
+	assert.Equal(t, AuctionExtendedEvent, last.Type)
+	assert.Equal(t, aggregate.Auction.ExpiresAt, last.Data.(AuctionExtendedData).ExpiresAt)
+}
+
+func TestLoadAuctionAggregateReplaysEvents(t *testing.T) {
+	aggregate := newTestAggregate(t)
+	bid, _ := CreateBid("user-1", aggregate.Auction.Id, NewMoney(1500, BRL))
+	require.Nil(t, aggregate.PlaceBid(bid))
+	require.Nil(t, aggregate.Cancel("seller withdrew the item"))
+
+	loaded, err := LoadAuctionAggregate(aggregate.Changes())
+	require.Nil(t, err)
+	assert.Equal(t, int64(4), loaded.Version)
+	assert.Empty(t, loaded.Changes())
+	assert.Equal(t, Cancelled, loaded.Auction.Status)
+	assert.Equal(t, NewMoney(1500, BRL), loaded.Auction.CurrentPrice)
+	assert.Equal(t, bid.Id, loaded.HighestBid.Id)
+	assert.Equal(t, aggregate.Auction.ExpiresAt, loaded.Auction.ExpiresAt)
+
+	// Um stream com lacuna de versão não é carregado
+	events := aggregate.Changes()
+	_, err = LoadAuctionAggregate([]AuctionEvent{events[0], events[2]})
+	assert.NotNil(t, err)
+}
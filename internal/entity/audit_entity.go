@@ -0,0 +1,310 @@
+package entity
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditAuctionCreated       AuditAction = "auction.created"
+	AuditAuctionStatusChanged AuditAction = "auction.status_changed"
+	AuditBidAccepted          AuditAction = "bid.accepted"
+	AuditBidRejected          AuditAction = "bid.rejected"
+	AuditAdminAction          AuditAction = "admin.action"
+)
+
+const (
+	// AuditAdminStream é a cadeia das ações administrativas; as demais usam o id do leilão
+	AuditAdminStream = "admin"
+
+	// SystemActor é o autor das mudanças sem usuário identificado
+	SystemActor = "system"
+	// ExpirationCheckerActor é o autor dos encerramentos automáticos
+	ExpirationCheckerActor = "system:expiration-checker"
+	// AnonymousActor é o autor de requisições que não informam usuário
+	AnonymousActor = "anonymous"
+)
+
+// AuditEntry é um registro imutável do log de auditoria. Cada stream (um leilão, ou as
+// ações administrativas) forma uma cadeia: o hash de cada registro cobre o hash do
+// anterior, então alterar ou remover um registro quebra a verificação dos seguintes.
+type AuditEntry struct {
+	Id           string
+	Stream       string
+	Sequence     int64
+	Action       AuditAction
+	Actor        string
+	Timestamp    time.Time
+	Before       json.RawMessage
+	After        json.RawMessage
+	Reason       string
+	PreviousHash string
+	Hash         string
+}
+
+// AuditEntryEntityMongo guarda os snapshots como texto, para que os bytes cobertos
+// pelo hash sejam exatamente os lidos de volta
+type AuditEntryEntityMongo struct {
+	Id           string      `bson:"_id"`
+	Stream       string      `bson:"stream"`
+	Sequence     int64       `bson:"sequence"`
+	Action       AuditAction `bson:"action"`
+	Actor        string      `bson:"actor"`
+	Timestamp    int64       `bson:"timestamp"`
+	Before       string      `bson:"before,omitempty"`
+	After        string      `bson:"after,omitempty"`
+	Reason       string      `bson:"reason,omitempty"`
+	PreviousHash string      `bson:"previous_hash"`
+	Hash         string      `bson:"hash"`
+}
+
+// AuditHead é a âncora da cadeia de um stream: a sequência e o hash do último registro
+// encadeado, guardados fora de audit_log. Uma cadeia que termina antes da âncora teve
+// registros removidos do fim, o que o encadeamento sozinho não denuncia.
+type AuditHead struct {
+	Stream   string
+	Sequence int64
+	Hash     string
+}
+
+type AuditHeadEntityMongo struct {
+	Stream    string `bson:"_id"`
+	Sequence  int64  `bson:"sequence"`
+	Hash      string `bson:"hash"`
+	UpdatedAt int64  `bson:"updated_at"`
+}
+
+type AuditRepositoryInterface interface {
+	// Append encadeia o registro ao último do stream, o grava e avança a âncora. Pode ser
+	// repetido: um registro já encadeado não é gravado de novo.
+	Append(ctx context.Context, entry AuditEntry) (*AuditEntry, error)
+	// FindAuditTrail retorna todos os registros do stream, em ordem
+	FindAuditTrail(ctx context.Context, stream string) ([]AuditEntry, error)
+	// FindAuditHead retorna a âncora do stream, ou nil se nenhum registro foi ancorado
+	FindAuditHead(ctx context.Context, stream string) (*AuditHead, error)
+	// EnqueuePending guarda o registro que não pôde ser encadeado, para nova tentativa
+	EnqueuePending(ctx context.Context, entry AuditEntry) error
+	// FindPendingEntries retorna os registros à espera de encadeamento, dos mais antigos
+	// para os mais recentes
+	FindPendingEntries(ctx context.Context) ([]AuditEntry, error)
+	// RemovePending tira o registro já encadeado da espera
+	RemovePending(ctx context.Context, id string) error
+}
+
+// AuditChainVerification é o resultado da verificação de uma cadeia. BrokenAt é a
+// sequência do primeiro registro inválido.
+type AuditChainVerification struct {
+	Valid    bool
+	Entries  int
+	BrokenAt int64
+	Problem  string
+}
+
+// NewAuditEntry cria um registro ainda não encadeado. Os snapshots são compactados para
+// que o hash não dependa da formatação.
+func NewAuditEntry(stream string, action AuditAction, actor string, before, after json.RawMessage, reason string) (AuditEntry, error) {
+	if actor == "" {
+		actor = SystemActor
+	}
+
+	entry := AuditEntry{
+		Id:        uuid.New().String(),
+		Stream:    stream,
+		Action:    action,
+		Actor:     actor,
+		Timestamp: time.Now().Truncate(time.Millisecond),
+		Reason:    reason,
+	}
+
+	var err error
+	if entry.Before, err = compactJSON(before); err != nil {
+		return AuditEntry{}, err
+	}
+	if entry.After, err = compactJSON(after); err != nil {
+		return AuditEntry{}, err
+	}
+
+	return entry, nil
+}
+
+// Chain liga o registro ao anterior do mesmo stream (nil para o primeiro) e calcula o hash
+func (e *AuditEntry) Chain(previous *AuditEntry) {
+	e.Sequence = 1
+	e.PreviousHash = ""
+	if previous != nil {
+		e.Sequence = previous.Sequence + 1
+		e.PreviousHash = previous.Hash
+	}
+	e.Hash = e.ComputeHash()
+}
+
+// ComputeHash calcula o SHA-256 de todos os campos do registro, exceto o próprio hash
+func (e AuditEntry) ComputeHash() string {
+	content, _ := json.Marshal(struct {
+		Id           string      `json:"id"`
+		Stream       string      `json:"stream"`
+		Sequence     int64       `json:"sequence"`
+		Action       AuditAction `json:"action"`
+		Actor        string      `json:"actor"`
+		Timestamp    int64       `json:"timestamp"`
+		Before       string      `json:"before"`
+		After        string      `json:"after"`
+		Reason       string      `json:"reason"`
+		PreviousHash string      `json:"previous_hash"`
+	}{e.Id, e.Stream, e.Sequence, e.Action, e.Actor, e.Timestamp.UnixMilli(), string(e.Before), string(e.After), e.Reason, e.PreviousHash})
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyAuditChain confere, em ordem, a sequência, o encadeamento e o hash de cada registro
+// e, com a âncora do stream, que a cadeia chega até ela
+func VerifyAuditChain(entries []AuditEntry, head *AuditHead) AuditChainVerification {
+	verification := AuditChainVerification{Valid: true, Entries: len(entries)}
+
+	var previous *AuditEntry
+	for i := range entries {
+		entry := entries[i]
+
+		expectedSequence, expectedPrevious := int64(1), ""
+		if previous != nil {
+			expectedSequence, expectedPrevious = previous.Sequence+1, previous.Hash
+		}
+
+		problem := ""
+		switch {
+		case entry.Sequence != expectedSequence:
+			problem = fmt.Sprintf("expected sequence %d, found %d", expectedSequence, entry.Sequence)
+		case entry.PreviousHash != expectedPrevious:
+			problem = "previous_hash does not match the hash of the previous entry"
+		case entry.Hash != entry.ComputeHash():
+			problem = "hash does not match the entry content"
+		}
+
+		if problem != "" {
+			verification.Valid = false
+			verification.BrokenAt = entry.Sequence
+			verification.Problem = problem
+			return verification
+		}
+
+		previous = &entries[i]
+	}
+
+	if head == nil {
+		return verification
+	}
+
+	// A cadeia é contínua a partir da sequência 1, então o registro ancorado é o de índice
+	// Sequence-1
+	last := int64(len(entries))
+	switch {
+	case last < head.Sequence:
+		verification.Valid = false
+		verification.BrokenAt = last + 1
+		verification.Problem = fmt.Sprintf("chain ends at sequence %d, before the anchored head at sequence %d", last, head.Sequence)
+	case head.Sequence > 0 && entries[head.Sequence-1].Hash != head.Hash:
+		verification.Valid = false
+		verification.BrokenAt = head.Sequence
+		verification.Problem = "hash does not match the anchored head"
+	}
+	return verification
+}
+
+type actorKey struct{}
+
+// WithActor guarda no contexto quem está executando a ação, para o log de auditoria
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext retorna o autor guardado por WithActor, ou SystemActor
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+// AuctionSnapshot é o estado do leilão guardado nos registros de auditoria
+func AuctionSnapshot(auction Auction) json.RawMessage {
+	snapshot, _ := json.Marshal(struct {
+		Id           string `json:"id"`
+		ProductName  string `json:"product_name"`
+		Category     string `json:"category"`
+		Status       string `json:"status"`
+		SellerId     string `json:"seller_id,omitempty"`
+		Currency     string `json:"currency"`
+		CurrentPrice int64  `json:"current_price_cents"`
+		ExpiresAt    string `json:"expires_at"`
+	}{
+		Id:           auction.Id,
+		ProductName:  auction.ProductName,
+		Category:     auction.Category,
+		Status:       auctionStatusName(auction.Status),
+		SellerId:     auction.SellerId,
+		Currency:     string(auction.Currency),
+		CurrentPrice: auction.CurrentPrice.Cents,
+		ExpiresAt:    auction.ExpiresAt.UTC().Format(time.RFC3339Nano),
+	})
+	return snapshot
+}
+
+// BidSnapshot é o lance guardado nos registros de auditoria
+func BidSnapshot(bid Bid) json.RawMessage {
+	snapshot, _ := json.Marshal(struct {
+		Id        string `json:"id,omitempty"`
+		UserId    string `json:"user_id"`
+		AuctionId string `json:"auction_id"`
+		Amount    int64  `json:"amount_cents"`
+		Currency  string `json:"currency,omitempty"`
+		Timestamp string `json:"timestamp,omitempty"`
+	}{
+		Id:        bid.Id,
+		UserId:    bid.UserId,
+		AuctionId: bid.AuctionId,
+		Amount:    bid.Amount.Cents,
+		Currency:  string(bid.Amount.Currency),
+		Timestamp: formatOptionalTime(bid.Timestamp),
+	})
+	return snapshot
+}
+
+func auctionStatusName(status AuctionStatus) string {
+	switch status {
+	case Active:
+		return "active"
+	case Completed:
+		return "completed"
+	case Cancelled:
+		return "cancelled"
+	}
+	return fmt.Sprintf("%d", status)
+}
+
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func compactJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, raw); err != nil {
+		return nil, fmt.Errorf("invalid audit snapshot: %w", err)
+	}
+	return compacted.Bytes(), nil
+}
@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildAuditChain(t *testing.T, size int) []AuditEntry {
+	var entries []AuditEntry
+	for i := 0; i < size; i++ {
+		entry, err := NewAuditEntry("auction-1", AuditBidAccepted, "user-1", nil,
+			json.RawMessage(`{ "amount_cents": 100 }`), "")
+		require.Nil(t, err)
+
+		var previous *AuditEntry
+		if len(entries) > 0 {
+			previous = &entries[len(entries)-1]
+		}
+		entry.Chain(previous)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	entries := buildAuditChain(t, 3)
+	assert.Equal(t, `{"amount_cents":100}`, string(entries[0].After))
+	assert.Equal(t, entries[0].Hash, entries[1].PreviousHash)
+
+	verification := VerifyAuditChain(entries, nil)
+	assert.True(t, verification.Valid)
+	assert.Equal(t, 3, verification.Entries)
+
+	assert.True(t, VerifyAuditChain(nil, nil).Valid)
+}
+
+func TestVerifyAuditChainDetectsTampering(t *testing.T) {
+	entries := buildAuditChain(t, 3)
+	entries[1].After = json.RawMessage(`{"amount_cents":1}`)
+
+	verification := VerifyAuditChain(entries, nil)
+	assert.False(t, verification.Valid)
+	assert.Equal(t, int64(2), verification.BrokenAt)
+	assert.Equal(t, "hash does not match the entry content", verification.Problem)
+
+	// Recalcular o hash do registro alterado quebra o encadeamento do seguinte
+	entries[1].Hash = entries[1].ComputeHash()
+	verification = VerifyAuditChain(entries, nil)
+	assert.False(t, verification.Valid)
+	assert.Equal(t, int64(3), verification.BrokenAt)
+
+	// Remover um registro quebra a sequência
+	entries = buildAuditChain(t, 3)
+	verification = VerifyAuditChain([]AuditEntry{entries[0], entries[2]}, nil)
+	assert.False(t, verification.Valid)
+	assert.Equal(t, int64(3), verification.BrokenAt)
+}
+
+func TestVerifyAuditChainDetectsTruncation(t *testing.T) {
+	entries := buildAuditChain(t, 3)
+	head := &AuditHead{Stream: "auction-1", Sequence: 3, Hash: entries[2].Hash}
+	assert.True(t, VerifyAuditChain(entries, head).Valid)
+
+	// Uma âncora atrasada (a gravação dela falhou) não invalida a cadeia
+	assert.True(t, VerifyAuditChain(entries, &AuditHead{Stream: "auction-1", Sequence: 2, Hash: entries[1].Hash}).Valid)
+
+	// Remover os últimos registros mantém o encadeamento, mas não chega à âncora
+	verification := VerifyAuditChain(entries[:2], head)
+	assert.False(t, verification.Valid)
+	assert.Equal(t, int64(3), verification.BrokenAt)
+	assert.Equal(t, "chain ends at sequence 2, before the anchored head at sequence 3", verification.Problem)
+
+	verification = VerifyAuditChain(nil, head)
+	assert.False(t, verification.Valid)
+	assert.Equal(t, int64(1), verification.BrokenAt)
+
+	// Reescrever a cadeia inteira a partir de um registro muda o hash ancorado
+	rewritten := buildAuditChain(t, 3)
+	verification = VerifyAuditChain(rewritten, head)
+	assert.False(t, verification.Valid)
+	assert.Equal(t, "hash does not match the anchored head", verification.Problem)
+}
@@ -11,31 +11,59 @@ type Bid struct {
 	Id        string
 	UserId    string
 	AuctionId string
-	Amount    float64
+	Amount    Money
 	Timestamp time.Time
 }
 
+// BidEntityMongo grava o valor em centavos e o horário em milissegundos Unix
 type BidEntityMongo struct {
-	Id        string  `bson:"_id"`
-	UserId    string  `bson:"user_id"`
-	AuctionId string  `bson:"auction_id"`
-	Amount    float64 `bson:"amount"`
-	Timestamp int64   `bson:"timestamp"`
+	Id        string   `bson:"_id"`
+	UserId    string   `bson:"user_id"`
+	AuctionId string   `bson:"auction_id"`
+	Amount    int64    `bson:"amount"`
+	Currency  Currency `bson:"currency"`
+	Timestamp int64    `bson:"timestamp"`
 }
 
 type BidRepositoryInterface interface {
 	CreateBid(ctx context.Context, bid *Bid) error
-	FindBidByAuctionId(ctx context.Context, auctionId string) ([]Bid, error)
+	FindBidByAuctionId(ctx context.Context, auctionId string, page PageRequest) ([]Bid, string, error)
 	FindWinningBidByAuctionId(ctx context.Context, auctionId string) (*Bid, error)
+	FindNextHighestBid(ctx context.Context, auctionId string, excludedUserIds []string) (*Bid, error)
+	FindHighestBidsByAuctionIds(ctx context.Context, auctionIds []string) (map[string]Bid, error)
 }
 
-func CreateBid(userId, auctionId string, amount float64) (*Bid, error) {
+// BidPlacedListener é chamado quando um lance passa a ser o maior do leilão.
+// previousHighest é nil quando não havia lance anterior.
+type BidPlacedListener func(ctx context.Context, bid Bid, previousHighest *Bid)
+
+// BidRejection descreve um lance recusado; Reason é o código do erro (por exemplo "bid_too_low")
+type BidRejection struct {
+	AuctionId string
+	UserId    string
+	Amount    Money
+	Reason    string
+}
+
+// BidRejectedListener é chamado quando um lance é recusado
+type BidRejectedListener func(ctx context.Context, rejection BidRejection)
+
+// Money retorna o valor do lance gravado em centavos; documentos antigos sem moeda usam a padrão
+func (b BidEntityMongo) Money() Money {
+	return NewMoney(b.Amount, b.Currency.OrDefault())
+}
+
+func CreateBid(userId, auctionId string, amount Money) (*Bid, error) {
+	if !amount.IsPositive() {
+		return nil, ErrInvalidBidAmount
+	}
+
 	bid := &Bid{
 		Id:        uuid.New().String(),
 		UserId:    userId,
 		AuctionId: auctionId,
 		Amount:    amount,
-		Timestamp: time.Now(),
+		Timestamp: time.Now().Truncate(time.Millisecond),
 	}
 
 	return bid, nil
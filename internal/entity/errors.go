@@ -0,0 +1,54 @@
+package entity
+
+import "fmt"
+
+// ErrorKind classifica os erros de domínio; a camada web traduz cada tipo em um status HTTP
+type ErrorKind string
+
+const (
+	InvalidInputError      ErrorKind = "invalid_input"
+	NotFoundError          ErrorKind = "not_found"
+	AuctionClosedError     ErrorKind = "auction_closed"
+	BidTooLowError         ErrorKind = "bid_too_low"
+	InsufficientFundsError ErrorKind = "insufficient_funds"
+	UnauthorizedError      ErrorKind = "unauthorized"
+	ForbiddenError         ErrorKind = "forbidden"
+	ConflictError          ErrorKind = "conflict"
+)
+
+// DomainError é um erro de regra de negócio com um código estável, exposto aos clientes da API
+type DomainError struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func NewDomainError(kind ErrorKind, code, message string) *DomainError {
+	return &DomainError{Kind: kind, Code: code, Message: message}
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+// Is compara pelo código, para que errors.Is reconheça o erro mesmo com outra mensagem
+func (e *DomainError) Is(target error) bool {
+	other, ok := target.(*DomainError)
+	return ok && other.Code == e.Code
+}
+
+// WithMessage retorna uma cópia do erro com uma mensagem mais específica
+func (e *DomainError) WithMessage(format string, args ...any) *DomainError {
+	return &DomainError{Kind: e.Kind, Code: e.Code, Message: fmt.Sprintf(format, args...)}
+}
+
+var (
+	ErrAuctionNotFound  = NewDomainError(NotFoundError, "auction_not_found", "auction not found")
+	ErrAuctionNotActive = NewDomainError(AuctionClosedError, "auction_not_active", "auction is not active")
+	ErrAuctionExpired   = NewDomainError(AuctionClosedError, "auction_expired", "auction has expired")
+	ErrInvalidBidAmount = NewDomainError(InvalidInputError, "invalid_bid_amount", "amount must be greater than zero")
+	ErrBidTooLow        = NewDomainError(BidTooLowError, "bid_too_low", "bid must be greater than the current highest bid")
+	ErrCurrencyMismatch = NewDomainError(InvalidInputError, "currency_mismatch", "bid currency does not match auction currency")
+
+	ErrInvalidReservePrice = NewDomainError(InvalidInputError, "invalid_reserve_price", "reserve price cannot be negative")
+)
@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateDecimalPlaces é a precisão das cotações; a taxa é guardada como inteiro escalado por RateScale
+const (
+	RateDecimalPlaces = 6
+	RateScale         = 1_000_000
+)
+
+var (
+	ErrInvalidExchangeRate  = NewDomainError(InvalidInputError, "invalid_exchange_rate", "invalid exchange rate, use a positive decimal with up to 6 decimal places")
+	ErrExchangeRateNotFound = NewDomainError(InvalidInputError, "exchange_rate_not_found", "exchange rate not found")
+)
+
+// ExchangeRate é a cotação de From para To (quantas unidades de To valem uma de From)
+// a partir de EffectiveAt. Rate é escalado por RateScale para evitar float.
+type ExchangeRate struct {
+	From        Currency
+	To          Currency
+	Rate        int64
+	EffectiveAt time.Time
+}
+
+type ExchangeRateEntityMongo struct {
+	Id          string   `bson:"_id"`
+	From        Currency `bson:"from"`
+	To          Currency `bson:"to"`
+	Rate        int64    `bson:"rate"`
+	EffectiveAt int64    `bson:"effective_at"`
+}
+
+type ExchangeRateRepositoryInterface interface {
+	UpsertExchangeRate(ctx context.Context, rate ExchangeRate) error
+	FindExchangeRates(ctx context.Context) ([]ExchangeRate, error)
+	// FindEffectiveRate retorna a cotação vigente em at, usando a cotação inversa
+	// quando só ela estiver cadastrada; retorna ErrExchangeRateNotFound se não houver
+	FindEffectiveRate(ctx context.Context, from, to Currency, at time.Time) (*ExchangeRate, error)
+}
+
+func CreateExchangeRate(from, to Currency, rate string, effectiveAt time.Time) (*ExchangeRate, error) {
+	if !from.IsSupported() || !to.IsSupported() || from == to {
+		return nil, ErrInvalidExchangeRate.WithMessage("exchange rate must convert between two different supported currencies")
+	}
+
+	value, err := parseDecimal(rate, RateDecimalPlaces)
+	if err != nil || value <= 0 {
+		return nil, ErrInvalidExchangeRate
+	}
+
+	return &ExchangeRate{
+		From:        from,
+		To:          to,
+		Rate:        value,
+		EffectiveAt: effectiveAt,
+	}, nil
+}
+
+// IdentityRate é a cotação de uma moeda para ela mesma
+func IdentityRate(currency Currency, at time.Time) *ExchangeRate {
+	return &ExchangeRate{From: currency, To: currency, Rate: RateScale, EffectiveAt: at}
+}
+
+// Convert converte um valor em From para To, arredondando meio centavo para cima
+func (r ExchangeRate) Convert(amount Money) Money {
+	return NewMoney(divRound(new(big.Int).Mul(big.NewInt(amount.Cents), big.NewInt(r.Rate)), RateScale), r.To)
+}
+
+// Inverse retorna a cotação de To para From com a mesma data de vigência
+func (r ExchangeRate) Inverse() ExchangeRate {
+	return ExchangeRate{
+		From:        r.To,
+		To:          r.From,
+		Rate:        divRound(new(big.Int).SetInt64(RateScale*RateScale), r.Rate),
+		EffectiveAt: r.EffectiveAt,
+	}
+}
+
+// RateString formata a cotação como decimal sem zeros à direita ("5.0123")
+func (r ExchangeRate) RateString() string {
+	formatted := strconv.FormatInt(r.Rate/RateScale, 10) + "." + leftPad(strconv.FormatInt(r.Rate%RateScale, 10), RateDecimalPlaces)
+	return strings.TrimSuffix(strings.TrimRight(formatted, "0"), ".")
+}
+
+func divRound(value *big.Int, divisor int64) int64 {
+	half := big.NewInt(divisor / 2)
+	if value.Sign() < 0 {
+		half.Neg(half)
+	}
+
+	return new(big.Int).Quo(value.Add(value, half), big.NewInt(divisor)).Int64()
+}
+
+func leftPad(value string, size int) string {
+	return strings.Repeat("0", size-len(value)) + value
+}
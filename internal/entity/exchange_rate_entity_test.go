@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateExchangeRate(t *testing.T) {
+	rate, err := CreateExchangeRate(USD, BRL, "4.9875", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4987500), rate.Rate)
+	assert.Equal(t, "4.9875", rate.RateString())
+
+	_, err = CreateExchangeRate(USD, BRL, "0", time.Now())
+	assert.ErrorIs(t, err, ErrInvalidExchangeRate)
+
+	_, err = CreateExchangeRate(USD, BRL, "1.1234567", time.Now())
+	assert.ErrorIs(t, err, ErrInvalidExchangeRate)
+
+	_, err = CreateExchangeRate(BRL, BRL, "1", time.Now())
+	assert.Error(t, err)
+}
+
+func TestExchangeRateConvert(t *testing.T) {
+	rate, _ := CreateExchangeRate(USD, BRL, "4.9875", time.Now())
+
+	converted := rate.Convert(NewMoney(1001, USD))
+	assert.Equal(t, BRL, converted.Currency)
+	// 10.01 * 4.9875 = 49.924875, arredondado para 49.92
+	assert.Equal(t, "49.92", converted.String())
+
+	inverse := rate.Inverse()
+	assert.Equal(t, BRL, inverse.From)
+	assert.Equal(t, USD, inverse.To)
+	assert.Equal(t, "0.200501", inverse.RateString())
+	assert.Equal(t, "10.01", inverse.Convert(converted).String())
+}
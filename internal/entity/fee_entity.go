@@ -0,0 +1,163 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BasisPoints é uma taxa em centésimos de ponto percentual: 1050 = 10,5%
+type BasisPoints int64
+
+// MaxBasisPoints é 100%
+const MaxBasisPoints BasisPoints = 10000
+
+// ParseBasisPoints converte um percentual decimal como "10.5" em pontos-base
+func ParseBasisPoints(value string) (BasisPoints, error) {
+	hundredths, err := parseDecimal(value, 2)
+	if err != nil || hundredths < 0 || BasisPoints(hundredths) > MaxBasisPoints {
+		return 0, fmt.Errorf("invalid percentage %q, expected a decimal between 0 and 100 with up to 2 decimal places", value)
+	}
+	return BasisPoints(hundredths), nil
+}
+
+// Of aplica a taxa ao valor em centavos, arredondando meio centavo para cima
+func (b BasisPoints) Of(cents int64) int64 {
+	return roundBasisPoints(cents * int64(b))
+}
+
+// roundBasisPoints converte centavos multiplicados por pontos-base em centavos
+func roundBasisPoints(value int64) int64 {
+	return (value + int64(MaxBasisPoints)/2) / int64(MaxBasisPoints)
+}
+
+// FeeTier é uma faixa da tarifa sobre o valor final: Rate vale para a parte do valor entre o
+// limite da faixa anterior e UpTo. A última faixa não tem limite (UpTo = 0).
+type FeeTier struct {
+	UpTo int64
+	Rate BasisPoints
+}
+
+// FinalValueFee é a tarifa cobrada do vendedor sobre o valor de venda, em faixas marginais,
+// limitada por Minimum e Maximum (0 = sem limite)
+type FinalValueFee struct {
+	Tiers   []FeeTier
+	Minimum int64
+	Maximum int64
+}
+
+// FeeSchedule é a tabela de tarifas de uma moeda; os valores estão em centavos dessa moeda.
+// Categories substitui a tarifa sobre o valor final nas categorias listadas.
+type FeeSchedule struct {
+	ListingFee    int64
+	FinalValueFee FinalValueFee
+	Categories    map[string]FinalValueFee
+	// FeeTaxRate incide sobre as tarifas cobradas do vendedor
+	FeeTaxRate BasisPoints
+	// BuyerTaxRate incide sobre o valor do item cobrado do comprador
+	BuyerTaxRate BasisPoints
+}
+
+// DefaultFeeSchedule é a tabela usada nas moedas sem tabela configurada: sem tarifa de
+// anúncio, 10% sobre o valor final até 1.000,00 e 5% sobre o que passar disso, com mínimo
+// de 1,00
+func DefaultFeeSchedule() FeeSchedule {
+	return FeeSchedule{
+		FinalValueFee: FinalValueFee{
+			Tiers:   []FeeTier{{UpTo: 100000, Rate: 1000}, {Rate: 500}},
+			Minimum: 100,
+		},
+	}
+}
+
+// Validate verifica se a tabela pode ser aplicada a qualquer valor de venda
+func (s FeeSchedule) Validate() error {
+	if s.ListingFee < 0 {
+		return fmt.Errorf("listing fee must not be negative")
+	}
+	if err := s.FinalValueFee.validate(); err != nil {
+		return fmt.Errorf("final value fee: %w", err)
+	}
+
+	categories := make([]string, 0, len(s.Categories))
+	for category := range s.Categories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		if err := s.Categories[category].validate(); err != nil {
+			return fmt.Errorf("final value fee of category %s: %w", category, err)
+		}
+	}
+	return nil
+}
+
+// FinalValueFeeFor calcula a tarifa sobre o valor final de uma venda na categoria
+func (s FeeSchedule) FinalValueFeeFor(category string, finalValue int64) int64 {
+	fee, ok := s.Categories[category]
+	if !ok {
+		fee = s.FinalValueFee
+	}
+	return fee.Calculate(finalValue)
+}
+
+// Calculate aplica a taxa de cada faixa à parte do valor que cai nela e depois os limites.
+// A tarifa nunca passa do valor da venda, mesmo quando o mínimo é maior que ele.
+func (f FinalValueFee) Calculate(finalValue int64) int64 {
+	if finalValue <= 0 {
+		return 0
+	}
+
+	var total, lower int64
+	for _, tier := range f.Tiers {
+		upper := finalValue
+		if tier.UpTo > 0 && tier.UpTo < finalValue {
+			upper = tier.UpTo
+		}
+		if upper > lower {
+			total += (upper - lower) * int64(tier.Rate)
+		}
+		if tier.UpTo == 0 || tier.UpTo >= finalValue {
+			break
+		}
+		lower = tier.UpTo
+	}
+
+	fee := roundBasisPoints(total)
+	if fee < f.Minimum {
+		fee = f.Minimum
+	}
+	if f.Maximum > 0 && fee > f.Maximum {
+		fee = f.Maximum
+	}
+	if fee > finalValue {
+		fee = finalValue
+	}
+	return fee
+}
+
+func (f FinalValueFee) validate() error {
+	if len(f.Tiers) == 0 {
+		return fmt.Errorf("at least one tier is required")
+	}
+	var lower int64
+	for i, tier := range f.Tiers {
+		last := i == len(f.Tiers)-1
+		if last && tier.UpTo != 0 {
+			return fmt.Errorf("the last tier must not have an upper limit")
+		}
+		if !last && tier.UpTo <= lower {
+			return fmt.Errorf("tier %d must have an upper limit greater than the previous one", i+1)
+		}
+		if tier.Rate < 0 || tier.Rate > MaxBasisPoints {
+			return fmt.Errorf("tier %d rate must be between 0 and 100%%", i+1)
+		}
+		lower = tier.UpTo
+	}
+	if f.Minimum < 0 || f.Maximum < 0 {
+		return fmt.Errorf("minimum and maximum must not be negative")
+	}
+	if f.Maximum > 0 && f.Minimum > f.Maximum {
+		return fmt.Errorf("minimum must not be greater than maximum")
+	}
+	return nil
+}
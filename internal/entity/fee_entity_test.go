@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinalValueFeeAppliesMarginalTiersAndLimits(t *testing.T) {
+	fee := FinalValueFee{
+		Tiers:   []FeeTier{{UpTo: 100000, Rate: 1000}, {UpTo: 500000, Rate: 500}, {Rate: 250}},
+		Minimum: 200,
+		Maximum: 30000,
+	}
+	require.NoError(t, FeeSchedule{FinalValueFee: fee}.Validate())
+
+	assert.Equal(t, int64(0), fee.Calculate(0))
+	assert.Equal(t, int64(200), fee.Calculate(1000))      // 10% de 10,00 fica abaixo do mínimo
+	assert.Equal(t, int64(10000), fee.Calculate(100000))  // 10% de 1.000,00
+	assert.Equal(t, int64(15000), fee.Calculate(200000))  // 100,00 + 5% de 1.000,00
+	assert.Equal(t, int64(30000), fee.Calculate(1000000)) // 300,00 + 2,5% de 5.000,00, limitado a 300,00
+
+	// Meio centavo é arredondado para cima
+	assert.Equal(t, int64(1), BasisPoints(1000).Of(5))
+
+	invalid := FeeSchedule{FinalValueFee: FinalValueFee{Tiers: []FeeTier{{UpTo: 1000, Rate: 1000}}}}
+	assert.Error(t, invalid.Validate())
+
+	rate, err := ParseBasisPoints("12.5")
+	require.NoError(t, err)
+	assert.Equal(t, BasisPoints(1250), rate)
+	_, err = ParseBasisPoints("100.01")
+	assert.Error(t, err)
+}
+
+func TestFinalValueFeeNeverExceedsFinalValue(t *testing.T) {
+	fee := DefaultFeeSchedule().FinalValueFee
+
+	tests := []struct {
+		name       string
+		finalValue int64
+		want       int64
+	}{
+		{"one cent", 1, 1},
+		{"below the minimum", 50, 50},
+		{"equal to the minimum", 100, 100},
+		{"minimum applies", 500, 100},
+		{"rate applies", 2000, 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fee.Calculate(tt.finalValue)
+			assert.Equal(t, tt.want, got)
+			assert.LessOrEqual(t, got, tt.finalValue)
+		})
+	}
+}
+
+func TestNewAuctionInvoices(t *testing.T) {
+	schedule := FeeSchedule{
+		ListingFee:    500,
+		FinalValueFee: FinalValueFee{Tiers: []FeeTier{{Rate: 1000}}},
+		Categories:    map[string]FinalValueFee{"Electronics": {Tiers: []FeeTier{{Rate: 800}}}},
+		FeeTaxRate:    1000,
+		BuyerTaxRate:  500,
+	}
+	auction := Auction{Id: "auction-1", ProductName: "Notebook", Category: "Electronics", Currency: BRL, SellerId: "seller-1"}
+	bid := &Bid{Id: "bid-1", UserId: "user-1", AuctionId: "auction-1", Amount: NewMoney(100000, BRL)}
+
+	invoices := NewAuctionInvoices(auction, bid, schedule, time.Now())
+	require.Len(t, invoices, 2)
+
+	seller := invoices[0]
+	assert.Equal(t, "auction-1:seller", seller.Id)
+	assert.Equal(t, "seller-1", seller.UserId)
+	assert.Equal(t, NewMoney(8500, BRL), seller.Subtotal) // 5,00 de anúncio + 8% de 1.000,00
+	assert.Equal(t, NewMoney(850, BRL), seller.Tax)
+	assert.Equal(t, NewMoney(9350, BRL), seller.Total)
+	assert.Equal(t, InvoiceLineTax, seller.Lines[len(seller.Lines)-1].Kind)
+
+	buyer := invoices[1]
+	assert.Equal(t, "auction-1:buyer", buyer.Id)
+	assert.Equal(t, "user-1", buyer.UserId)
+	assert.Equal(t, NewMoney(105000, BRL), buyer.Total)
+	assert.Equal(t, InvoiceIssued, buyer.Status)
+
+	// As faturas reemitidas para outra tentativa têm id próprio
+	fallback := Bid{Id: "bid-0", UserId: "user-2", AuctionId: "auction-1", Amount: NewMoney(90000, BRL)}
+	reissued := NewReissuedInvoices(auction, fallback, 2, schedule, time.Now())
+	require.Len(t, reissued, 2)
+	assert.Equal(t, "auction-1:seller:2", reissued[0].Id)
+	assert.Equal(t, "auction-1:buyer:2", reissued[1].Id)
+	assert.Equal(t, "user-2", reissued[1].UserId)
+	assert.Equal(t, 2, reissued[1].Attempt)
+
+	// Sem lances, só o vendedor é faturado, pela tarifa de anúncio
+	unsold := NewAuctionInvoices(auction, nil, schedule, time.Now())
+	require.Len(t, unsold, 1)
+	assert.Equal(t, NewMoney(550, BRL), unsold[0].Total)
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+// ExpirationCheckerStatus descreve a goroutine que encerra os leilões expirados
+type ExpirationCheckerStatus struct {
+	// Leader indica se esta réplica detém a concessão do verificador e, portanto, é a que
+	// encerra os leilões expirados
+	Leader        bool
+	Interval      time.Duration
+	StartedAt     time.Time
+	LastRunAt     time.Time
+	LastSuccessAt time.Time
+	LastError     string
+	// Lag é há quanto tempo expirou o leilão ativo mais antigo (zero se não houver nenhum)
+	Lag time.Duration
+}
+
+// Healthy indica se o verificador está em dia: concluiu uma execução nos últimos três
+// intervalos e nenhum leilão ativo está expirado há mais do que isso
+func (s ExpirationCheckerStatus) Healthy(now time.Time) bool {
+	last := s.LastSuccessAt
+	if last.IsZero() {
+		last = s.StartedAt
+	}
+	tolerance := 3 * s.Interval
+	return now.Sub(last) <= tolerance && s.Lag <= tolerance
+}
+
+type ExpirationCheckerInterface interface {
+	ExpirationCheckerStatus(ctx context.Context) (ExpirationCheckerStatus, error)
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type IdempotencyStatus string
+
+const (
+	IdempotencyProcessing IdempotencyStatus = "processing"
+	IdempotencyCompleted  IdempotencyStatus = "completed"
+)
+
+// ErrIdempotencyLockLost indica que a reserva passou para outra requisição (o LockedUntil
+// venceu) antes de esta terminar, então a resposta dela não é guardada
+var ErrIdempotencyLockLost = errors.New("idempotency key was taken over by another request")
+
+// IdempotencyRecord guarda a resposta de uma requisição feita com Idempotency-Key.
+// Enquanto Status é processing, LockedUntil impede que outra requisição com a mesma
+// chave execute; depois de completed a resposta é reenviada até ExpiresAt. Owner identifica
+// a requisição que tem a reserva, e só ela pode completá-la ou liberá-la.
+type IdempotencyRecord struct {
+	Id             string
+	Owner          string
+	RequestHash    string
+	Status         IdempotencyStatus
+	ResponseStatus int
+	ContentType    string
+	ResponseBody   []byte
+	LockedUntil    time.Time
+	ExpiresAt      time.Time
+}
+
+// IdempotencyRecordEntityMongo usa data BSON em expires_at por causa do índice TTL
+type IdempotencyRecordEntityMongo struct {
+	Id             string            `bson:"_id"`
+	Owner          string            `bson:"owner"`
+	RequestHash    string            `bson:"request_hash"`
+	Status         IdempotencyStatus `bson:"status"`
+	ResponseStatus int               `bson:"response_status,omitempty"`
+	ContentType    string            `bson:"content_type,omitempty"`
+	ResponseBody   []byte            `bson:"response_body,omitempty"`
+	LockedUntil    int64             `bson:"locked_until"`
+	ExpiresAt      time.Time         `bson:"expires_at"`
+}
+
+type IdempotencyRepositoryInterface interface {
+	// Reserve registra a chave como em processamento. Retorna nil se a reserva foi
+	// feita, ou o registro existente se a chave já estiver em uso.
+	Reserve(ctx context.Context, record IdempotencyRecord) (*IdempotencyRecord, error)
+	// Complete guarda a resposta se a reserva ainda for de owner; caso contrário retorna
+	// ErrIdempotencyLockLost
+	Complete(ctx context.Context, id, owner string, status int, contentType string, body []byte) error
+	// Release remove a reserva de owner para que a requisição possa ser repetida
+	Release(ctx context.Context, id, owner string) error
+}
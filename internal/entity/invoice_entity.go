@@ -0,0 +1,184 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// InvoiceParty é a parte cobrada pela fatura
+type InvoiceParty string
+
+const (
+	InvoiceSeller InvoiceParty = "seller"
+	InvoiceBuyer  InvoiceParty = "buyer"
+)
+
+// InvoiceStatus indica se a fatura vale ou foi anulada pela troca do comprador
+type InvoiceStatus string
+
+const (
+	InvoiceIssued InvoiceStatus = "issued"
+	InvoiceVoided InvoiceStatus = "voided"
+)
+
+// InvoiceLineKind é o tipo de um item da fatura; os itens tax formam o imposto da fatura
+type InvoiceLineKind string
+
+const (
+	InvoiceLineItem          InvoiceLineKind = "item"
+	InvoiceLineListingFee    InvoiceLineKind = "listing_fee"
+	InvoiceLineFinalValueFee InvoiceLineKind = "final_value_fee"
+	InvoiceLineTax           InvoiceLineKind = "tax"
+)
+
+var ErrInvoiceNotFound = NewDomainError(NotFoundError, "invoice_not_found", "invoice not found")
+
+type InvoiceLine struct {
+	Kind        InvoiceLineKind
+	Description string
+	Amount      Money
+}
+
+// Invoice é a fatura de uma das partes de um leilão encerrado, emitida para uma tentativa de
+// acerto. O Id é "<auction_id>:<party>" nas faturas do encerramento e
+// "<auction_id>:<party>:<attempt>" nas reemitidas depois dele, então cada emissão tem no máximo
+// uma fatura por parte. Quando o comprador muda, as faturas anteriores são anuladas (VoidedAt).
+type Invoice struct {
+	Id        string
+	AuctionId string
+	Attempt   int
+	Party     InvoiceParty
+	UserId    string
+	Currency  Currency
+	Status    InvoiceStatus
+	Lines     []InvoiceLine
+	Subtotal  Money
+	Tax       Money
+	Total     Money
+	IssuedAt  time.Time
+	VoidedAt  *time.Time
+}
+
+type InvoiceLineEntityMongo struct {
+	Kind        InvoiceLineKind `bson:"kind"`
+	Description string          `bson:"description"`
+	Amount      int64           `bson:"amount"`
+}
+
+// InvoiceEntityMongo grava os valores em centavos e os horários em milissegundos Unix
+type InvoiceEntityMongo struct {
+	Id        string                   `bson:"_id"`
+	AuctionId string                   `bson:"auction_id"`
+	Attempt   int                      `bson:"attempt"`
+	Party     InvoiceParty             `bson:"party"`
+	UserId    string                   `bson:"user_id"`
+	Currency  Currency                 `bson:"currency"`
+	Status    InvoiceStatus            `bson:"status"`
+	Lines     []InvoiceLineEntityMongo `bson:"lines"`
+	Subtotal  int64                    `bson:"subtotal"`
+	Tax       int64                    `bson:"tax"`
+	Total     int64                    `bson:"total"`
+	IssuedAt  int64                    `bson:"issued_at"`
+	VoidedAt  int64                    `bson:"voided_at,omitempty"`
+}
+
+type InvoiceRepositoryInterface interface {
+	// CreateInvoices grava as faturas; as que já foram emitidas são ignoradas
+	CreateInvoices(ctx context.Context, invoices []Invoice) error
+	FindInvoiceById(ctx context.Context, invoiceId string) (*Invoice, error)
+	// VoidInvoices anula as faturas do leilão que ainda valem, menos as reemitidas para a
+	// tentativa informada; uma reemissão atrasada não anula as de uma tentativa mais nova
+	VoidInvoices(ctx context.Context, auctionId string, currentAttempt int, at time.Time) error
+	// FindInvoicesIssuedBetween busca as faturas emitidas no intervalo, das mais antigas
+	// para as mais recentes
+	FindInvoicesIssuedBetween(ctx context.Context, from, to time.Time) ([]Invoice, error)
+}
+
+// NewAuctionInvoices calcula as faturas emitidas no encerramento do leilão, da primeira
+// tentativa de acerto. O vendedor paga a tarifa de anúncio e, se houve venda, a tarifa sobre o
+// valor final, mais o imposto sobre as tarifas; o comprador paga o lance vendido mais o
+// imposto sobre o item. Leilões sem seller_id não têm fatura do vendedor, e leilões sem lances
+// (ou com a reserva não alcançada) não têm fatura do comprador.
+func NewAuctionInvoices(auction Auction, winningBid *Bid, schedule FeeSchedule, at time.Time) []Invoice {
+	return auctionInvoices(auction, winningBid, 1, schedule, at)
+}
+
+// NewReissuedInvoices calcula as faturas de uma tentativa de acerto aberta depois do
+// encerramento, com o comprador e o valor vendido dela
+func NewReissuedInvoices(auction Auction, sale Bid, attempt int, schedule FeeSchedule, at time.Time) []Invoice {
+	invoices := auctionInvoices(auction, &sale, attempt, schedule, at)
+	for i := range invoices {
+		invoices[i].Id = ReissuedInvoiceId(auction.Id, invoices[i].Party, attempt)
+	}
+	return invoices
+}
+
+func auctionInvoices(auction Auction, winningBid *Bid, attempt int, schedule FeeSchedule, at time.Time) []Invoice {
+	at = at.Truncate(time.Millisecond)
+	var invoices []Invoice
+
+	if auction.SellerId != "" {
+		var lines []InvoiceLine
+		if schedule.ListingFee > 0 {
+			lines = append(lines, InvoiceLine{Kind: InvoiceLineListingFee, Description: "Listing fee",
+				Amount: NewMoney(schedule.ListingFee, auction.Currency)})
+		}
+		if winningBid != nil {
+			if fee := schedule.FinalValueFeeFor(auction.Category, winningBid.Amount.Cents); fee > 0 {
+				lines = append(lines, InvoiceLine{Kind: InvoiceLineFinalValueFee, Description: "Final value fee on " + winningBid.Amount.String(),
+					Amount: NewMoney(fee, auction.Currency)})
+			}
+		}
+		if len(lines) > 0 {
+			invoices = append(invoices, newInvoice(auction, attempt, InvoiceSeller, auction.SellerId, lines, schedule.FeeTaxRate, "Tax on fees", at))
+		}
+	}
+
+	if winningBid != nil {
+		lines := []InvoiceLine{{Kind: InvoiceLineItem, Description: auction.ProductName, Amount: winningBid.Amount}}
+		invoices = append(invoices, newInvoice(auction, attempt, InvoiceBuyer, winningBid.UserId, lines, schedule.BuyerTaxRate, "Sales tax", at))
+	}
+
+	return invoices
+}
+
+// newInvoice soma os itens, acrescenta o imposto (se houver) e fecha os totais
+func newInvoice(auction Auction, attempt int, party InvoiceParty, userId string, lines []InvoiceLine, taxRate BasisPoints, taxDescription string, at time.Time) Invoice {
+	var subtotal int64
+	for _, line := range lines {
+		subtotal += line.Amount.Cents
+	}
+
+	tax := taxRate.Of(subtotal)
+	if tax > 0 {
+		lines = append(lines, InvoiceLine{Kind: InvoiceLineTax, Description: taxDescription, Amount: NewMoney(tax, auction.Currency)})
+	}
+
+	return Invoice{
+		Id:        InvoiceId(auction.Id, party),
+		AuctionId: auction.Id,
+		Attempt:   attempt,
+		Party:     party,
+		UserId:    userId,
+		Currency:  auction.Currency,
+		Status:    InvoiceIssued,
+		Lines:     lines,
+		Subtotal:  NewMoney(subtotal, auction.Currency),
+		Tax:       NewMoney(tax, auction.Currency),
+		Total:     NewMoney(subtotal+tax, auction.Currency),
+		IssuedAt:  at,
+	}
+}
+
+// InvoiceId identifica a fatura da parte emitida no encerramento do leilão
+func InvoiceId(auctionId string, party InvoiceParty) string {
+	return auctionId + ":" + string(party)
+}
+
+// ReissuedInvoiceId identifica a fatura da parte reemitida para a tentativa de acerto. A
+// tentativa 1 só é reemitida quando a reserva não foi alcançada e o vendedor aceita uma
+// oferta, e não colide com as faturas do encerramento.
+func ReissuedInvoiceId(auctionId string, party InvoiceParty, attempt int) string {
+	return fmt.Sprintf("%s:%s:%d", auctionId, party, attempt)
+}
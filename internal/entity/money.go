@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Currency string
+
+const (
+	BRL Currency = "BRL"
+	USD Currency = "USD"
+)
+
+// DefaultCurrency é a moeda usada quando nenhuma é informada
+const DefaultCurrency = BRL
+
+var ErrInvalidMoney = NewDomainError(InvalidInputError, "invalid_money", "invalid monetary amount, use a decimal with up to 2 decimal places")
+
+var errInvalidDecimal = errors.New("invalid decimal")
+
+// SupportedCurrencies são as moedas aceitas em leilões e lances
+var SupportedCurrencies = []Currency{BRL, USD}
+
+// IsSupported informa se a moeda é aceita pelo marketplace
+func (c Currency) IsSupported() bool {
+	for _, supported := range SupportedCurrencies {
+		if c == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// OrDefault retorna a moeda padrão quando nenhuma foi informada (documentos antigos)
+func (c Currency) OrDefault() Currency {
+	if c == "" {
+		return DefaultCurrency
+	}
+	return c
+}
+
+// Money representa um valor monetário exato em centavos, evitando os erros de
+// arredondamento de float64. Em JSON é lido e escrito como string decimal ("10.50").
+type Money struct {
+	Cents    int64
+	Currency Currency
+}
+
+func NewMoney(cents int64, currency Currency) Money {
+	return Money{Cents: cents, Currency: currency}
+}
+
+// ParseMoney converte uma string decimal como "1234.5" em Money sem passar por float
+func ParseMoney(value string, currency Currency) (Money, error) {
+	cents, err := parseDecimal(value, 2)
+	if err != nil {
+		return Money{}, ErrInvalidMoney
+	}
+
+	return Money{Cents: cents, Currency: currency}, nil
+}
+
+// parseDecimal converte uma string decimal em inteiro escalado por 10^places,
+// rejeitando notação científica e mais casas decimais do que o permitido
+func parseDecimal(value string, places int) (int64, error) {
+	value = strings.TrimSpace(value)
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	integer, fraction, hasFraction := strings.Cut(value, ".")
+	if integer == "" || (hasFraction && fraction == "") || len(fraction) > places {
+		return 0, errInvalidDecimal
+	}
+
+	for _, digit := range integer + fraction {
+		if digit < '0' || digit > '9' {
+			return 0, errInvalidDecimal
+		}
+	}
+
+	fraction += strings.Repeat("0", places-len(fraction))
+	scale := int64(math.Pow10(places))
+
+	units, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil || units > (math.MaxInt64-scale+1)/scale {
+		return 0, errInvalidDecimal
+	}
+
+	var decimals int64
+	if places > 0 {
+		decimals, _ = strconv.ParseInt(fraction, 10, 64)
+	}
+
+	total := units*scale + decimals
+	if negative {
+		total = -total
+	}
+
+	return total, nil
+}
+
+// String retorna o valor decimal com duas casas, sem a moeda
+func (m Money) String() string {
+	sign := ""
+	cents := m.Cents
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func (m Money) IsPositive() bool {
+	return m.Cents > 0
+}
+
+// GreaterThan compara dois valores; ambos devem estar na mesma moeda
+func (m Money) GreaterThan(other Money) bool {
+	return m.Cents > other.Cents
+}
+
+func (m Money) Add(other Money) Money {
+	return Money{Cents: m.Cents + other.Cents, Currency: m.Currency}
+}
+
+func (m Money) Sub(other Money) Money {
+	return Money{Cents: m.Cents - other.Cents, Currency: m.Currency}
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON aceita "10.50" e também o número 10.50 (lido pelo texto, sem float);
+// a moeda deve ser definida por quem lê o valor
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		return nil
+	}
+
+	if strings.HasPrefix(value, `"`) {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return ErrInvalidMoney
+		}
+	}
+
+	parsed, err := ParseMoney(value, m.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
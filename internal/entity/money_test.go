@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int64
+		valid    bool
+	}{
+		{value: "10", expected: 1000, valid: true},
+		{value: "0.1", expected: 10, valid: true},
+		{value: "2500.55", expected: 250055, valid: true},
+		{value: "-3.05", expected: -305, valid: true},
+		{value: "1.005", valid: false},
+		{value: "1e3", valid: false},
+		{value: ".5", valid: false},
+		{value: "10.", valid: false},
+		{value: "", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			money, err := ParseMoney(tt.value, BRL)
+			if !tt.valid {
+				assert.ErrorIs(t, err, ErrInvalidMoney)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, money.Cents)
+			assert.Equal(t, BRL, money.Currency)
+		})
+	}
+}
+
+func TestMoneyAddsWithoutDrift(t *testing.T) {
+	total := NewMoney(0, BRL)
+	increment, _ := ParseMoney("0.1", BRL)
+	for i := 0; i < 10; i++ {
+		total = total.Add(increment)
+	}
+
+	assert.Equal(t, "1.00", total.String())
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var input struct {
+		Amount Money `json:"amount"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":"19.9"}`), &input))
+	assert.Equal(t, int64(1990), input.Amount.Cents)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":2500.10}`), &input))
+	assert.Equal(t, int64(250010), input.Amount.Cents)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"abc"}`), &input))
+
+	output, err := json.Marshal(input)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"2500.10"}`, string(output))
+}
@@ -0,0 +1,122 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	OutbidNotification           NotificationType = "outbid"
+	AuctionWonNotification       NotificationType = "auction_won"
+	EndingSoonNotification       NotificationType = "ending_soon"
+	PaymentRequestedNotification NotificationType = "payment_requested"
+	SecondChanceNotification     NotificationType = "second_chance_offer"
+)
+
+const (
+	EmailNotificationChannel = "email"
+	InboxNotificationChannel = "inbox"
+	LogNotificationChannel   = "log"
+)
+
+// DefaultNotificationChannels são usados quando o usuário não configurou preferências
+var DefaultNotificationChannels = []string{InboxNotificationChannel, LogNotificationChannel}
+
+// DefaultReminderMinutes define quando avisar os observadores de um leilão que está terminando
+var DefaultReminderMinutes = []int{10}
+
+// MaxReminderMinutes é a maior antecedência permitida para lembretes de fim de leilão
+const MaxReminderMinutes = 60
+
+type Notification struct {
+	Id        string
+	UserId    string
+	AuctionId string
+	Type      NotificationType
+	Title     string
+	Message   string
+	Read      bool
+	Timestamp time.Time
+}
+
+type NotificationEntityMongo struct {
+	Id        string           `bson:"_id"`
+	UserId    string           `bson:"user_id"`
+	AuctionId string           `bson:"auction_id"`
+	Type      NotificationType `bson:"type"`
+	Title     string           `bson:"title"`
+	Message   string           `bson:"message"`
+	Read      bool             `bson:"read"`
+	Timestamp int64            `bson:"timestamp"`
+}
+
+// NotificationPreference guarda as preferências de notificação de um usuário
+type NotificationPreference struct {
+	UserId          string
+	Locale          string
+	Email           string
+	Channels        []string
+	MutedTypes      []NotificationType
+	ReminderMinutes []int
+}
+
+type NotificationPreferenceEntityMongo struct {
+	UserId          string             `bson:"_id"`
+	Locale          string             `bson:"locale"`
+	Email           string             `bson:"email"`
+	Channels        []string           `bson:"channels"`
+	MutedTypes      []NotificationType `bson:"muted_types"`
+	ReminderMinutes []int              `bson:"reminder_minutes"`
+}
+
+type NotificationRepositoryInterface interface {
+	CreateNotification(ctx context.Context, notification *Notification) error
+	FindNotificationsByUserId(ctx context.Context, userId string, onlyUnread bool) ([]Notification, error)
+	MarkNotificationsAsRead(ctx context.Context, userId string, ids []string) error
+	// RegisterDelivery retorna false se a chave já foi registrada, evitando notificações duplicadas
+	RegisterDelivery(ctx context.Context, dedupKey string) (bool, error)
+	// ReleaseDelivery remove a chave quando a entrega falhou, permitindo uma nova tentativa
+	ReleaseDelivery(ctx context.Context, dedupKey string) error
+}
+
+type NotificationPreferenceRepositoryInterface interface {
+	FindPreferenceByUserId(ctx context.Context, userId string) (*NotificationPreference, error)
+	UpsertPreference(ctx context.Context, preference *NotificationPreference) error
+}
+
+func CreateNotification(userId, auctionId string, notificationType NotificationType, title, message string) (*Notification, error) {
+	notification := &Notification{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		AuctionId: auctionId,
+		Type:      notificationType,
+		Title:     title,
+		Message:   message,
+		Read:      false,
+		Timestamp: time.Now(),
+	}
+
+	return notification, nil
+}
+
+// Allows informa se o usuário deseja receber notificações do tipo informado
+func (p *NotificationPreference) Allows(notificationType NotificationType) bool {
+	for _, muted := range p.MutedTypes {
+		if muted == notificationType {
+			return false
+		}
+	}
+	return true
+}
+
+// Reminders retorna as antecedências (em minutos) dos lembretes de fim de leilão
+func (p *NotificationPreference) Reminders() []int {
+	if len(p.ReminderMinutes) == 0 {
+		return DefaultReminderMinutes
+	}
+	return p.ReminderMinutes
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OfferStatus é a situação de uma oferta de segunda chance. Uma oferta pendente cujo prazo
+// terminou é apresentada como expired, sem precisar ser gravada.
+type OfferStatus string
+
+const (
+	OfferPending   OfferStatus = "pending"
+	OfferAccepted  OfferStatus = "accepted"
+	OfferDeclined  OfferStatus = "declined"
+	OfferExpired   OfferStatus = "expired"
+	OfferWithdrawn OfferStatus = "withdrawn"
+)
+
+const (
+	// DefaultOfferExpiry é o prazo de resposta de uma oferta quando o vendedor não informa outro
+	DefaultOfferExpiry = 24 * time.Hour
+	// MaxOfferExpiry é o maior prazo de resposta aceito
+	MaxOfferExpiry = 7 * 24 * time.Hour
+)
+
+var (
+	ErrOfferNotFound        = NewDomainError(NotFoundError, "offer_not_found", "offer not found")
+	ErrOfferNotPending      = NewDomainError(ConflictError, "offer_not_pending", "offer is no longer pending")
+	ErrOfferAlreadyAccepted = NewDomainError(ConflictError, "offer_already_accepted", "an offer was already accepted for this auction")
+	ErrSecondChanceNotOpen  = NewDomainError(ConflictError, "second_chance_not_available", "second-chance offers are only available after the winner defaults or when the reserve price is not met")
+	ErrNotAuctionSeller     = NewDomainError(ForbiddenError, "not_auction_seller", "only the seller can make offers for this auction")
+	ErrNotOfferRecipient    = NewDomainError(ForbiddenError, "not_offer_recipient", "only the recipient can answer this offer")
+	ErrNotRunnerUp          = NewDomainError(InvalidInputError, "not_a_runner_up", "user is not a runner-up of this auction")
+)
+
+// SecondChanceOffer oferece o item de um leilão cujo vencedor não pagou a um dos demais
+// licitantes, pelo valor do maior lance dele. Só uma oferta pode ser aceita por leilão.
+type SecondChanceOffer struct {
+	Id          string
+	AuctionId   string
+	SellerId    string
+	UserId      string
+	BidId       string
+	Amount      Money
+	Status      OfferStatus
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+	RespondedAt time.Time
+}
+
+// SecondChanceOfferEntityMongo grava o valor em centavos e os horários em milissegundos
+// Unix; responded_at é 0 enquanto a oferta não foi respondida
+type SecondChanceOfferEntityMongo struct {
+	Id          string      `bson:"_id"`
+	AuctionId   string      `bson:"auction_id"`
+	SellerId    string      `bson:"seller_id"`
+	UserId      string      `bson:"user_id"`
+	BidId       string      `bson:"bid_id"`
+	Amount      int64       `bson:"amount"`
+	Currency    Currency    `bson:"currency"`
+	Status      OfferStatus `bson:"status"`
+	ExpiresAt   int64       `bson:"expires_at"`
+	CreatedAt   int64       `bson:"created_at"`
+	RespondedAt int64       `bson:"responded_at"`
+}
+
+type OfferRepositoryInterface interface {
+	CreateOffers(ctx context.Context, offers []SecondChanceOffer) error
+	FindOffersByAuctionId(ctx context.Context, auctionId string) ([]SecondChanceOffer, error)
+	FindOfferById(ctx context.Context, offerId string) (*SecondChanceOffer, error)
+	// AcceptOffer aceita a oferta pendente e não expirada; retorna ErrOfferAlreadyAccepted
+	// se outra oferta do leilão já foi aceita e ErrOfferNotPending se esta não está mais aberta
+	AcceptOffer(ctx context.Context, offerId string, at time.Time) error
+	DeclineOffer(ctx context.Context, offerId string, at time.Time) error
+	// ReopenOffer volta a oferta aceita para pendente, quando o aceite não pôde ser concluído
+	ReopenOffer(ctx context.Context, offerId string) error
+	// WithdrawPendingOffers encerra as ofertas ainda pendentes do leilão
+	WithdrawPendingOffers(ctx context.Context, auctionId string, at time.Time) error
+}
+
+// SecondChanceOfferedListener é chamado para cada oferta de segunda chance criada
+type SecondChanceOfferedListener func(ctx context.Context, offer SecondChanceOffer)
+
+// NewSecondChanceOffer cria a oferta para o autor do lance, pelo valor do lance
+func NewSecondChanceOffer(sellerId string, bid Bid, expiresIn time.Duration) SecondChanceOffer {
+	now := time.Now().Truncate(time.Millisecond)
+	return SecondChanceOffer{
+		Id:        uuid.New().String(),
+		AuctionId: bid.AuctionId,
+		SellerId:  sellerId,
+		UserId:    bid.UserId,
+		BidId:     bid.Id,
+		Amount:    bid.Amount,
+		Status:    OfferPending,
+		ExpiresAt: now.Add(expiresIn),
+		CreatedAt: now,
+	}
+}
+
+// StatusAt retorna a situação da oferta no instante informado
+func (o SecondChanceOffer) StatusAt(now time.Time) OfferStatus {
+	if o.Status == OfferPending && now.After(o.ExpiresAt) {
+		return OfferExpired
+	}
+	return o.Status
+}
+
+// RunnerUps retorna o maior lance de cada usuário, do maior para o menor, sem os usuários
+// em excludedUserIds. bids deve estar ordenado pelo valor, do maior para o menor.
+func RunnerUps(bids []Bid, excludedUserIds []string) []Bid {
+	seen := make(map[string]bool, len(excludedUserIds))
+	for _, userId := range excludedUserIds {
+		seen[userId] = true
+	}
+
+	var runnerUps []Bid
+	for _, bid := range bids {
+		if seen[bid.UserId] {
+			continue
+		}
+		seen[bid.UserId] = true
+		runnerUps = append(runnerUps, bid)
+	}
+	return runnerUps
+}
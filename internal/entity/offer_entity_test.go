@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunnerUpsKeepsHighestBidPerUser(t *testing.T) {
+	bids := []Bid{
+		{Id: "bid-5", UserId: "winner", Amount: NewMoney(5000, BRL)},
+		{Id: "bid-4", UserId: "user-2", Amount: NewMoney(4000, BRL)},
+		{Id: "bid-3", UserId: "user-3", Amount: NewMoney(3000, BRL)},
+		{Id: "bid-2", UserId: "user-2", Amount: NewMoney(2000, BRL)},
+		{Id: "bid-1", UserId: "winner", Amount: NewMoney(1000, BRL)},
+	}
+
+	runnerUps := RunnerUps(bids, []string{"winner"})
+
+	var ids []string
+	for _, bid := range runnerUps {
+		ids = append(ids, bid.Id)
+	}
+	assert.Equal(t, []string{"bid-4", "bid-3"}, ids)
+	assert.Empty(t, RunnerUps(bids[:1], []string{"winner"}))
+}
+
+func TestSecondChanceOfferStatusAt(t *testing.T) {
+	bid := Bid{Id: "bid-1", UserId: "user-2", AuctionId: "auction-1", Amount: NewMoney(4000, BRL)}
+	offer := NewSecondChanceOffer("seller", bid, time.Hour)
+	assert.Equal(t, NewMoney(4000, BRL), offer.Amount)
+
+	assert.Equal(t, OfferPending, offer.StatusAt(offer.ExpiresAt))
+	assert.Equal(t, OfferExpired, offer.StatusAt(offer.ExpiresAt.Add(time.Millisecond)))
+
+	offer.Status = OfferDeclined
+	assert.Equal(t, OfferDeclined, offer.StatusAt(offer.ExpiresAt.Add(time.Hour)))
+}
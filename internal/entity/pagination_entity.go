@@ -0,0 +1,66 @@
+package entity
+
+type SortOrder string
+
+const (
+	Ascending  SortOrder = "asc"
+	Descending SortOrder = "desc"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Campos aceitos para ordenação das listagens
+var (
+	AuctionSortFields = []string{"expires_at", "timestamp", "current_price"}
+	BidSortFields     = []string{"timestamp", "amount"}
+)
+
+// Ordenações exclusivas da busca de leilões
+const (
+	SortByRelevance  = "relevance"
+	SortByEndingSoon = "ending_soon"
+)
+
+var ErrInvalidCursor = NewDomainError(InvalidInputError, "invalid_cursor", "invalid pagination cursor")
+
+// PageRequest descreve uma página de uma listagem paginada por cursor
+type PageRequest struct {
+	Limit  int
+	Cursor string
+	SortBy string
+	Order  SortOrder
+}
+
+// WithDefaults preenche os campos não informados com os valores padrão da listagem
+func (p PageRequest) WithDefaults(sortBy string, order SortOrder) PageRequest {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+	if p.SortBy == "" {
+		p.SortBy = sortBy
+	}
+	if p.Order == "" {
+		p.Order = order
+	}
+	return p
+}
+
+// IsSortedBy informa se a página usa um dos campos de ordenação permitidos
+func (p PageRequest) IsSortedBy(allowed []string) bool {
+	if p.Order != Ascending && p.Order != Descending {
+		return false
+	}
+
+	for _, field := range allowed {
+		if p.SortBy == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitPolicy descreve um token bucket: até Capacity requisições seguidas, com
+// um token devolvido ao bucket a cada RefillEvery
+type RateLimitPolicy struct {
+	Capacity    int64
+	RefillEvery time.Duration
+}
+
+// Enabled indica se a política limita alguma coisa (capacidade zero desliga o limite)
+func (p RateLimitPolicy) Enabled() bool {
+	return p.Capacity > 0 && p.RefillEvery > 0
+}
+
+// FullAfter é o tempo para um bucket vazio voltar a ficar cheio
+func (p RateLimitPolicy) FullAfter() time.Duration {
+	return time.Duration(p.Capacity) * p.RefillEvery
+}
+
+// ParseRateLimitPolicy lê uma política no formato "<requisições>/<janela>", como "10/1m": até 10
+// requisições seguidas, com o bucket inteiro reabastecido em 1 minuto. "0" ou "off" desliga.
+func ParseRateLimitPolicy(value string) (RateLimitPolicy, error) {
+	value = strings.TrimSpace(value)
+	if value == "0" || strings.EqualFold(value, "off") {
+		return RateLimitPolicy{}, nil
+	}
+
+	capacityStr, windowStr, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimitPolicy{}, fmt.Errorf("invalid rate limit %q, expected <requests>/<window>", value)
+	}
+
+	capacity, err := strconv.ParseInt(capacityStr, 10, 64)
+	if err != nil || capacity <= 0 {
+		return RateLimitPolicy{}, fmt.Errorf("invalid rate limit %q: requests must be a positive integer", value)
+	}
+
+	window, err := time.ParseDuration(windowStr)
+	if err != nil || window <= 0 {
+		return RateLimitPolicy{}, fmt.Errorf("invalid rate limit %q: window must be a positive duration", value)
+	}
+
+	return RateLimitPolicy{
+		Capacity:    capacity,
+		RefillEvery: window / time.Duration(capacity),
+	}, nil
+}
+
+// RateLimitBucket é o estado de um bucket: tokens disponíveis (fracionários, pois o
+// reabastecimento é contínuo) e o instante da última atualização
+type RateLimitBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// NewRateLimitBucket cria um bucket cheio
+func NewRateLimitBucket(policy RateLimitPolicy, now time.Time) RateLimitBucket {
+	return RateLimitBucket{Tokens: float64(policy.Capacity), UpdatedAt: now}
+}
+
+// Refill devolve os tokens acumulados desde a última atualização, até a capacidade
+func (b *RateLimitBucket) Refill(policy RateLimitPolicy, now time.Time) {
+	if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens += float64(elapsed) / float64(policy.RefillEvery)
+	}
+	b.Tokens = math.Min(b.Tokens, float64(policy.Capacity))
+	b.UpdatedAt = now
+}
+
+// Take reabastece o bucket e consome um token se houver
+func (b *RateLimitBucket) Take(policy RateLimitPolicy, now time.Time) RateLimitResult {
+	b.Refill(policy, now)
+
+	allowed := b.Tokens >= 1
+	if allowed {
+		b.Tokens--
+	}
+
+	return NewRateLimitResult(policy, b.Tokens, allowed)
+}
+
+// RateLimitBucketEntityMongo usa data BSON em expires_at por causa do índice TTL
+type RateLimitBucketEntityMongo struct {
+	Key       string    `bson:"_id"`
+	Tokens    float64   `bson:"tokens"`
+	Allowed   bool      `bson:"allowed"`
+	UpdatedAt int64     `bson:"updated_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// RateLimitResult é o resultado de uma tentativa de consumir um token
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int64
+	Remaining int64
+	// RetryAfter é quanto falta para o próximo token quando a requisição foi negada
+	RetryAfter time.Duration
+	// ResetAfter é quanto falta para o bucket voltar a ficar cheio
+	ResetAfter time.Duration
+}
+
+// NewRateLimitResult monta o resultado a partir dos tokens que sobraram no bucket
+func NewRateLimitResult(policy RateLimitPolicy, tokens float64, allowed bool) RateLimitResult {
+	result := RateLimitResult{
+		Allowed:    allowed,
+		Limit:      policy.Capacity,
+		Remaining:  int64(math.Floor(tokens)),
+		ResetAfter: time.Duration((float64(policy.Capacity) - tokens) * float64(policy.RefillEvery)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(policy.RefillEvery))
+	}
+	return result
+}
+
+type RateLimiterInterface interface {
+	// Take consome um token do bucket identificado por key
+	Take(ctx context.Context, key string, policy RateLimitPolicy) (RateLimitResult, error)
+}
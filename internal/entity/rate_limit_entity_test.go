@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimitPolicy(t *testing.T) {
+	policy, err := ParseRateLimitPolicy("10/1m")
+	assert.Nil(t, err)
+	assert.Equal(t, RateLimitPolicy{Capacity: 10, RefillEvery: 6 * time.Second}, policy)
+
+	policy, err = ParseRateLimitPolicy("off")
+	assert.Nil(t, err)
+	assert.False(t, policy.Enabled())
+
+	for _, value := range []string{"10", "-1/1m", "10/abc", "10/0s"} {
+		_, err := ParseRateLimitPolicy(value)
+		assert.NotNil(t, err, value)
+	}
+}
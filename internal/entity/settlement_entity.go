@@ -0,0 +1,233 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SettlementStatus é a etapa do acerto entre vendedor e vencedor depois do encerramento.
+// O caminho normal é awaiting_payment → paid → shipped → delivered; sem pagamento no prazo
+// o acerto vai para expired, e depois de pago qualquer das partes pode abrir uma disputa.
+// Enquanto o pagamento é capturado na carteira o acerto fica em capturing.
+type SettlementStatus string
+
+const (
+	SettlementAwaitingPayment SettlementStatus = "awaiting_payment"
+	SettlementCapturing       SettlementStatus = "capturing"
+	SettlementPaid            SettlementStatus = "paid"
+	SettlementShipped         SettlementStatus = "shipped"
+	SettlementDelivered       SettlementStatus = "delivered"
+	SettlementExpired         SettlementStatus = "expired"
+	SettlementDisputed        SettlementStatus = "disputed"
+)
+
+var (
+	ErrSettlementNotFound          = NewDomainError(NotFoundError, "settlement_not_found", "settlement not found")
+	ErrInvalidSettlementTransition = NewDomainError(ConflictError, "invalid_settlement_transition", "settlement cannot move to the requested status")
+	ErrPaymentDeadlinePassed       = NewDomainError(ConflictError, "payment_deadline_passed", "payment deadline has passed")
+	ErrNotSettlementParty          = NewDomainError(ForbiddenError, "not_settlement_party", "user is not allowed to perform this settlement action")
+	ErrSettlementAttemptTaken      = NewDomainError(ConflictError, "settlement_attempt_taken", "another settlement attempt was opened for this auction")
+)
+
+// Settlement é uma tentativa de acerto de um leilão encerrado. A primeira tentativa é com o
+// autor do lance vencedor; se ele não pagar no prazo, uma nova tentativa é aberta com o autor
+// do próximo maior lance, e assim por diante. OfferPending marca a tentativa aberta cuja
+// reserva ou aviso aos listeners ainda não terminou, e ExpiryPending a tentativa expirada cujo
+// estorno ou passagem ao próximo licitante ainda não terminou.
+type Settlement struct {
+	Id              string
+	AuctionId       string
+	Attempt         int
+	SellerId        string
+	WinnerId        string
+	BidId           string
+	Amount          Money
+	Status          SettlementStatus
+	PaymentDeadline time.Time
+	TrackingCode    string
+	DisputeReason   string
+	DisputedBy      string
+	OfferPending    bool
+	ExpiryPending   bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// SettlementEntityMongo grava o valor em centavos e os horários em milissegundos Unix
+type SettlementEntityMongo struct {
+	Id              string           `bson:"_id"`
+	AuctionId       string           `bson:"auction_id"`
+	Attempt         int              `bson:"attempt"`
+	SellerId        string           `bson:"seller_id,omitempty"`
+	WinnerId        string           `bson:"winner_id"`
+	BidId           string           `bson:"bid_id"`
+	Amount          int64            `bson:"amount"`
+	Currency        Currency         `bson:"currency"`
+	Status          SettlementStatus `bson:"status"`
+	PaymentDeadline int64            `bson:"payment_deadline"`
+	TrackingCode    string           `bson:"tracking_code,omitempty"`
+	DisputeReason   string           `bson:"dispute_reason,omitempty"`
+	DisputedBy      string           `bson:"disputed_by,omitempty"`
+	OfferPending    bool             `bson:"offer_pending,omitempty"`
+	ExpiryPending   bool             `bson:"expiry_pending,omitempty"`
+	CreatedAt       int64            `bson:"created_at"`
+	UpdatedAt       int64            `bson:"updated_at"`
+}
+
+type SettlementRepositoryInterface interface {
+	// CreateSettlement grava a tentativa; retorna false se ela já existia
+	CreateSettlement(ctx context.Context, settlement *Settlement) (bool, error)
+	FindSettlementsByAuctionId(ctx context.Context, auctionId string) ([]Settlement, error)
+	// UpdateSettlement grava a tentativa somente se ela ainda estiver em expectedStatus
+	UpdateSettlement(ctx context.Context, settlement *Settlement, expectedStatus SettlementStatus) (bool, error)
+	// FindOverdueSettlements busca as tentativas aguardando pagamento, ou paradas numa
+	// captura, com o prazo vencido
+	FindOverdueSettlements(ctx context.Context, now time.Time) ([]Settlement, error)
+	// FindPendingExpiries busca as tentativas expiradas com ExpiryPending
+	FindPendingExpiries(ctx context.Context) ([]Settlement, error)
+	// FindPendingOffers busca as tentativas com OfferPending abertas até createdBefore
+	FindPendingOffers(ctx context.Context, createdBefore time.Time) ([]Settlement, error)
+}
+
+// SettlementOfferedListener é chamado quando uma tentativa de acerto é aberta. Se algum
+// retornar erro, a tentativa fica com a oferta pendente e todos são chamados de novo.
+type SettlementOfferedListener func(ctx context.Context, settlement Settlement) error
+
+// NewSettlement abre a tentativa de acerto com o autor do lance, que tem até paymentWindow
+// para pagar. Ela fica com OfferPending até a reserva e os avisos terminarem.
+func NewSettlement(sellerId string, bid Bid, attempt int, paymentWindow time.Duration) *Settlement {
+	now := time.Now().Truncate(time.Millisecond)
+	return &Settlement{
+		Id:              fmt.Sprintf("%s:%d", bid.AuctionId, attempt),
+		AuctionId:       bid.AuctionId,
+		Attempt:         attempt,
+		SellerId:        sellerId,
+		WinnerId:        bid.UserId,
+		BidId:           bid.Id,
+		Amount:          bid.Amount,
+		Status:          SettlementAwaitingPayment,
+		PaymentDeadline: now.Add(paymentWindow),
+		OfferPending:    true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// StartPayment inicia o pagamento do vencedor dentro do prazo; o acerto fica em capturing
+// até o valor ser capturado na carteira
+func (s *Settlement) StartPayment(userId string, at time.Time) error {
+	if userId != s.WinnerId {
+		return ErrNotSettlementParty.WithMessage("only the winner can pay for the auction")
+	}
+	if err := s.requireStatus(SettlementCapturing, SettlementAwaitingPayment); err != nil {
+		return err
+	}
+	if at.After(s.PaymentDeadline) {
+		return ErrPaymentDeadlinePassed.WithMessage("payment deadline was %s", s.PaymentDeadline.Format(time.RFC3339))
+	}
+	s.moveTo(SettlementCapturing, at)
+	return nil
+}
+
+// CompletePayment registra o pagamento cujo valor já foi capturado
+func (s *Settlement) CompletePayment(at time.Time) error {
+	if err := s.requireStatus(SettlementPaid, SettlementCapturing); err != nil {
+		return err
+	}
+	s.moveTo(SettlementPaid, at)
+	return nil
+}
+
+// CancelPayment volta a aguardar o pagamento quando a captura falhou
+func (s *Settlement) CancelPayment(at time.Time) error {
+	if err := s.requireStatus(SettlementAwaitingPayment, SettlementCapturing); err != nil {
+		return err
+	}
+	s.moveTo(SettlementAwaitingPayment, at)
+	return nil
+}
+
+// Ship registra o envio pelo vendedor; o código de rastreio é opcional
+func (s *Settlement) Ship(userId, trackingCode string, at time.Time) error {
+	if s.SellerId == "" || userId != s.SellerId {
+		return ErrNotSettlementParty.WithMessage("only the seller can ship the item")
+	}
+	if err := s.requireStatus(SettlementShipped, SettlementPaid); err != nil {
+		return err
+	}
+	s.TrackingCode = trackingCode
+	s.moveTo(SettlementShipped, at)
+	return nil
+}
+
+// ConfirmDelivery registra o recebimento pelo vencedor e conclui o acerto
+func (s *Settlement) ConfirmDelivery(userId string, at time.Time) error {
+	if userId != s.WinnerId {
+		return ErrNotSettlementParty.WithMessage("only the winner can confirm the delivery")
+	}
+	if err := s.requireStatus(SettlementDelivered, SettlementShipped); err != nil {
+		return err
+	}
+	s.moveTo(SettlementDelivered, at)
+	return nil
+}
+
+// Dispute abre uma disputa de uma das partes depois do pagamento e antes da entrega
+func (s *Settlement) Dispute(userId, reason string, at time.Time) error {
+	if userId != s.WinnerId && (s.SellerId == "" || userId != s.SellerId) {
+		return ErrNotSettlementParty.WithMessage("only the winner or the seller can open a dispute")
+	}
+	if err := s.requireStatus(SettlementDisputed, SettlementPaid, SettlementShipped); err != nil {
+		return err
+	}
+	s.DisputeReason = reason
+	s.DisputedBy = userId
+	s.moveTo(SettlementDisputed, at)
+	return nil
+}
+
+// Expire encerra a tentativa cujo prazo de pagamento terminou, inclusive a que parou no meio
+// de uma captura
+func (s *Settlement) Expire(at time.Time) error {
+	if err := s.requireStatus(SettlementExpired, SettlementAwaitingPayment, SettlementCapturing); err != nil {
+		return err
+	}
+	if !at.After(s.PaymentDeadline) {
+		return ErrInvalidSettlementTransition.WithMessage("payment deadline is %s", s.PaymentDeadline.Format(time.RFC3339))
+	}
+	s.ExpiryPending = true
+	s.moveTo(SettlementExpired, at)
+	return nil
+}
+
+// CompleteExpiry registra que a tentativa expirada já foi estornada e, se era o caso,
+// passada ao próximo licitante
+func (s *Settlement) CompleteExpiry(at time.Time) error {
+	if err := s.requireStatus(SettlementExpired, SettlementExpired); err != nil {
+		return err
+	}
+	s.ExpiryPending = false
+	s.UpdatedAt = at.Truncate(time.Millisecond)
+	return nil
+}
+
+// CompleteOffer registra que a reserva e os avisos da tentativa aberta terminaram
+func (s *Settlement) CompleteOffer(at time.Time) {
+	s.OfferPending = false
+	s.UpdatedAt = at.Truncate(time.Millisecond)
+}
+
+func (s *Settlement) requireStatus(target SettlementStatus, allowed ...SettlementStatus) error {
+	for _, status := range allowed {
+		if s.Status == status {
+			return nil
+		}
+	}
+	return ErrInvalidSettlementTransition.WithMessage("settlement cannot move from %s to %s", s.Status, target)
+}
+
+func (s *Settlement) moveTo(status SettlementStatus, at time.Time) {
+	s.Status = status
+	s.UpdatedAt = at.Truncate(time.Millisecond)
+}
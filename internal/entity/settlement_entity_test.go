@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSettlement(t *testing.T) *Settlement {
+	bid, err := CreateBid("winner", "auction-1", NewMoney(1500, BRL))
+	require.Nil(t, err)
+	return NewSettlement("seller", *bid, 1, time.Hour)
+}
+
+func TestSettlementHappyPath(t *testing.T) {
+	settlement := newTestSettlement(t)
+	assert.Equal(t, "auction-1:1", settlement.Id)
+	assert.Equal(t, SettlementAwaitingPayment, settlement.Status)
+
+	now := time.Now()
+	assert.ErrorIs(t, settlement.StartPayment("seller", now), ErrNotSettlementParty)
+	assert.ErrorIs(t, settlement.Ship("seller", "BR123", now), ErrInvalidSettlementTransition)
+
+	require.Nil(t, settlement.StartPayment("winner", now))
+	assert.Equal(t, SettlementCapturing, settlement.Status)
+	assert.ErrorIs(t, settlement.Ship("seller", "BR123", now), ErrInvalidSettlementTransition)
+	require.Nil(t, settlement.CompletePayment(now))
+	assert.ErrorIs(t, settlement.Ship("winner", "BR123", now), ErrNotSettlementParty)
+	require.Nil(t, settlement.Ship("seller", "BR123", now))
+	assert.Equal(t, "BR123", settlement.TrackingCode)
+	require.Nil(t, settlement.ConfirmDelivery("winner", now))
+	assert.Equal(t, SettlementDelivered, settlement.Status)
+
+	// Depois da entrega não há disputa nem outra etapa
+	assert.ErrorIs(t, settlement.Dispute("winner", "broken item", now), ErrInvalidSettlementTransition)
+}
+
+func TestSettlementPaymentDeadline(t *testing.T) {
+	settlement := newTestSettlement(t)
+
+	assert.ErrorIs(t, settlement.Expire(settlement.PaymentDeadline), ErrInvalidSettlementTransition)
+
+	late := settlement.PaymentDeadline.Add(time.Millisecond)
+	assert.ErrorIs(t, settlement.StartPayment("winner", late), ErrPaymentDeadlinePassed)
+	require.Nil(t, settlement.Expire(late))
+	assert.Equal(t, SettlementExpired, settlement.Status)
+	assert.True(t, settlement.ExpiryPending)
+	assert.ErrorIs(t, settlement.StartPayment("winner", time.Now()), ErrInvalidSettlementTransition)
+
+	require.Nil(t, settlement.CompleteExpiry(time.Now()))
+	assert.False(t, settlement.ExpiryPending)
+}
+
+func TestSettlementCancelledCaptureAwaitsPaymentAgain(t *testing.T) {
+	settlement := newTestSettlement(t)
+
+	require.Nil(t, settlement.StartPayment("winner", time.Now()))
+	require.Nil(t, settlement.CancelPayment(time.Now()))
+	assert.Equal(t, SettlementAwaitingPayment, settlement.Status)
+	assert.ErrorIs(t, settlement.CompletePayment(time.Now()), ErrInvalidSettlementTransition)
+
+	// Uma captura parada depois do prazo também expira
+	require.Nil(t, settlement.StartPayment("winner", time.Now()))
+	require.Nil(t, settlement.Expire(settlement.PaymentDeadline.Add(time.Millisecond)))
+	assert.Equal(t, SettlementExpired, settlement.Status)
+}
+
+func TestSettlementDispute(t *testing.T) {
+	settlement := newTestSettlement(t)
+	assert.ErrorIs(t, settlement.Dispute("winner", "seller is not answering", time.Now()), ErrInvalidSettlementTransition)
+
+	require.Nil(t, settlement.StartPayment("winner", time.Now()))
+	require.Nil(t, settlement.CompletePayment(time.Now()))
+	assert.ErrorIs(t, settlement.Dispute("someone", "spam", time.Now()), ErrNotSettlementParty)
+	require.Nil(t, settlement.Dispute("seller", "payment was reversed", time.Now()))
+	assert.Equal(t, SettlementDisputed, settlement.Status)
+	assert.Equal(t, "seller", settlement.DisputedBy)
+}
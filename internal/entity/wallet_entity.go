@@ -0,0 +1,244 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+// LedgerTransactionType é a operação registrada numa transação do livro-razão
+type LedgerTransactionType string
+
+const (
+	LedgerDeposit LedgerTransactionType = "deposit"
+	LedgerHold    LedgerTransactionType = "hold"
+	LedgerRelease LedgerTransactionType = "release"
+	LedgerCapture LedgerTransactionType = "capture"
+	LedgerRefund  LedgerTransactionType = "refund"
+)
+
+var (
+	ErrInsufficientFunds    = NewDomainError(InsufficientFundsError, "insufficient_funds", "available balance does not cover the bid")
+	ErrInvalidDepositAmount = NewDomainError(InvalidInputError, "invalid_deposit_amount", "deposit amount must be greater than zero")
+	ErrWalletConflict       = NewDomainError(ConflictError, "wallet_conflict", "wallet was modified concurrently, retry the request")
+)
+
+// AvailableAccount é a conta com o saldo livre do usuário na moeda
+func AvailableAccount(userId string, currency Currency) string {
+	return "wallet:" + userId + ":" + string(currency) + ":available"
+}
+
+// HeldAccount é a conta com o saldo reservado pelos lances do usuário na moeda
+func HeldAccount(userId string, currency Currency) string {
+	return "wallet:" + userId + ":" + string(currency) + ":held"
+}
+
+// DepositsAccount é a contrapartida dos depósitos, o dinheiro que entrou de fora da plataforma
+func DepositsAccount(currency Currency) string {
+	return "external:deposits:" + string(currency)
+}
+
+// CapturedAccount guarda os valores capturados dos vencedores até o repasse ao vendedor
+func CapturedAccount(currency Currency) string {
+	return "escrow:captured:" + string(currency)
+}
+
+// LedgerEntry é um lançamento numa conta; a soma dos lançamentos de uma transação é zero
+type LedgerEntry struct {
+	Account string
+	Amount  int64
+}
+
+// Hold é a reserva de um usuário num leilão, que cobre o lance BidId. Cada usuário tem no
+// máximo uma reserva por leilão: um lance novo substitui a reserva do anterior.
+type Hold struct {
+	BidId  string
+	Amount int64
+}
+
+// Wallet é o saldo de um usuário numa moeda, conforme a última transação do livro-razão.
+// Sequence é o número dessa transação; a próxima usa Sequence+1.
+type Wallet struct {
+	UserId    string
+	Currency  Currency
+	Sequence  int64
+	Available Money
+	Held      Money
+}
+
+// LedgerTransaction é uma transação do livro-razão de partidas dobradas. Ela é gravada num
+// único documento, com os lançamentos e os saldos resultantes, então é aplicada por inteiro
+// ou não é aplicada. Hold e PreviousHold são a reserva do usuário no leilão depois e antes
+// da transação.
+type LedgerTransaction struct {
+	Id           string
+	UserId       string
+	Currency     Currency
+	Sequence     int64
+	Type         LedgerTransactionType
+	AuctionId    string
+	BidId        string
+	Entries      []LedgerEntry
+	Available    Money
+	Held         Money
+	Hold         Hold
+	PreviousHold Hold
+	CreatedAt    time.Time
+}
+
+type LedgerEntryEntityMongo struct {
+	Account string `bson:"account"`
+	Amount  int64  `bson:"amount"`
+}
+
+// LedgerTransactionEntityMongo grava os valores em centavos e o horário em milissegundos
+// Unix. O índice único em (wallet_id, sequence) impede duas transações com a mesma
+// sequência na carteira.
+type LedgerTransactionEntityMongo struct {
+	Id                 string                   `bson:"_id"`
+	WalletId           string                   `bson:"wallet_id"`
+	UserId             string                   `bson:"user_id"`
+	Currency           Currency                 `bson:"currency"`
+	Sequence           int64                    `bson:"sequence"`
+	Type               LedgerTransactionType    `bson:"type"`
+	AuctionId          string                   `bson:"auction_id,omitempty"`
+	BidId              string                   `bson:"bid_id,omitempty"`
+	Entries            []LedgerEntryEntityMongo `bson:"entries"`
+	Available          int64                    `bson:"available"`
+	Held               int64                    `bson:"held"`
+	HoldBidId          string                   `bson:"hold_bid_id,omitempty"`
+	HoldAmount         int64                    `bson:"hold_amount"`
+	PreviousHoldBidId  string                   `bson:"previous_hold_bid_id,omitempty"`
+	PreviousHoldAmount int64                    `bson:"previous_hold_amount"`
+	CreatedAt          int64                    `bson:"created_at"`
+}
+
+// WalletId identifica a carteira do usuário na moeda
+func WalletId(userId string, currency Currency) string {
+	return userId + ":" + string(currency)
+}
+
+type WalletRepositoryInterface interface {
+	Deposit(ctx context.Context, userId string, amount Money) (*LedgerTransaction, error)
+	// HoldBid reserva o valor do lance, descontando a reserva que o usuário já tem no leilão;
+	// retorna ErrInsufficientFunds se o saldo disponível não cobrir a diferença
+	HoldBid(ctx context.Context, bid Bid) error
+	// RevertBidHold desfaz a reserva de um lance que não foi gravado
+	RevertBidHold(ctx context.Context, bid Bid) error
+	// HoldSettlement passa a reserva do comprador da tentativa de acerto para o lance dela;
+	// retorna ErrInsufficientFunds se o saldo disponível não cobrir a diferença
+	HoldSettlement(ctx context.Context, settlement Settlement) error
+	// CaptureSettlement reserva, se preciso, e captura o valor do pagamento da tentativa
+	CaptureSettlement(ctx context.Context, settlement Settlement) error
+	// RefundSettlement libera a reserva da tentativa encerrada sem venda e devolve o valor
+	// que tiver sido capturado
+	RefundSettlement(ctx context.Context, settlement Settlement) error
+	FindWallets(ctx context.Context, userId string) ([]Wallet, error)
+	FindLedger(ctx context.Context, userId string, limit int64) ([]LedgerTransaction, error)
+}
+
+// Deposit credita o valor no saldo disponível
+func (w Wallet) Deposit(amount Money, at time.Time) (*LedgerTransaction, error) {
+	if !amount.IsPositive() {
+		return nil, ErrInvalidDepositAmount
+	}
+	return w.transaction(LedgerDeposit, "", "", amount.Cents, 0, DepositsAccount(w.Currency), at), nil
+}
+
+// HoldBid passa a reserva do usuário no leilão para o lance. Só a diferença para a reserva
+// atual sai do saldo disponível.
+func (w Wallet) HoldBid(bid Bid, current Hold, at time.Time) (*LedgerTransaction, error) {
+	return w.moveHold(LedgerHold, bid.AuctionId, bid.Id, current, Hold{BidId: bid.Id, Amount: bid.Amount.Cents}, at)
+}
+
+// RestoreHold volta a reserva do leilão para restored, se ela ainda cobre o lance bidId.
+// Retorna nil se outro lance ou uma liberação já mudou a reserva.
+func (w Wallet) RestoreHold(auctionId, bidId string, current, restored Hold, at time.Time) *LedgerTransaction {
+	if current.BidId != bidId {
+		return nil
+	}
+	transaction, _ := w.moveHold(LedgerRelease, auctionId, bidId, current, restored, at)
+	return transaction
+}
+
+// ReleaseHold devolve a reserva do leilão ao saldo disponível se ela ainda cobre o lance
+// bidId, ou qualquer que seja o lance quando bidId é vazio. Retorna nil se não há o que
+// liberar.
+func (w Wallet) ReleaseHold(auctionId, bidId string, current Hold, at time.Time) *LedgerTransaction {
+	if current.Amount == 0 || (bidId != "" && current.BidId != bidId) {
+		return nil
+	}
+	transaction, _ := w.moveHold(LedgerRelease, auctionId, current.BidId, current, Hold{}, at)
+	return transaction
+}
+
+// HoldSettlement passa a reserva do usuário no leilão para o lance e o valor da tentativa de
+// acerto. Retorna nil se a reserva já cobre a tentativa.
+func (w Wallet) HoldSettlement(settlement Settlement, current Hold, at time.Time) (*LedgerTransaction, error) {
+	target := Hold{BidId: settlement.BidId, Amount: settlement.Amount.Cents}
+	if current == target {
+		return nil, nil
+	}
+	return w.moveHold(LedgerHold, settlement.AuctionId, settlement.BidId, current, target, at)
+}
+
+// RefundCapture devolve ao saldo disponível o valor capturado do lance bidId
+func (w Wallet) RefundCapture(auctionId, bidId string, amount int64, at time.Time) *LedgerTransaction {
+	if amount == 0 {
+		return nil
+	}
+	return w.transaction(LedgerRefund, auctionId, bidId, amount, 0, CapturedAccount(w.Currency), at)
+}
+
+// CaptureHold converte a reserva do leilão em captura: o valor sai do saldo reservado para a
+// conta de valores capturados. Retorna nil se não há reserva.
+func (w Wallet) CaptureHold(auctionId string, current Hold, at time.Time) *LedgerTransaction {
+	if current.Amount == 0 {
+		return nil
+	}
+	transaction := w.transaction(LedgerCapture, auctionId, current.BidId, 0, -current.Amount, CapturedAccount(w.Currency), at)
+	transaction.PreviousHold = current
+	return transaction
+}
+
+// moveHold troca a reserva current por target, movendo a diferença entre o saldo disponível e
+// o reservado
+func (w Wallet) moveHold(txType LedgerTransactionType, auctionId, bidId string, current, target Hold, at time.Time) (*LedgerTransaction, error) {
+	increase := target.Amount - current.Amount
+	if increase > w.Available.Cents {
+		return nil, ErrInsufficientFunds.WithMessage("available balance of %s %s does not cover the %s %s to be held",
+			w.Available, w.Currency, NewMoney(increase, w.Currency), w.Currency)
+	}
+
+	transaction := w.transaction(txType, auctionId, bidId, -increase, increase, "", at)
+	transaction.Hold = target
+	transaction.PreviousHold = current
+	return transaction, nil
+}
+
+// transaction monta a próxima transação da carteira com os lançamentos nas contas do usuário
+// e, se eles não se anularem, a contrapartida em counterAccount
+func (w Wallet) transaction(txType LedgerTransactionType, auctionId, bidId string, availableDelta, heldDelta int64, counterAccount string, at time.Time) *LedgerTransaction {
+	var entries []LedgerEntry
+	if availableDelta != 0 {
+		entries = append(entries, LedgerEntry{Account: AvailableAccount(w.UserId, w.Currency), Amount: availableDelta})
+	}
+	if heldDelta != 0 {
+		entries = append(entries, LedgerEntry{Account: HeldAccount(w.UserId, w.Currency), Amount: heldDelta})
+	}
+	if counter := -(availableDelta + heldDelta); counter != 0 {
+		entries = append(entries, LedgerEntry{Account: counterAccount, Amount: counter})
+	}
+
+	return &LedgerTransaction{
+		UserId:    w.UserId,
+		Currency:  w.Currency,
+		Sequence:  w.Sequence + 1,
+		Type:      txType,
+		AuctionId: auctionId,
+		BidId:     bidId,
+		Entries:   entries,
+		Available: NewMoney(w.Available.Cents+availableDelta, w.Currency),
+		Held:      NewMoney(w.Held.Cents+heldDelta, w.Currency),
+		CreatedAt: at.Truncate(time.Millisecond),
+	}
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletHoldBidHoldsOnlyTheIncrement(t *testing.T) {
+	now := time.Now()
+	wallet := Wallet{UserId: "user-1", Currency: BRL, Sequence: 3, Available: NewMoney(10000, BRL), Held: NewMoney(4000, BRL)}
+	bid := Bid{Id: "bid-2", UserId: "user-1", AuctionId: "auction-1", Amount: NewMoney(6000, BRL)}
+
+	transaction, err := wallet.HoldBid(bid, Hold{BidId: "bid-1", Amount: 4000}, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4), transaction.Sequence)
+	assert.Equal(t, NewMoney(8000, BRL), transaction.Available)
+	assert.Equal(t, NewMoney(6000, BRL), transaction.Held)
+	assert.Equal(t, Hold{BidId: "bid-2", Amount: 6000}, transaction.Hold)
+	assert.Equal(t, []LedgerEntry{
+		{Account: AvailableAccount("user-1", BRL), Amount: -2000},
+		{Account: HeldAccount("user-1", BRL), Amount: 2000},
+	}, transaction.Entries)
+
+	_, err = wallet.HoldBid(Bid{Id: "bid-3", AuctionId: "auction-1", Amount: NewMoney(14001, BRL)}, Hold{BidId: "bid-1", Amount: 4000}, now)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestWalletReleaseAndCaptureBalanceTheEntries(t *testing.T) {
+	now := time.Now()
+	wallet := Wallet{UserId: "user-1", Currency: BRL, Sequence: 1, Available: NewMoney(1000, BRL), Held: NewMoney(5000, BRL)}
+	current := Hold{BidId: "bid-1", Amount: 5000}
+
+	// Uma liberação de outro lance não mexe na reserva atual
+	assert.Nil(t, wallet.ReleaseHold("auction-1", "bid-0", current, now))
+
+	release := wallet.ReleaseHold("auction-1", "bid-1", current, now)
+	require.NotNil(t, release)
+	assert.Equal(t, NewMoney(6000, BRL), release.Available)
+	assert.Equal(t, NewMoney(0, BRL), release.Held)
+	assert.Equal(t, Hold{}, release.Hold)
+
+	capture := wallet.CaptureHold("auction-1", current, now)
+	require.NotNil(t, capture)
+	assert.Equal(t, NewMoney(1000, BRL), capture.Available)
+	assert.Equal(t, NewMoney(0, BRL), capture.Held)
+	assert.Equal(t, []LedgerEntry{
+		{Account: HeldAccount("user-1", BRL), Amount: -5000},
+		{Account: CapturedAccount(BRL), Amount: 5000},
+	}, capture.Entries)
+
+	deposit, err := wallet.Deposit(NewMoney(2500, BRL), now)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(3500, BRL), deposit.Available)
+	assert.Equal(t, int64(0), deposit.Entries[0].Amount+deposit.Entries[1].Amount)
+
+	_, err = wallet.Deposit(NewMoney(0, BRL), now)
+	assert.ErrorIs(t, err, ErrInvalidDepositAmount)
+}
+
+func TestWalletHoldSettlementAndRefundCapture(t *testing.T) {
+	now := time.Now()
+	wallet := Wallet{UserId: "user-1", Currency: BRL, Sequence: 2, Available: NewMoney(9000, BRL), Held: NewMoney(0, BRL)}
+	settlement := Settlement{AuctionId: "auction-1", BidId: "bid-1", Amount: NewMoney(8000, BRL)}
+
+	hold, err := wallet.HoldSettlement(settlement, Hold{}, now)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(1000, BRL), hold.Available)
+	assert.Equal(t, Hold{BidId: "bid-1", Amount: 8000}, hold.Hold)
+
+	// A reserva que já cobre a tentativa não gera transação
+	hold, err = wallet.HoldSettlement(settlement, Hold{BidId: "bid-1", Amount: 8000}, now)
+	require.NoError(t, err)
+	assert.Nil(t, hold)
+
+	refund := wallet.RefundCapture("auction-1", "bid-1", 8000, now)
+	require.NotNil(t, refund)
+	assert.Equal(t, NewMoney(17000, BRL), refund.Available)
+	assert.Equal(t, []LedgerEntry{
+		{Account: AvailableAccount("user-1", BRL), Amount: 8000},
+		{Account: CapturedAccount(BRL), Amount: -8000},
+	}, refund.Entries)
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+type WatchlistItem struct {
+	UserId    string
+	AuctionId string
+	Timestamp time.Time
+}
+
+type WatchlistItemEntityMongo struct {
+	Id        string `bson:"_id"`
+	UserId    string `bson:"user_id"`
+	AuctionId string `bson:"auction_id"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+type WatchlistRepositoryInterface interface {
+	AddToWatchlist(ctx context.Context, item *WatchlistItem) error
+	RemoveFromWatchlist(ctx context.Context, userId, auctionId string) (bool, error)
+	FindWatchlistByUserId(ctx context.Context, userId string) ([]WatchlistItem, error)
+	FindWatchersByAuctionIds(ctx context.Context, auctionIds []string) ([]WatchlistItem, error)
+}
+
+func CreateWatchlistItem(userId, auctionId string) (*WatchlistItem, error) {
+	item := &WatchlistItem{
+		UserId:    userId,
+		AuctionId: auctionId,
+		Timestamp: time.Now(),
+	}
+
+	return item, nil
+}
@@ -0,0 +1,193 @@
+package auction_service
+
+import (
+	"context"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/grpc/pb"
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/auction-goexpert/internal/usecase/auction_usecase"
+	"github.com/auction-goexpert/internal/usecase/bid_usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuctionServer implementa o AuctionService gRPC sobre os mesmos use cases da API HTTP
+type AuctionServer struct {
+	pb.UnimplementedAuctionServiceServer
+
+	createAuctionUseCase *auction_usecase.CreateAuctionUseCase
+	findAuctionUseCase   *auction_usecase.FindAuctionUseCase
+	createBidUseCase     *bid_usecase.CreateBidUseCase
+	findBidUseCase       *bid_usecase.FindBidUseCase
+	watchHub             *WatchHub
+}
+
+func NewAuctionServer(
+	createAuctionUseCase *auction_usecase.CreateAuctionUseCase,
+	findAuctionUseCase *auction_usecase.FindAuctionUseCase,
+	createBidUseCase *bid_usecase.CreateBidUseCase,
+	findBidUseCase *bid_usecase.FindBidUseCase,
+	watchHub *WatchHub,
+) *AuctionServer {
+	return &AuctionServer{
+		createAuctionUseCase: createAuctionUseCase,
+		findAuctionUseCase:   findAuctionUseCase,
+		createBidUseCase:     createBidUseCase,
+		findBidUseCase:       findBidUseCase,
+		watchHub:             watchHub,
+	}
+}
+
+func (as *AuctionServer) CreateAuction(ctx context.Context, request *pb.CreateAuctionRequest) (*pb.Auction, error) {
+	input := auction_usecase.AuctionInputDTO{
+		ProductName: request.GetProductName(),
+		Category:    request.GetCategory(),
+		Description: request.GetDescription(),
+		Condition:   entity.ProductCondition(request.GetCondition()),
+		SellerId:    request.GetSellerId(),
+		Currency:    entity.Currency(request.GetCurrency()),
+	}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	if reservePrice := request.GetReservePrice(); reservePrice != "" {
+		amount, err := entity.ParseMoney(reservePrice, input.Currency.OrDefault())
+		if err != nil {
+			return nil, toStatus(internal_error.FromError(err))
+		}
+		input.ReservePrice = amount
+	}
+
+	output, internalErr := as.createAuctionUseCase.Execute(ctx, input)
+	if internalErr != nil {
+		return nil, toStatus(internalErr)
+	}
+
+	return toAuction(*output), nil
+}
+
+func (as *AuctionServer) FindAuction(ctx context.Context, request *pb.FindAuctionRequest) (*pb.Auction, error) {
+	output, internalErr := as.findAuctionUseCase.FindAuctionById(ctx, request.GetId())
+	if internalErr != nil {
+		return nil, toStatus(internalErr)
+	}
+
+	return toAuction(*output), nil
+}
+
+func (as *AuctionServer) FindAuctions(ctx context.Context, request *pb.FindAuctionsRequest) (*pb.FindAuctionsResponse, error) {
+	var auctionStatus entity.AuctionStatus = -1
+	if request.Status != nil {
+		auctionStatus = entity.AuctionStatus(request.GetStatus())
+	}
+
+	displayCurrency := entity.Currency(request.GetDisplayCurrency())
+	if displayCurrency != "" && !displayCurrency.IsSupported() {
+		return nil, status.Error(codes.InvalidArgument, "bad_request: invalid display_currency, allowed values: BRL, USD")
+	}
+
+	page := toPageRequest(request.GetPage())
+	if page.Limit < 0 || page.Limit > entity.MaxPageLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "bad_request: limit must be a number between 1 and %d", entity.MaxPageLimit)
+	}
+
+	output, nextCursor, internalErr := as.findAuctionUseCase.FindAuctions(ctx, auctionStatus, request.GetCategory(), request.GetProductName(), displayCurrency, page)
+	if internalErr != nil {
+		return nil, toStatus(internalErr)
+	}
+
+	response := &pb.FindAuctionsResponse{NextCursor: nextCursor}
+	for _, auction := range output {
+		response.Auctions = append(response.Auctions, toAuction(auction))
+	}
+	return response, nil
+}
+
+func (as *AuctionServer) CreateBid(ctx context.Context, request *pb.CreateBidRequest) (*pb.Bid, error) {
+	amount, err := entity.ParseMoney(request.GetAmount(), "")
+	if err != nil {
+		return nil, toStatus(internal_error.FromError(err))
+	}
+
+	input := bid_usecase.BidInputDTO{
+		UserId:    request.GetUserId(),
+		AuctionId: request.GetAuctionId(),
+		Amount:    amount,
+		Currency:  entity.Currency(request.GetCurrency()),
+	}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	output, internalErr := as.createBidUseCase.Execute(ctx, input)
+	if internalErr != nil {
+		return nil, toStatus(internalErr)
+	}
+
+	return toBid(*output), nil
+}
+
+func (as *AuctionServer) ListBids(ctx context.Context, request *pb.ListBidsRequest) (*pb.ListBidsResponse, error) {
+	page := toPageRequest(request.GetPage())
+	if page.Limit < 0 || page.Limit > entity.MaxPageLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "bad_request: limit must be a number between 1 and %d", entity.MaxPageLimit)
+	}
+
+	output, nextCursor, internalErr := as.findBidUseCase.FindBidByAuctionId(ctx, request.GetAuctionId(), page)
+	if internalErr != nil {
+		return nil, toStatus(internalErr)
+	}
+
+	response := &pb.ListBidsResponse{NextCursor: nextCursor}
+	for _, bid := range output {
+		response.Bids = append(response.Bids, toBid(bid))
+	}
+	return response, nil
+}
+
+func (as *AuctionServer) GetWinner(ctx context.Context, request *pb.GetWinnerRequest) (*pb.Bid, error) {
+	output, internalErr := as.findBidUseCase.FindWinningBidByAuctionId(ctx, request.GetAuctionId())
+	if internalErr != nil {
+		return nil, toStatus(internalErr)
+	}
+
+	return toBid(*output), nil
+}
+
+func (as *AuctionServer) WatchAuction(request *pb.WatchAuctionRequest, stream pb.AuctionService_WatchAuctionServer) error {
+	ctx := stream.Context()
+
+	// O estado atual vem do stream de eventos, na versão a partir da qual o watcher recebe
+	// os eventos novos
+	w, auction, err := as.watchHub.subscribe(ctx, request.GetAuctionId())
+	if err != nil {
+		return toStatus(internal_error.FromError(err))
+	}
+	defer as.watchHub.unsubscribe(request.GetAuctionId(), w)
+
+	if auction.Status != entity.Active {
+		return stream.Send(&pb.AuctionEvent{Event: &pb.AuctionEvent_Closed{Closed: toAuctionFromEntity(auction)}})
+	}
+
+	if err := stream.Send(&pb.AuctionEvent{Event: &pb.AuctionEvent_Snapshot{Snapshot: toAuctionFromEntity(auction)}}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-w.lagging:
+			return status.Error(codes.ResourceExhausted, "watcher fell behind, reconnect to resume")
+		case event := <-w.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+			if _, closed := event.Event.(*pb.AuctionEvent_Closed); closed {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package auction_service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/grpc/pb"
+	"github.com/auction-goexpert/internal/usecase/auction_usecase"
+	"github.com/auction-goexpert/internal/usecase/bid_usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// memoryEventStore guarda os streams dos leilões, como o event store, para o WatchHub
+type memoryEventStore struct {
+	mu      sync.Mutex
+	streams map[string][]entity.AuctionEvent
+}
+
+// execute aplica o comando ao agregado e grava os eventos gerados
+func (m *memoryEventStore) execute(t *testing.T, auctionId string, command func(aggregate *entity.AuctionAggregate) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	aggregate, err := entity.LoadAuctionAggregate(m.streams[auctionId])
+	require.NoError(t, err)
+	require.NoError(t, command(aggregate))
+	m.streams[auctionId] = append(m.streams[auctionId], aggregate.Changes()...)
+}
+
+func (m *memoryEventStore) LoadAfter(ctx context.Context, auctionId string, version int64) ([]entity.AuctionEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []entity.AuctionEvent
+	for _, event := range m.streams[auctionId] {
+		if event.Version > version {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// memoryAuctionRepository implementa apenas o que os use cases usados aqui precisam
+type memoryAuctionRepository struct {
+	entity.AuctionRepositoryInterface
+
+	t        *testing.T
+	events   *memoryEventStore
+	mu       sync.Mutex
+	auctions map[string]entity.Auction
+}
+
+func (m *memoryAuctionRepository) CreateAuction(ctx context.Context, auction *entity.Auction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	auction.ExpiresAt = time.Now().Add(time.Hour)
+	m.auctions[auction.Id] = *auction
+	m.events.execute(m.t, auction.Id, func(aggregate *entity.AuctionAggregate) error {
+		return aggregate.Create(*auction)
+	})
+	return nil
+}
+
+func (m *memoryAuctionRepository) FindAuctionById(ctx context.Context, id string) (*entity.Auction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	auction, ok := m.auctions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &auction, nil
+}
+
+type memoryBidRepository struct {
+	entity.BidRepositoryInterface
+
+	mu       sync.Mutex
+	auctions *memoryAuctionRepository
+	bids     []entity.Bid
+}
+
+func (m *memoryBidRepository) CreateBid(ctx context.Context, bid *entity.Bid) error {
+	auction, _ := m.auctions.FindAuctionById(ctx, bid.AuctionId)
+	if auction == nil {
+		return entity.ErrAuctionNotFound
+	}
+
+	m.mu.Lock()
+	winner := m.winner(bid.AuctionId)
+	if winner != nil && !bid.Amount.GreaterThan(winner.Amount) {
+		m.mu.Unlock()
+		return entity.ErrBidTooLow
+	}
+	bid.Amount.Currency = auction.Currency
+	m.bids = append(m.bids, *bid)
+	m.mu.Unlock()
+
+	m.auctions.events.execute(m.auctions.t, bid.AuctionId, func(aggregate *entity.AuctionAggregate) error {
+		return aggregate.PlaceBid(bid)
+	})
+	return nil
+}
+
+func (m *memoryBidRepository) FindWinningBidByAuctionId(ctx context.Context, auctionId string) (*entity.Bid, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.winner(auctionId), nil
+}
+
+func (m *memoryBidRepository) winner(auctionId string) *entity.Bid {
+	var winner *entity.Bid
+	for i := range m.bids {
+		if m.bids[i].AuctionId == auctionId && (winner == nil || m.bids[i].Amount.GreaterThan(winner.Amount)) {
+			winner = &m.bids[i]
+		}
+	}
+	return winner
+}
+
+func newClient(t *testing.T, opts ...grpc.ServerOption) (pb.AuctionServiceClient, *memoryEventStore) {
+	events := &memoryEventStore{streams: make(map[string][]entity.AuctionEvent)}
+	auctionRepo := &memoryAuctionRepository{t: t, events: events, auctions: make(map[string]entity.Auction)}
+	bidRepo := &memoryBidRepository{auctions: auctionRepo}
+	watchHub := NewWatchHub(events, 10*time.Millisecond, slog.Default())
+	watchHub.Start()
+
+	server := grpc.NewServer(opts...)
+	pb.RegisterAuctionServiceServer(server, NewAuctionServer(
+		auction_usecase.NewCreateAuctionUseCase(auctionRepo),
+		auction_usecase.NewFindAuctionUseCase(auctionRepo, nil),
+		bid_usecase.NewCreateBidUseCase(bidRepo),
+		bid_usecase.NewFindBidUseCase(bidRepo),
+		watchHub,
+	))
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewAuctionServiceClient(conn), events
+}
+
+func createAuction(t *testing.T, client pb.AuctionServiceClient) *pb.Auction {
+	auction, err := client.CreateAuction(context.Background(), &pb.CreateAuctionRequest{
+		ProductName: "iPhone 13 Pro",
+		Category:    "Electronics",
+		Description: "Brand new iPhone 13 Pro with 256GB storage",
+		Condition:   pb.ProductCondition_PRODUCT_CONDITION_NEW,
+	})
+	require.Nil(t, err)
+	return auction
+}
+
+func TestCreateAndFindAuction(t *testing.T) {
+	client, _ := newClient(t)
+	ctx := context.Background()
+
+	created := createAuction(t, client)
+	assert.Equal(t, pb.AuctionStatus_AUCTION_STATUS_ACTIVE, created.Status)
+	assert.Equal(t, "0.00", created.CurrentPrice)
+
+	found, err := client.FindAuction(ctx, &pb.FindAuctionRequest{Id: created.Id})
+	require.Nil(t, err)
+	assert.Equal(t, created.ProductName, found.ProductName)
+
+	_, err = client.FindAuction(ctx, &pb.FindAuctionRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.CreateAuction(ctx, &pb.CreateAuctionRequest{ProductName: "x", Category: "y", Description: "short"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "validation_failed")
+}
+
+func TestCreateBidAndGetWinner(t *testing.T) {
+	client, _ := newClient(t)
+	ctx := context.Background()
+	auction := createAuction(t, client)
+
+	_, err := client.GetWinner(ctx, &pb.GetWinnerRequest{AuctionId: auction.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	bid, err := client.CreateBid(ctx, &pb.CreateBidRequest{UserId: "u1", AuctionId: auction.Id, Amount: "150.50"})
+	require.Nil(t, err)
+	assert.Equal(t, "150.50", bid.Amount)
+
+	_, err = client.CreateBid(ctx, &pb.CreateBidRequest{UserId: "u2", AuctionId: auction.Id, Amount: "150.50"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "bid_too_low")
+
+	_, err = client.CreateBid(ctx, &pb.CreateBidRequest{UserId: "u2", AuctionId: auction.Id, Amount: "1.234"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.CreateBid(ctx, &pb.CreateBidRequest{UserId: "u2", AuctionId: "missing", Amount: "10"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	winner, err := client.GetWinner(ctx, &pb.GetWinnerRequest{AuctionId: auction.Id})
+	require.Nil(t, err)
+	assert.Equal(t, bid.Id, winner.Id)
+}
+
+func TestWatchAuctionStreamsBidsUntilClosed(t *testing.T) {
+	client, events := newClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	auction := createAuction(t, client)
+
+	stream, err := client.WatchAuction(ctx, &pb.WatchAuctionRequest{AuctionId: auction.Id})
+	require.Nil(t, err)
+
+	event, err := stream.Recv()
+	require.Nil(t, err)
+	assert.Equal(t, auction.Id, event.GetSnapshot().GetId())
+
+	bid, err := client.CreateBid(ctx, &pb.CreateBidRequest{UserId: "u1", AuctionId: auction.Id, Amount: "99.90"})
+	require.Nil(t, err)
+
+	event, err = stream.Recv()
+	require.Nil(t, err)
+	assert.Equal(t, bid.Id, event.GetBidPlaced().GetId())
+
+	// A prorrogação chega como um novo snapshot
+	expiresAt := auction.ExpiresAt.AsTime().Add(time.Hour)
+	events.execute(t, auction.Id, func(aggregate *entity.AuctionAggregate) error {
+		return aggregate.Extend(expiresAt, time.Now())
+	})
+
+	event, err = stream.Recv()
+	require.Nil(t, err)
+	assert.Equal(t, expiresAt.UnixMilli(), event.GetSnapshot().GetExpiresAt().AsTime().UnixMilli())
+	assert.Equal(t, "99.90", event.GetSnapshot().GetCurrentPrice())
+
+	// O encerramento gravado só no stream, como o feito por outra réplica, também chega
+	events.execute(t, auction.Id, func(aggregate *entity.AuctionAggregate) error {
+		return aggregate.Close(time.Now())
+	})
+
+	event, err = stream.Recv()
+	require.Nil(t, err)
+	assert.Equal(t, pb.AuctionStatus_AUCTION_STATUS_COMPLETED, event.GetClosed().GetStatus())
+
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestWatchAuctionEndsOnCancellation(t *testing.T) {
+	client, events := newClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	auction := createAuction(t, client)
+
+	stream, err := client.WatchAuction(ctx, &pb.WatchAuctionRequest{AuctionId: auction.Id})
+	require.Nil(t, err)
+	_, err = stream.Recv()
+	require.Nil(t, err)
+
+	events.execute(t, auction.Id, func(aggregate *entity.AuctionAggregate) error {
+		return aggregate.Cancel("fraud")
+	})
+
+	event, err := stream.Recv()
+	require.Nil(t, err)
+	assert.Equal(t, pb.AuctionStatus_AUCTION_STATUS_CANCELLED, event.GetClosed().GetStatus())
+
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	missing, err := client.WatchAuction(ctx, &pb.WatchAuctionRequest{AuctionId: "missing"})
+	require.Nil(t, err)
+	_, err = missing.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,95 @@
+package auction_service
+
+import (
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/grpc/pb"
+	"github.com/auction-goexpert/internal/usecase/auction_usecase"
+	"github.com/auction-goexpert/internal/usecase/bid_usecase"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toAuction(auction auction_usecase.AuctionOutputDTO) *pb.Auction {
+	output := &pb.Auction{
+		Id:           auction.Id,
+		ProductName:  auction.ProductName,
+		Category:     auction.Category,
+		Description:  auction.Description,
+		Condition:    pb.ProductCondition(auction.Condition),
+		Status:       pb.AuctionStatus(auction.Status),
+		CurrentPrice: auction.CurrentPrice.String(),
+		Currency:     string(auction.Currency),
+		SellerId:     auction.SellerId,
+		Timestamp:    timestamppb.New(auction.Timestamp),
+		ExpiresAt:    timestamppb.New(auction.ExpiresAt),
+		ReserveMet:   auction.ReserveMet,
+	}
+
+	if converted := auction.ConvertedPrice; converted != nil {
+		output.ConvertedPrice = &pb.ConvertedPrice{
+			Amount:      converted.Amount.String(),
+			Currency:    string(converted.Currency),
+			Rate:        converted.Rate,
+			EffectiveAt: timestamppb.New(converted.EffectiveAt),
+		}
+	}
+
+	return output
+}
+
+// toAuctionFromEntity converte o leilão recebido pelos listeners do repositório
+func toAuctionFromEntity(auction entity.Auction) *pb.Auction {
+	return toAuction(auction_usecase.AuctionOutputDTO{
+		Id:           auction.Id,
+		ProductName:  auction.ProductName,
+		Category:     auction.Category,
+		Description:  auction.Description,
+		Condition:    auction.Condition,
+		Status:       auction.Status,
+		CurrentPrice: auction.CurrentPrice,
+		Currency:     auction.Currency,
+		SellerId:     auction.SellerId,
+		Timestamp:    auction.Timestamp,
+		ExpiresAt:    auction.ExpiresAt,
+		ReserveMet:   reserveMet(auction),
+	})
+}
+
+// reserveMet retorna nil para leilões sem preço de reserva
+func reserveMet(auction entity.Auction) *bool {
+	if !auction.HasReserve() {
+		return nil
+	}
+	met := auction.ReserveMet()
+	return &met
+}
+
+func toBid(bid bid_usecase.BidOutputDTO) *pb.Bid {
+	return &pb.Bid{
+		Id:        bid.Id,
+		UserId:    bid.UserId,
+		AuctionId: bid.AuctionId,
+		Amount:    bid.Amount.String(),
+		Currency:  string(bid.Currency),
+		Timestamp: timestamppb.New(bid.Timestamp),
+	}
+}
+
+func toBidFromEntity(bid entity.Bid) *pb.Bid {
+	return toBid(bid_usecase.BidOutputDTO{
+		Id:        bid.Id,
+		UserId:    bid.UserId,
+		AuctionId: bid.AuctionId,
+		Amount:    bid.Amount,
+		Currency:  bid.Amount.Currency,
+		Timestamp: bid.Timestamp,
+	})
+}
+
+func toPageRequest(page *pb.PageRequest) entity.PageRequest {
+	return entity.PageRequest{
+		Limit:  int(page.GetLimit()),
+		Cursor: page.GetCursor(),
+		SortBy: page.GetSort(),
+		Order:  entity.SortOrder(page.GetOrder()),
+	}
+}
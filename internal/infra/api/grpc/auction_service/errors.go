@@ -0,0 +1,54 @@
+package auction_service
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// codeByHttpStatus traduz o status HTTP do InternalError para o código gRPC equivalente
+var codeByHttpStatus = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.FailedPrecondition,
+	http.StatusUnprocessableEntity: codes.FailedPrecondition,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+}
+
+// toStatus converte o erro da aplicação em status gRPC. A mensagem leva o código
+// estável do erro ("bid_too_low: ..."); erros internos não expõem detalhes.
+func toStatus(internalErr *internal_error.InternalError) error {
+	code, ok := codeByHttpStatus[internalErr.Code]
+	if !ok {
+		return status.Error(codes.Internal, "internal_server_error: an unexpected error occurred")
+	}
+
+	return status.Error(code, internalErr.Err+": "+internalErr.Message)
+}
+
+// validate aplica as mesmas regras de binding dos DTOs usadas pela API HTTP
+func validate(input any) error {
+	err := binding.Validator.ValidateStruct(input)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return status.Error(codes.InvalidArgument, "validation_failed: "+err.Error())
+	}
+
+	fields := make([]string, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		fields = append(fields, fieldErr.Field()+" ("+fieldErr.Tag()+")")
+	}
+	return status.Error(codes.InvalidArgument, "validation_failed: invalid fields: "+strings.Join(fields, ", "))
+}
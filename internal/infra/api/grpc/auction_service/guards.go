@@ -0,0 +1,276 @@
+package auction_service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/grpc/pb"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// IdempotencyKeyMetadata é o equivalente gRPC do cabeçalho Idempotency-Key
+	IdempotencyKeyMetadata = "idempotency-key"
+	// ReplayedMetadata marca, nos metadados de resposta, uma resposta reenviada
+	ReplayedMetadata = "idempotent-replayed"
+	// RetryAfterMetadata informa em quantos segundos o cliente limitado pode repetir
+	RetryAfterMetadata = "retry-after"
+
+	maxIdempotencyKeyLength = 255
+	idempotencyLockDuration = time.Minute
+	protoContentType        = "application/grpc+proto"
+	errorContentType        = "text/plain"
+)
+
+// GuardPolicies são os limites de criação de leilões e lances, os mesmos da API HTTP
+type GuardPolicies struct {
+	AuctionIP   entity.RateLimitPolicy
+	AuctionUser entity.RateLimitPolicy
+	BidIP       entity.RateLimitPolicy
+	BidUser     entity.RateLimitPolicy
+}
+
+// guardedMethod descreve um RPC de criação: a operação ("auction" ou "bid"), que prefixa os
+// buckets e as chaves de idempotência, os limites, como achar o usuário na requisição e o
+// tipo da resposta reenviada
+type guardedMethod struct {
+	name       string
+	ipPolicy   entity.RateLimitPolicy
+	userPolicy entity.RateLimitPolicy
+	user       func(request any) string
+	response   func() proto.Message
+}
+
+// Guards aplica a CreateAuction e CreateBid os limites de requisições e a idempotência da
+// API HTTP. Os buckets ("bid:user:<id>", "auction:ip:<ip>"...) são os mesmos, então um
+// usuário não ganha limite extra alternando entre HTTP e gRPC. As chaves de idempotência
+// também são as da API HTTP ("bid:<usuário>:<chave>"): a mesma chave usada nos dois
+// protocolos conta como outra requisição e é recusada.
+type Guards struct {
+	limiter    entity.RateLimiterInterface
+	repository entity.IdempotencyRepositoryInterface
+	ttl        time.Duration
+	methods    map[string]guardedMethod
+	logger     *slog.Logger
+}
+
+func NewGuards(
+	limiter entity.RateLimiterInterface,
+	repository entity.IdempotencyRepositoryInterface,
+	policies GuardPolicies,
+	ttl time.Duration,
+	logger *slog.Logger,
+) *Guards {
+	return &Guards{
+		limiter:    limiter,
+		repository: repository,
+		ttl:        ttl,
+		methods: map[string]guardedMethod{
+			pb.AuctionService_CreateAuction_FullMethodName: {
+				name:       "auction",
+				ipPolicy:   policies.AuctionIP,
+				userPolicy: policies.AuctionUser,
+				user:       func(request any) string { return request.(*pb.CreateAuctionRequest).GetSellerId() },
+				response:   func() proto.Message { return &pb.Auction{} },
+			},
+			pb.AuctionService_CreateBid_FullMethodName: {
+				name:       "bid",
+				ipPolicy:   policies.BidIP,
+				userPolicy: policies.BidUser,
+				user:       func(request any) string { return request.(*pb.CreateBidRequest).GetUserId() },
+				response:   func() proto.Message { return &pb.Bid{} },
+			},
+		},
+		logger: logger,
+	}
+}
+
+// UnaryInterceptor aplica, nesta ordem, o limite por IP, o limite por usuário e a
+// idempotência; os demais RPCs passam direto
+func (g *Guards) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method, ok := g.methods[info.FullMethod]
+		if !ok {
+			return handler(ctx, request)
+		}
+
+		user := method.user(request)
+		if err := g.take(ctx, method.name+":ip", method.ipPolicy, clientIP(ctx)); err != nil {
+			return nil, err
+		}
+		if err := g.take(ctx, method.name+":user", method.userPolicy, user); err != nil {
+			return nil, err
+		}
+
+		return g.idempotent(ctx, method.name, info.FullMethod, user, request, method.response, handler)
+	}
+}
+
+// take consome um token do bucket da identidade. Se o backend falhar a requisição segue
+// sem limite, como na API HTTP.
+func (g *Guards) take(ctx context.Context, name string, policy entity.RateLimitPolicy, identity string) error {
+	if !policy.Enabled() || identity == "" {
+		return nil
+	}
+
+	result, err := g.limiter.Take(ctx, name+":"+identity, policy)
+	if err != nil {
+		g.logger.ErrorContext(ctx, "Error checking rate limit", slog.String("limit", name), logging.Err(err))
+		return nil
+	}
+
+	if !result.Allowed {
+		retryAfter := int64(math.Ceil(result.RetryAfter.Seconds()))
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RetryAfterMetadata, strconv.FormatInt(retryAfter, 10)))
+		return toStatus(&internal_error.InternalError{
+			Message: "too many requests, retry later",
+			Err:     "rate_limited",
+			Code:    http.StatusTooManyRequests,
+		})
+	}
+	return nil
+}
+
+// idempotent executa o RPC uma única vez por chave. A resposta (ou o erro de negócio) é
+// guardada e reenviada nas repetições; erros internos liberam a chave para nova tentativa.
+func (g *Guards) idempotent(ctx context.Context, operation, fullMethod, user string, request any, response func() proto.Message, handler grpc.UnaryHandler) (any, error) {
+	key := firstMetadata(ctx, IdempotencyKeyMetadata)
+	if key == "" {
+		return handler(ctx, request)
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, toStatus(internal_error.NewBadRequestError("idempotency-key must have at most 255 characters"))
+	}
+
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(request.(proto.Message))
+	if err != nil {
+		return nil, toStatus(internal_error.NewBadRequestError("could not read request"))
+	}
+
+	now := time.Now()
+	record := entity.IdempotencyRecord{
+		Id:          operation + ":" + user + ":" + key,
+		Owner:       uuid.New().String(),
+		RequestHash: requestHash(fullMethod, body),
+		LockedUntil: now.Add(idempotencyLockDuration),
+		ExpiresAt:   now.Add(g.ttl),
+	}
+
+	existing, err := g.repository.Reserve(ctx, record)
+	if err != nil {
+		return nil, toStatus(internal_error.FromError(err))
+	}
+	if existing != nil {
+		return replay(ctx, record, existing, response)
+	}
+
+	result, handlerErr := handler(ctx, request)
+
+	// Usa um contexto próprio para gravar o resultado mesmo se o cliente desconectar
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	switch code := status.Code(handlerErr); {
+	case retryable(code):
+		if err := g.repository.Release(storeCtx, record.Id, record.Owner); err != nil {
+			g.logger.ErrorContext(ctx, "Error releasing idempotency key", slog.String("idempotency_key", record.Id), logging.Err(err))
+		}
+	case handlerErr != nil:
+		err = g.repository.Complete(storeCtx, record.Id, record.Owner, int(code), errorContentType, []byte(status.Convert(handlerErr).Message()))
+	default:
+		var stored []byte
+		if stored, err = proto.Marshal(result.(proto.Message)); err == nil {
+			err = g.repository.Complete(storeCtx, record.Id, record.Owner, int(codes.OK), protoContentType, stored)
+		}
+	}
+	if errors.Is(err, entity.ErrIdempotencyLockLost) {
+		g.logger.WarnContext(ctx, "Idempotency key taken over before the response was stored", slog.String("idempotency_key", record.Id))
+	} else if err != nil {
+		g.logger.ErrorContext(ctx, "Error storing idempotent response", slog.String("idempotency_key", record.Id), logging.Err(err))
+	}
+
+	return result, handlerErr
+}
+
+func replay(ctx context.Context, record entity.IdempotencyRecord, existing *entity.IdempotencyRecord, response func() proto.Message) (any, error) {
+	if existing.RequestHash != record.RequestHash {
+		return nil, toStatus(&internal_error.InternalError{
+			Message: "idempotency-key was already used with a different request",
+			Err:     "idempotency_key_reused",
+			Code:    http.StatusUnprocessableEntity,
+		})
+	}
+
+	if existing.Status != entity.IdempotencyCompleted {
+		return nil, toStatus(&internal_error.InternalError{
+			Message: "a request with this idempotency-key is still being processed",
+			Err:     "idempotency_request_in_progress",
+			Code:    http.StatusConflict,
+		})
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(ReplayedMetadata, "true"))
+	if code := codes.Code(existing.ResponseStatus); code != codes.OK {
+		return nil, status.Error(code, string(existing.ResponseBody))
+	}
+
+	message := response()
+	if err := proto.Unmarshal(existing.ResponseBody, message); err != nil {
+		return nil, toStatus(internal_error.NewInternalServerError(err.Error()))
+	}
+	return message, nil
+}
+
+// retryable são os códigos que não representam uma resposta definitiva do RPC
+func retryable(code codes.Code) bool {
+	switch code {
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return true
+	}
+	return false
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// clientIP é o endereço da conexão; a API gRPC é interna e não passa por proxies
+func clientIP(ctx context.Context) string {
+	client, ok := peer.FromContext(ctx)
+	if !ok || client.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(client.Addr.String())
+	if err != nil {
+		return client.Addr.String()
+	}
+	return host
+}
+
+func requestHash(fullMethod string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(fullMethod + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,109 @@
+package auction_service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/grpc/pb"
+	"github.com/auction-goexpert/internal/infra/api/web/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type memoryIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]entity.IdempotencyRecord
+}
+
+func (m *memoryIdempotencyRepository) Reserve(ctx context.Context, record entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.records[record.Id]; ok {
+		return &existing, nil
+	}
+	record.Status = entity.IdempotencyProcessing
+	m.records[record.Id] = record
+	return nil, nil
+}
+
+func (m *memoryIdempotencyRepository) Complete(ctx context.Context, id, owner string, status int, contentType string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.records[id]
+	if !ok || record.Owner != owner || record.Status != entity.IdempotencyProcessing {
+		return entity.ErrIdempotencyLockLost
+	}
+	record.Status = entity.IdempotencyCompleted
+	record.ResponseStatus = status
+	record.ContentType = contentType
+	record.ResponseBody = body
+	m.records[id] = record
+	return nil
+}
+
+func (m *memoryIdempotencyRepository) Release(ctx context.Context, id, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if record, ok := m.records[id]; ok && record.Owner == owner {
+		delete(m.records, id)
+	}
+	return nil
+}
+
+func newGuardedClient(t *testing.T, policies GuardPolicies) pb.AuctionServiceClient {
+	guards := NewGuards(ratelimit.NewMemoryStore(), &memoryIdempotencyRepository{records: map[string]entity.IdempotencyRecord{}},
+		policies, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	client, _ := newClient(t, grpc.ChainUnaryInterceptor(guards.UnaryInterceptor()))
+	return client
+}
+
+func TestGuardsReplayIdempotentBids(t *testing.T) {
+	client := newGuardedClient(t, GuardPolicies{})
+	auction := createAuction(t, client)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), IdempotencyKeyMetadata, "bid-1")
+	request := &pb.CreateBidRequest{UserId: "u1", AuctionId: auction.Id, Amount: "10.00"}
+
+	first, err := client.CreateBid(ctx, request)
+	require.Nil(t, err)
+
+	var header metadata.MD
+	second, err := client.CreateBid(ctx, request, grpc.Header(&header))
+	require.Nil(t, err)
+	assert.Equal(t, first.Id, second.Id)
+	assert.Equal(t, []string{"true"}, header.Get(ReplayedMetadata))
+
+	_, err = client.CreateBid(ctx, &pb.CreateBidRequest{UserId: "u1", AuctionId: auction.Id, Amount: "20.00"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "idempotency_key_reused")
+}
+
+func TestGuardsRateLimitBidsPerUser(t *testing.T) {
+	client := newGuardedClient(t, GuardPolicies{BidUser: entity.RateLimitPolicy{Capacity: 1, RefillEvery: time.Hour}})
+	auction := createAuction(t, client)
+	ctx := context.Background()
+
+	_, err := client.CreateBid(ctx, &pb.CreateBidRequest{UserId: "u1", AuctionId: auction.Id, Amount: "10.00"})
+	require.Nil(t, err)
+
+	var header metadata.MD
+	_, err = client.CreateBid(ctx, &pb.CreateBidRequest{UserId: "u1", AuctionId: auction.Id, Amount: "20.00"}, grpc.Header(&header))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.NotEmpty(t, header.Get(RetryAfterMetadata))
+
+	// Outro usuário tem o próprio bucket
+	_, err = client.CreateBid(ctx, &pb.CreateBidRequest{UserId: "u2", AuctionId: auction.Id, Amount: "20.00"})
+	assert.Nil(t, err)
+}
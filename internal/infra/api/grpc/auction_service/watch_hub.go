@@ -0,0 +1,208 @@
+package auction_service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/grpc/pb"
+	"github.com/auction-goexpert/internal/infra/logging"
+)
+
+// watcherBufferSize é quantos eventos um stream pode acumular antes de ser
+// considerado lento demais e desconectado
+const watcherBufferSize = 32
+
+type watcher struct {
+	events chan *pb.AuctionEvent
+	// lagging é fechado quando o watcher perde eventos por não acompanhar o ritmo
+	lagging chan struct{}
+}
+
+// AuctionEventLoader lê o stream de eventos de um leilão; é implementado pelo event store
+type AuctionEventLoader interface {
+	LoadAfter(ctx context.Context, auctionId string, version int64) ([]entity.AuctionEvent, error)
+}
+
+// watchedAuction é um leilão com streams abertos, no estado da última versão lida
+type watchedAuction struct {
+	aggregate *entity.AuctionAggregate
+	watchers  map[*watcher]struct{}
+}
+
+// WatchHub acompanha o stream de eventos (auction_events) dos leilões com streams
+// WatchAuction abertos e distribui os eventos novos: lances aceitos, prorrogações,
+// encerramento e cancelamento. Como lê do event store, recebe também o que outras réplicas
+// gravaram, como o encerramento feito pela réplica que detém a concessão do verificador.
+type WatchHub struct {
+	events       AuctionEventLoader
+	pollInterval time.Duration
+	logger       *slog.Logger
+
+	mu       sync.Mutex
+	auctions map[string]*watchedAuction
+}
+
+func NewWatchHub(events AuctionEventLoader, pollInterval time.Duration, logger *slog.Logger) *WatchHub {
+	return &WatchHub{
+		events:       events,
+		pollInterval: pollInterval,
+		logger:       logger,
+		auctions:     make(map[string]*watchedAuction),
+	}
+}
+
+// Start inicia a goroutine que lê os eventos novos a cada pollInterval
+func (wh *WatchHub) Start() {
+	go wh.run()
+}
+
+func (wh *WatchHub) run() {
+	ticker := time.NewTicker(wh.pollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		wh.poll(context.Background())
+	}
+}
+
+// subscribe inscreve um watcher no leilão e retorna o estado do leilão na versão a partir da
+// qual o watcher recebe os eventos, então nada se perde nem se repete entre os dois. Sem
+// stream, retorna ErrAuctionNotFound.
+func (wh *WatchHub) subscribe(ctx context.Context, auctionId string) (*watcher, entity.Auction, error) {
+	wh.mu.Lock()
+	watched, ok := wh.auctions[auctionId]
+	wh.mu.Unlock()
+
+	if !ok {
+		events, err := wh.events.LoadAfter(ctx, auctionId, 0)
+		if err != nil {
+			return nil, entity.Auction{}, err
+		}
+		aggregate, err := entity.LoadAuctionAggregate(events)
+		if err != nil {
+			return nil, entity.Auction{}, err
+		}
+		if !aggregate.Exists() {
+			return nil, entity.Auction{}, entity.ErrAuctionNotFound
+		}
+		watched = &watchedAuction{aggregate: aggregate, watchers: make(map[*watcher]struct{})}
+	}
+
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+
+	// Outro stream pode ter inscrito o leilão enquanto os eventos eram lidos
+	if current, ok := wh.auctions[auctionId]; ok {
+		watched = current
+	} else {
+		wh.auctions[auctionId] = watched
+	}
+
+	w := &watcher{
+		events:  make(chan *pb.AuctionEvent, watcherBufferSize),
+		lagging: make(chan struct{}),
+	}
+	watched.watchers[w] = struct{}{}
+	return w, watched.aggregate.Auction, nil
+}
+
+func (wh *WatchHub) unsubscribe(auctionId string, w *watcher) {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+
+	wh.remove(auctionId, w)
+}
+
+func (wh *WatchHub) remove(auctionId string, w *watcher) {
+	watched, ok := wh.auctions[auctionId]
+	if !ok {
+		return
+	}
+	delete(watched.watchers, w)
+	if len(watched.watchers) == 0 {
+		delete(wh.auctions, auctionId)
+	}
+}
+
+// poll lê os eventos gravados depois da última versão de cada leilão acompanhado. A leitura é
+// feita fora do lock, para não atrasar quem se inscreve.
+func (wh *WatchHub) poll(ctx context.Context) {
+	wh.mu.Lock()
+	versions := make(map[string]int64, len(wh.auctions))
+	for auctionId, watched := range wh.auctions {
+		versions[auctionId] = watched.aggregate.Version
+	}
+	wh.mu.Unlock()
+
+	for auctionId, version := range versions {
+		events, err := wh.events.LoadAfter(ctx, auctionId, version)
+		if err != nil {
+			wh.logger.ErrorContext(ctx, "Error loading auction events for watchers", logging.AuctionId(auctionId), logging.Err(err))
+			continue
+		}
+		if len(events) > 0 {
+			wh.publish(auctionId, events)
+		}
+	}
+}
+
+// publish aplica os eventos ao leilão e os envia aos watchers, descartando os que o leilão já
+// tem (de uma inscrição feita durante a leitura). Nunca bloqueia: um watcher com o buffer
+// cheio é desconectado.
+func (wh *WatchHub) publish(auctionId string, events []entity.AuctionEvent) {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+
+	watched, ok := wh.auctions[auctionId]
+	if !ok {
+		return
+	}
+
+	for _, event := range events {
+		if event.Version <= watched.aggregate.Version {
+			continue
+		}
+		if err := watched.aggregate.Apply([]entity.AuctionEvent{event}); err != nil {
+			wh.logger.Error("Error applying auction event for watchers", logging.AuctionId(auctionId), logging.Err(err))
+			return
+		}
+
+		message := toAuctionEvent(event, watched.aggregate.Auction)
+		if message == nil {
+			continue
+		}
+		for w := range watched.watchers {
+			select {
+			case w.events <- message:
+			default:
+				close(w.lagging)
+				wh.remove(auctionId, w)
+			}
+		}
+	}
+}
+
+// toAuctionEvent converte o evento do stream na mensagem do WatchAuction, com o leilão já no
+// estado depois dele. A prorrogação vai como um novo snapshot e o cancelamento como o leilão
+// encerrado, com o status cancelado.
+func toAuctionEvent(event entity.AuctionEvent, auction entity.Auction) *pb.AuctionEvent {
+	switch data := event.Data.(type) {
+	case entity.BidPlacedData:
+		bid := entity.Bid{
+			Id:        data.BidId,
+			UserId:    data.UserId,
+			AuctionId: event.AuctionId,
+			Amount:    entity.NewMoney(data.Amount, data.Currency.OrDefault()),
+			Timestamp: event.OccurredAt,
+		}
+		return &pb.AuctionEvent{Event: &pb.AuctionEvent_BidPlaced{BidPlaced: toBidFromEntity(bid)}}
+	case entity.AuctionExtendedData:
+		return &pb.AuctionEvent{Event: &pb.AuctionEvent_Snapshot{Snapshot: toAuctionFromEntity(auction)}}
+	case entity.AuctionClosedData, entity.AuctionCancelledData:
+		return &pb.AuctionEvent{Event: &pb.AuctionEvent_Closed{Closed: toAuctionFromEntity(auction)}}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package adminauth
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/gin-gonic/gin"
+)
+
+type adminToken struct {
+	token []byte
+	actor string
+}
+
+// Middleware exige um "Authorization: Bearer <token>" de tokens, que associa cada token ao
+// operador. O operador fica no contexto da requisição e é o autor no log de auditoria.
+// Sem tokens configurados, todas as requisições são recusadas.
+func Middleware(tokens map[string]string) gin.HandlerFunc {
+	known := make([]adminToken, 0, len(tokens))
+	for token, actor := range tokens {
+		known = append(known, adminToken{token: []byte(token), actor: actor})
+	}
+
+	return func(c *gin.Context) {
+		actor, ok := authenticate(known, c.GetHeader("Authorization"))
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			problem.Respond(c, internal_error.NewUnauthorizedError("missing or invalid admin token"))
+			return
+		}
+
+		c.Request = c.Request.WithContext(entity.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
+
+// authenticate compara o token com todos os conhecidos em tempo constante, para que o
+// tempo de resposta não revele prefixos válidos
+func authenticate(known []adminToken, header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+
+	var actor string
+	for _, candidate := range known {
+		if subtle.ConstantTimeCompare(candidate.token, []byte(token)) == 1 {
+			actor = candidate.actor
+		}
+	}
+	return actor, actor != ""
+}
@@ -0,0 +1,41 @@
+package adminauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareTakesActorFromToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware(map[string]string{"0123456789abcdef": "admin-maria"}))
+	router.POST("/admin/fx-rates", func(c *gin.Context) {
+		c.String(http.StatusOK, entity.ActorFromContext(c.Request.Context()))
+	})
+
+	perform := func(authorization string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/admin/fx-rates", nil)
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+		request.Header.Set("X-Actor", "someone-else")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := perform("Bearer 0123456789abcdef")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "admin-maria", recorder.Body.String())
+
+	for _, authorization := range []string{"", "Bearer wrong-token-value", "Basic 0123456789abcdef", "Bearer "} {
+		recorder := perform(authorization)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, authorization)
+		assert.Equal(t, `Bearer realm="admin"`, recorder.Header().Get("WWW-Authenticate"))
+	}
+}
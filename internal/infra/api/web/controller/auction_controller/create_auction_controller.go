@@ -3,6 +3,10 @@ package auction_controller
 import (
 	"net/http"
 
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/web/pagination"
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/internal_error"
 	"github.com/auction-goexpert/internal/usecase/auction_usecase"
 	"github.com/gin-gonic/gin"
 )
@@ -26,13 +30,13 @@ func (ac *AuctionController) CreateAuction(c *gin.Context) {
 	var input auction_usecase.AuctionInputDTO
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.RespondBindingError(c, err)
 		return
 	}
 
 	output, internalErr := ac.createAuctionUseCase.Execute(c.Request.Context(), input)
 	if internalErr != nil {
-		c.JSON(internalErr.Code, gin.H{"error": internalErr.Message})
+		problem.Respond(c, internalErr)
 		return
 	}
 
@@ -44,7 +48,7 @@ func (ac *AuctionController) FindAuctionById(c *gin.Context) {
 
 	output, internalErr := ac.findAuctionUseCase.FindAuctionById(c.Request.Context(), auctionId)
 	if internalErr != nil {
-		c.JSON(internalErr.Code, gin.H{"error": internalErr.Message})
+		problem.Respond(c, internalErr)
 		return
 	}
 
@@ -55,19 +59,58 @@ func (ac *AuctionController) FindAuctions(c *gin.Context) {
 	status := c.Query("status")
 	category := c.Query("category")
 	productName := c.Query("productName")
+	displayCurrency := entity.Currency(c.Query("displayCurrency"))
 
-	var auctionStatus int = -1
+	var auctionStatus entity.AuctionStatus = -1
 	if status == "0" {
 		auctionStatus = 0
 	} else if status == "1" {
 		auctionStatus = 1
+	} else if status == "2" {
+		auctionStatus = 2
 	}
 
-	output, internalErr := ac.findAuctionUseCase.FindAuctions(c.Request.Context(), auctionStatus, category, productName)
+	if displayCurrency != "" && !displayCurrency.IsSupported() {
+		problem.Respond(c, internal_error.NewBadRequestError("invalid displayCurrency, allowed values: BRL, USD"))
+		return
+	}
+
+	page, err := pagination.ParsePageRequest(c)
+	if err != nil {
+		problem.Respond(c, internal_error.NewBadRequestError(err.Error()))
+		return
+	}
+
+	output, nextCursor, internalErr := ac.findAuctionUseCase.FindAuctions(c.Request.Context(), auctionStatus, category, productName, displayCurrency, page)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	pagination.SetNextPageHeaders(c, nextCursor)
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (ac *AuctionController) SearchAuctions(c *gin.Context) {
+	var input auction_usecase.AuctionSearchInputDTO
+	if err := c.ShouldBindQuery(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	page, err := pagination.ParsePageRequest(c)
+	if err != nil {
+		problem.Respond(c, internal_error.NewBadRequestError(err.Error()))
+		return
+	}
+
+	output, nextCursor, internalErr := ac.findAuctionUseCase.SearchAuctions(c.Request.Context(), input, page)
 	if internalErr != nil {
-		c.JSON(internalErr.Code, gin.H{"error": internalErr.Message})
+		problem.Respond(c, internalErr)
 		return
 	}
 
+	pagination.SetNextPageHeaders(c, nextCursor)
 	c.JSON(http.StatusOK, output)
 }
@@ -0,0 +1,38 @@
+package audit_controller
+
+import (
+	"net/http"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/usecase/audit_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditController struct {
+	auditUseCase *audit_usecase.AuditUseCase
+}
+
+func NewAuditController(auditUseCase *audit_usecase.AuditUseCase) *AuditController {
+	return &AuditController{
+		auditUseCase: auditUseCase,
+	}
+}
+
+func (ac *AuditController) FindAuctionAuditTrail(c *gin.Context) {
+	ac.respondAuditTrail(c, c.Param("auctionId"))
+}
+
+func (ac *AuditController) FindAdminAuditTrail(c *gin.Context) {
+	ac.respondAuditTrail(c, entity.AuditAdminStream)
+}
+
+func (ac *AuditController) respondAuditTrail(c *gin.Context, stream string) {
+	output, internalErr := ac.auditUseCase.FindAuditTrail(c.Request.Context(), stream)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
@@ -3,6 +3,9 @@ package bid_controller
 import (
 	"net/http"
 
+	"github.com/auction-goexpert/internal/infra/api/web/pagination"
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/internal_error"
 	"github.com/auction-goexpert/internal/usecase/bid_usecase"
 	"github.com/gin-gonic/gin"
 )
@@ -26,13 +29,13 @@ func (bc *BidController) CreateBid(c *gin.Context) {
 	var input bid_usecase.BidInputDTO
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.RespondBindingError(c, err)
 		return
 	}
 
 	output, internalErr := bc.createBidUseCase.Execute(c.Request.Context(), input)
 	if internalErr != nil {
-		c.JSON(internalErr.Code, gin.H{"error": internalErr.Message})
+		problem.Respond(c, internalErr)
 		return
 	}
 
@@ -42,12 +45,20 @@ func (bc *BidController) CreateBid(c *gin.Context) {
 func (bc *BidController) FindBidByAuctionId(c *gin.Context) {
 	auctionId := c.Param("auctionId")
 
-	output, internalErr := bc.findBidUseCase.FindBidByAuctionId(c.Request.Context(), auctionId)
+	page, err := pagination.ParsePageRequest(c)
+	if err != nil {
+		problem.Respond(c, internal_error.NewBadRequestError(err.Error()))
+		return
+	}
+
+	output, nextCursor, internalErr := bc.findBidUseCase.FindBidByAuctionId(c.Request.Context(), auctionId, page)
 	if internalErr != nil {
-		c.JSON(internalErr.Code, gin.H{"error": internalErr.Message})
+		problem.Respond(c, internalErr)
 		return
 	}
 
+	pagination.SetNextPageHeaders(c, nextCursor)
+
 	c.JSON(http.StatusOK, output)
 }
 
@@ -56,7 +67,7 @@ func (bc *BidController) FindWinningBidByAuctionId(c *gin.Context) {
 
 	output, internalErr := bc.findBidUseCase.FindWinningBidByAuctionId(c.Request.Context(), auctionId)
 	if internalErr != nil {
-		c.JSON(internalErr.Code, gin.H{"error": internalErr.Message})
+		problem.Respond(c, internalErr)
 		return
 	}
 
@@ -0,0 +1,46 @@
+package exchange_rate_controller
+
+import (
+	"net/http"
+
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/usecase/exchange_rate_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRateController struct {
+	exchangeRateUseCase *exchange_rate_usecase.ExchangeRateUseCase
+}
+
+func NewExchangeRateController(exchangeRateUseCase *exchange_rate_usecase.ExchangeRateUseCase) *ExchangeRateController {
+	return &ExchangeRateController{
+		exchangeRateUseCase: exchangeRateUseCase,
+	}
+}
+
+func (ec *ExchangeRateController) CreateExchangeRate(c *gin.Context) {
+	var input exchange_rate_usecase.ExchangeRateInputDTO
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	output, internalErr := ec.exchangeRateUseCase.CreateExchangeRate(c.Request.Context(), input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, output)
+}
+
+func (ec *ExchangeRateController) FindExchangeRates(c *gin.Context) {
+	output, internalErr := ec.exchangeRateUseCase.FindExchangeRates(c.Request.Context())
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
@@ -0,0 +1,93 @@
+package invoice_controller
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/usecase/invoice_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// csvHeader são as colunas da exportação em CSV: uma linha por item de fatura, repetindo
+// os dados e os totais da fatura. Faturas anuladas pela troca do comprador vêm com status
+// voided e o horário da anulação.
+var csvHeader = []string{
+	"invoice_id", "issued_at", "status", "voided_at", "auction_id", "attempt", "party", "user_id", "currency",
+	"line_kind", "line_description", "line_amount", "subtotal", "tax", "total",
+}
+
+type InvoiceController struct {
+	invoiceUseCase *invoice_usecase.InvoiceUseCase
+}
+
+func NewInvoiceController(invoiceUseCase *invoice_usecase.InvoiceUseCase) *InvoiceController {
+	return &InvoiceController{
+		invoiceUseCase: invoiceUseCase,
+	}
+}
+
+func (ic *InvoiceController) FindInvoice(c *gin.Context) {
+	output, internalErr := ic.invoiceUseCase.FindInvoice(c.Request.Context(), c.Param("invoiceId"))
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// ExportInvoices exporta as faturas do período em JSON (padrão) ou CSV, para a contabilidade
+func (ic *InvoiceController) ExportInvoices(c *gin.Context) {
+	var input invoice_usecase.ExportInvoicesInputDTO
+	if err := c.ShouldBindQuery(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	output, internalErr := ic.invoiceUseCase.ExportInvoices(c.Request.Context(), input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	if input.Format != "csv" {
+		c.JSON(http.StatusOK, output)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="invoices.csv"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write(csvHeader)
+	for _, invoice := range output {
+		voidedAt := ""
+		if invoice.VoidedAt != nil {
+			voidedAt = invoice.VoidedAt.UTC().Format(time.RFC3339)
+		}
+		for _, line := range invoice.Lines {
+			_ = writer.Write([]string{
+				invoice.Id,
+				invoice.IssuedAt.UTC().Format(time.RFC3339),
+				string(invoice.Status),
+				voidedAt,
+				invoice.AuctionId,
+				strconv.Itoa(invoice.Attempt),
+				string(invoice.Party),
+				invoice.UserId,
+				string(invoice.Currency),
+				string(line.Kind),
+				line.Description,
+				line.Amount.String(),
+				invoice.Subtotal.String(),
+				invoice.Tax.String(),
+				invoice.Total.String(),
+			})
+		}
+	}
+	writer.Flush()
+}
@@ -0,0 +1,86 @@
+package notification_controller
+
+import (
+	"net/http"
+
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/usecase/notification_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationController struct {
+	findNotificationUseCase       *notification_usecase.FindNotificationUseCase
+	notificationPreferenceUseCase *notification_usecase.NotificationPreferenceUseCase
+}
+
+func NewNotificationController(
+	findNotificationUseCase *notification_usecase.FindNotificationUseCase,
+	notificationPreferenceUseCase *notification_usecase.NotificationPreferenceUseCase,
+) *NotificationController {
+	return &NotificationController{
+		findNotificationUseCase:       findNotificationUseCase,
+		notificationPreferenceUseCase: notificationPreferenceUseCase,
+	}
+}
+
+func (nc *NotificationController) FindNotifications(c *gin.Context) {
+	userId := c.Param("userId")
+	onlyUnread := c.Query("unread") == "true"
+
+	output, internalErr := nc.findNotificationUseCase.FindNotificationsByUserId(c.Request.Context(), userId, onlyUnread)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (nc *NotificationController) MarkNotificationsAsRead(c *gin.Context) {
+	userId := c.Param("userId")
+
+	var input notification_usecase.MarkAsReadInputDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			problem.RespondBindingError(c, err)
+			return
+		}
+	}
+
+	if internalErr := nc.findNotificationUseCase.MarkNotificationsAsRead(c.Request.Context(), userId, input); internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (nc *NotificationController) FindPreference(c *gin.Context) {
+	userId := c.Param("userId")
+
+	output, internalErr := nc.notificationPreferenceUseCase.FindPreference(c.Request.Context(), userId)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (nc *NotificationController) UpdatePreference(c *gin.Context) {
+	userId := c.Param("userId")
+
+	var input notification_usecase.NotificationPreferenceInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	output, internalErr := nc.notificationPreferenceUseCase.UpdatePreference(c.Request.Context(), userId, input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
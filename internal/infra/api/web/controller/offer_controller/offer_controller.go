@@ -0,0 +1,87 @@
+package offer_controller
+
+import (
+	"net/http"
+
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/usecase/offer_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type OfferController struct {
+	offerUseCase *offer_usecase.OfferUseCase
+}
+
+func NewOfferController(offerUseCase *offer_usecase.OfferUseCase) *OfferController {
+	return &OfferController{
+		offerUseCase: offerUseCase,
+	}
+}
+
+func (oc *OfferController) FindRunnerUps(c *gin.Context) {
+	output, internalErr := oc.offerUseCase.FindRunnerUps(c.Request.Context(), c.Param("auctionId"))
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (oc *OfferController) CreateOffers(c *gin.Context) {
+	var input offer_usecase.CreateOffersInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	output, internalErr := oc.offerUseCase.CreateOffers(c.Request.Context(), c.Param("auctionId"), input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, output)
+}
+
+func (oc *OfferController) FindOffers(c *gin.Context) {
+	output, internalErr := oc.offerUseCase.FindOffers(c.Request.Context(), c.Param("auctionId"))
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (oc *OfferController) AcceptOffer(c *gin.Context) {
+	var input offer_usecase.OfferResponseInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	output, internalErr := oc.offerUseCase.AcceptOffer(c.Request.Context(), c.Param("auctionId"), c.Param("offerId"), input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (oc *OfferController) DeclineOffer(c *gin.Context) {
+	var input offer_usecase.OfferResponseInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	output, internalErr := oc.offerUseCase.DeclineOffer(c.Request.Context(), c.Param("auctionId"), c.Param("offerId"), input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
@@ -0,0 +1,35 @@
+package report_controller
+
+import (
+	"net/http"
+
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/usecase/report_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportController struct {
+	salesReportUseCase *report_usecase.SalesReportUseCase
+}
+
+func NewReportController(salesReportUseCase *report_usecase.SalesReportUseCase) *ReportController {
+	return &ReportController{
+		salesReportUseCase: salesReportUseCase,
+	}
+}
+
+func (rc *ReportController) SalesReport(c *gin.Context) {
+	var input report_usecase.SalesReportInputDTO
+	if err := c.ShouldBindQuery(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	output, internalErr := rc.salesReportUseCase.Execute(c.Request.Context(), input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
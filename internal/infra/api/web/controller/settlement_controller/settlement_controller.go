@@ -0,0 +1,119 @@
+package settlement_controller
+
+import (
+	"net/http"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/infra/api/web/userauth"
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/auction-goexpert/internal/usecase/settlement_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type SettlementController struct {
+	settlementUseCase *settlement_usecase.SettlementUseCase
+}
+
+func NewSettlementController(settlementUseCase *settlement_usecase.SettlementUseCase) *SettlementController {
+	return &SettlementController{
+		settlementUseCase: settlementUseCase,
+	}
+}
+
+func (sc *SettlementController) FindSettlement(c *gin.Context) {
+	output, internalErr := sc.settlementUseCase.FindSettlement(c.Request.Context(), c.Param("auctionId"))
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (sc *SettlementController) Pay(c *gin.Context) {
+	var input settlement_usecase.SettlementActionInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+	if !authorize(c, input.UserId) {
+		return
+	}
+
+	output, internalErr := sc.settlementUseCase.Pay(c.Request.Context(), c.Param("auctionId"), input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (sc *SettlementController) Ship(c *gin.Context) {
+	var input settlement_usecase.ShipSettlementInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+	if !authorize(c, input.UserId) {
+		return
+	}
+
+	output, internalErr := sc.settlementUseCase.Ship(c.Request.Context(), c.Param("auctionId"), input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (sc *SettlementController) ConfirmDelivery(c *gin.Context) {
+	var input settlement_usecase.SettlementActionInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+	if !authorize(c, input.UserId) {
+		return
+	}
+
+	output, internalErr := sc.settlementUseCase.ConfirmDelivery(c.Request.Context(), c.Param("auctionId"), input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (sc *SettlementController) Dispute(c *gin.Context) {
+	var input settlement_usecase.DisputeSettlementInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+	if !authorize(c, input.UserId) {
+		return
+	}
+
+	output, internalErr := sc.settlementUseCase.Dispute(c.Request.Context(), c.Param("auctionId"), input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// authorize recusa a ação quando o user_id do corpo não é o usuário autenticado por
+// userauth.Middleware, para que ninguém aja no acerto em nome de outro
+func authorize(c *gin.Context, userId string) bool {
+	if userId != userauth.UserId(c) {
+		problem.Respond(c, internal_error.FromError(
+			entity.ErrNotSettlementParty.WithMessage("user_id does not match the authenticated user")))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package settlement_controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/auction-goexpert/internal/infra/api/web/userauth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettlementActionsRejectAnotherUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	controller := NewSettlementController(nil)
+	auth := userauth.Middleware(map[string]string{"0123456789abcdef": "user-123"})
+	router.POST("/auction/:auctionId/settlement/pay", auth, controller.Pay)
+	router.POST("/auction/:auctionId/settlement/ship", auth, controller.Ship)
+	router.POST("/auction/:auctionId/settlement/deliver", auth, controller.ConfirmDelivery)
+	router.POST("/auction/:auctionId/settlement/dispute", auth, controller.Dispute)
+
+	// O corpo informa outro usuário; a recusa vem antes do use case
+	for _, action := range []string{"pay", "ship", "deliver", "dispute"} {
+		body := `{"user_id": "user-456", "reason": "item not received"}`
+		request := httptest.NewRequest(http.MethodPost, "/auction/auction-1/settlement/"+action, strings.NewReader(body))
+		request.Header.Set("Authorization", "Bearer 0123456789abcdef")
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code, action)
+		assert.Contains(t, recorder.Body.String(), "not_settlement_party", action)
+	}
+}
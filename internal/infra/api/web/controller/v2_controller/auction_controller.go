@@ -0,0 +1,114 @@
+package v2_controller
+
+import (
+	"net/http"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/web/pagination"
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/auction-goexpert/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// AuctionController atende as rotas de leilão da v2 com os mesmos use cases da v1
+type AuctionController struct {
+	createAuctionUseCase *auction_usecase.CreateAuctionUseCase
+	findAuctionUseCase   *auction_usecase.FindAuctionUseCase
+}
+
+func NewAuctionController(
+	createAuctionUseCase *auction_usecase.CreateAuctionUseCase,
+	findAuctionUseCase *auction_usecase.FindAuctionUseCase,
+) *AuctionController {
+	return &AuctionController{
+		createAuctionUseCase: createAuctionUseCase,
+		findAuctionUseCase:   findAuctionUseCase,
+	}
+}
+
+func (ac *AuctionController) CreateAuction(c *gin.Context) {
+	var input AuctionInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	auctionInput, err := toAuctionInput(input)
+	if err != nil {
+		problem.Respond(c, internal_error.FromError(err))
+		return
+	}
+
+	output, internalErr := ac.createAuctionUseCase.Execute(c.Request.Context(), auctionInput)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toAuctionDTO(*output))
+}
+
+func (ac *AuctionController) FindAuctionById(c *gin.Context) {
+	output, internalErr := ac.findAuctionUseCase.FindAuctionById(c.Request.Context(), c.Param("auctionId"))
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAuctionDTO(*output))
+}
+
+func (ac *AuctionController) FindAuctions(c *gin.Context) {
+	var auctionStatus entity.AuctionStatus = -1
+	if status := c.Query("status"); status != "" {
+		parsed := parseStatus(status)
+		if parsed == nil {
+			problem.Respond(c, internal_error.NewBadRequestError("invalid status, allowed values: active, completed, cancelled"))
+			return
+		}
+		auctionStatus = *parsed
+	}
+
+	displayCurrency := entity.Currency(c.Query("displayCurrency"))
+	if displayCurrency != "" && !displayCurrency.IsSupported() {
+		problem.Respond(c, internal_error.NewBadRequestError("invalid displayCurrency, allowed values: BRL, USD"))
+		return
+	}
+
+	page, err := pagination.ParsePageRequest(c)
+	if err != nil {
+		problem.Respond(c, internal_error.NewBadRequestError(err.Error()))
+		return
+	}
+
+	output, nextCursor, internalErr := ac.findAuctionUseCase.FindAuctions(c.Request.Context(), auctionStatus, c.Query("category"), c.Query("productName"), displayCurrency, page)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAuctionPage(output, nextCursor))
+}
+
+func (ac *AuctionController) SearchAuctions(c *gin.Context) {
+	var input AuctionSearchInputDTO
+	if err := c.ShouldBindQuery(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	page, err := pagination.ParsePageRequest(c)
+	if err != nil {
+		problem.Respond(c, internal_error.NewBadRequestError(err.Error()))
+		return
+	}
+
+	output, nextCursor, internalErr := ac.findAuctionUseCase.SearchAuctions(c.Request.Context(), toSearchInput(input), page)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAuctionPage(output, nextCursor))
+}
@@ -0,0 +1,75 @@
+package v2_controller
+
+import (
+	"net/http"
+
+	"github.com/auction-goexpert/internal/infra/api/web/pagination"
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/auction-goexpert/internal/usecase/bid_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// BidController atende as rotas de lance da v2 com os mesmos use cases da v1
+type BidController struct {
+	createBidUseCase *bid_usecase.CreateBidUseCase
+	findBidUseCase   *bid_usecase.FindBidUseCase
+}
+
+func NewBidController(
+	createBidUseCase *bid_usecase.CreateBidUseCase,
+	findBidUseCase *bid_usecase.FindBidUseCase,
+) *BidController {
+	return &BidController{
+		createBidUseCase: createBidUseCase,
+		findBidUseCase:   findBidUseCase,
+	}
+}
+
+func (bc *BidController) CreateBid(c *gin.Context) {
+	var input BidInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	bidInput, err := toBidInput(input)
+	if err != nil {
+		problem.Respond(c, internal_error.FromError(err))
+		return
+	}
+
+	output, internalErr := bc.createBidUseCase.Execute(c.Request.Context(), bidInput)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toBidDTO(*output))
+}
+
+func (bc *BidController) FindBidByAuctionId(c *gin.Context) {
+	page, err := pagination.ParsePageRequest(c)
+	if err != nil {
+		problem.Respond(c, internal_error.NewBadRequestError(err.Error()))
+		return
+	}
+
+	output, nextCursor, internalErr := bc.findBidUseCase.FindBidByAuctionId(c.Request.Context(), c.Param("auctionId"), page)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, toBidPage(output, nextCursor))
+}
+
+func (bc *BidController) FindWinningBidByAuctionId(c *gin.Context) {
+	output, internalErr := bc.findBidUseCase.FindWinningBidByAuctionId(c.Request.Context(), c.Param("auctionId"))
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, toBidDTO(*output))
+}
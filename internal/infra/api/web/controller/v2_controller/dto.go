@@ -0,0 +1,85 @@
+package v2_controller
+
+import (
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+)
+
+// Os DTOs da v2 representam dinheiro como objeto com valor e moeda, enums como
+// strings e listas paginadas com o cursor no corpo. A v1 continua usando os DTOs
+// dos use cases sem alteração.
+
+type MoneyDTO struct {
+	Amount   string          `json:"amount" binding:"required"`
+	Currency entity.Currency `json:"currency" binding:"omitempty,oneof=BRL USD"`
+}
+
+type AuctionInputDTO struct {
+	ProductName string `json:"product_name" binding:"required,min=1"`
+	Category    string `json:"category" binding:"required,min=2"`
+	Description string `json:"description" binding:"required,min=10,max=200"`
+	Condition   string `json:"condition" binding:"required,oneof=new used refurbished"`
+	SellerId    string `json:"seller_id"`
+	// Currency é a moeda do leilão (padrão: BRL)
+	Currency entity.Currency `json:"currency" binding:"omitempty,oneof=BRL USD"`
+	// ReservePrice é o preço mínimo de venda, na moeda do leilão; ausente significa sem reserva
+	ReservePrice *MoneyDTO `json:"reserve_price"`
+}
+
+type ConvertedPriceDTO struct {
+	Amount      MoneyDTO  `json:"amount"`
+	Rate        string    `json:"rate"`
+	EffectiveAt time.Time `json:"effective_at"`
+}
+
+type AuctionDTO struct {
+	Id             string             `json:"id"`
+	ProductName    string             `json:"product_name"`
+	Category       string             `json:"category"`
+	Description    string             `json:"description"`
+	Condition      string             `json:"condition"`
+	Status         string             `json:"status"`
+	CurrentPrice   MoneyDTO           `json:"current_price"`
+	SellerId       string             `json:"seller_id,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	ExpiresAt      time.Time          `json:"expires_at"`
+	ConvertedPrice *ConvertedPriceDTO `json:"converted_price,omitempty"`
+	ReserveMet     *bool              `json:"reserve_met,omitempty"`
+}
+
+// AuctionSearchInputDTO espelha a busca da v1, com condition e status por nome
+type AuctionSearchInputDTO struct {
+	Query        string     `form:"q" binding:"max=200"`
+	Categories   []string   `form:"category"`
+	Conditions   []string   `form:"condition" binding:"dive,oneof=new used refurbished"`
+	Status       string     `form:"status" binding:"omitempty,oneof=active completed cancelled"`
+	SellerId     string     `form:"seller"`
+	Currency     string     `form:"currency" binding:"omitempty,oneof=BRL USD"`
+	MinPrice     string     `form:"minPrice"`
+	MaxPrice     string     `form:"maxPrice"`
+	EndingAfter  *time.Time `form:"endingAfter" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndingBefore *time.Time `form:"endingBefore" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	DisplayCurrency string `form:"displayCurrency" binding:"omitempty,oneof=BRL USD"`
+}
+
+type BidInputDTO struct {
+	UserId    string   `json:"user_id" binding:"required"`
+	AuctionId string   `json:"auction_id" binding:"required"`
+	Amount    MoneyDTO `json:"amount"`
+}
+
+type BidDTO struct {
+	Id        string    `json:"id"`
+	UserId    string    `json:"user_id"`
+	AuctionId string    `json:"auction_id"`
+	Amount    MoneyDTO  `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PageDTO é a resposta das listagens; NextCursor fica vazio na última página
+type PageDTO[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,164 @@
+package v2_controller
+
+import (
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/usecase/auction_usecase"
+	"github.com/auction-goexpert/internal/usecase/bid_usecase"
+)
+
+var conditionNames = map[entity.ProductCondition]string{
+	entity.New:         "new",
+	entity.Used:        "used",
+	entity.Refurbished: "refurbished",
+}
+
+var statusNames = map[entity.AuctionStatus]string{
+	entity.Active:    "active",
+	entity.Completed: "completed",
+	entity.Cancelled: "cancelled",
+}
+
+func parseCondition(name string) entity.ProductCondition {
+	for condition, conditionName := range conditionNames {
+		if conditionName == name {
+			return condition
+		}
+	}
+	return entity.New
+}
+
+// parseStatus retorna nil para "" (qualquer status)
+func parseStatus(name string) *entity.AuctionStatus {
+	for status, statusName := range statusNames {
+		if statusName == name {
+			return &status
+		}
+	}
+	return nil
+}
+
+func toMoneyDTO(money entity.Money) MoneyDTO {
+	return MoneyDTO{Amount: money.String(), Currency: money.Currency}
+}
+
+// toAuctionInput rejeita um preço de reserva em moeda diferente da do leilão
+func toAuctionInput(input AuctionInputDTO) (auction_usecase.AuctionInputDTO, error) {
+	output := auction_usecase.AuctionInputDTO{
+		ProductName: input.ProductName,
+		Category:    input.Category,
+		Description: input.Description,
+		Condition:   parseCondition(input.Condition),
+		SellerId:    input.SellerId,
+		Currency:    input.Currency,
+	}
+
+	if reserve := input.ReservePrice; reserve != nil {
+		currency := input.Currency.OrDefault()
+		if reserve.Currency != "" && reserve.Currency != currency {
+			return auction_usecase.AuctionInputDTO{}, entity.ErrCurrencyMismatch.WithMessage("reserve price currency does not match auction currency")
+		}
+
+		amount, err := entity.ParseMoney(reserve.Amount, currency)
+		if err != nil {
+			return auction_usecase.AuctionInputDTO{}, err
+		}
+		output.ReservePrice = amount
+	}
+
+	return output, nil
+}
+
+func toAuctionDTO(auction auction_usecase.AuctionOutputDTO) AuctionDTO {
+	currentPrice := auction.CurrentPrice
+	currentPrice.Currency = auction.Currency
+
+	output := AuctionDTO{
+		Id:           auction.Id,
+		ProductName:  auction.ProductName,
+		Category:     auction.Category,
+		Description:  auction.Description,
+		Condition:    conditionNames[auction.Condition],
+		Status:       statusNames[auction.Status],
+		CurrentPrice: toMoneyDTO(currentPrice),
+		SellerId:     auction.SellerId,
+		CreatedAt:    auction.Timestamp,
+		ExpiresAt:    auction.ExpiresAt,
+		ReserveMet:   auction.ReserveMet,
+	}
+
+	if converted := auction.ConvertedPrice; converted != nil {
+		amount := converted.Amount
+		amount.Currency = converted.Currency
+		output.ConvertedPrice = &ConvertedPriceDTO{
+			Amount:      toMoneyDTO(amount),
+			Rate:        converted.Rate,
+			EffectiveAt: converted.EffectiveAt,
+		}
+	}
+
+	return output
+}
+
+func toAuctionPage(auctions []auction_usecase.AuctionOutputDTO, nextCursor string) PageDTO[AuctionDTO] {
+	page := PageDTO[AuctionDTO]{Data: make([]AuctionDTO, 0, len(auctions)), NextCursor: nextCursor}
+	for _, auction := range auctions {
+		page.Data = append(page.Data, toAuctionDTO(auction))
+	}
+	return page
+}
+
+func toSearchInput(input AuctionSearchInputDTO) auction_usecase.AuctionSearchInputDTO {
+	conditions := make([]entity.ProductCondition, 0, len(input.Conditions))
+	for _, condition := range input.Conditions {
+		conditions = append(conditions, parseCondition(condition))
+	}
+
+	return auction_usecase.AuctionSearchInputDTO{
+		Query:           input.Query,
+		Categories:      input.Categories,
+		Conditions:      conditions,
+		Status:          parseStatus(input.Status),
+		SellerId:        input.SellerId,
+		Currency:        entity.Currency(input.Currency),
+		MinPrice:        input.MinPrice,
+		MaxPrice:        input.MaxPrice,
+		EndingAfter:     input.EndingAfter,
+		EndingBefore:    input.EndingBefore,
+		DisplayCurrency: entity.Currency(input.DisplayCurrency),
+	}
+}
+
+func toBidInput(input BidInputDTO) (bid_usecase.BidInputDTO, error) {
+	amount, err := entity.ParseMoney(input.Amount.Amount, input.Amount.Currency)
+	if err != nil {
+		return bid_usecase.BidInputDTO{}, err
+	}
+
+	return bid_usecase.BidInputDTO{
+		UserId:    input.UserId,
+		AuctionId: input.AuctionId,
+		Amount:    amount,
+		Currency:  input.Amount.Currency,
+	}, nil
+}
+
+func toBidDTO(bid bid_usecase.BidOutputDTO) BidDTO {
+	amount := bid.Amount
+	amount.Currency = bid.Currency
+
+	return BidDTO{
+		Id:        bid.Id,
+		UserId:    bid.UserId,
+		AuctionId: bid.AuctionId,
+		Amount:    toMoneyDTO(amount),
+		CreatedAt: bid.Timestamp,
+	}
+}
+
+func toBidPage(bids []bid_usecase.BidOutputDTO, nextCursor string) PageDTO[BidDTO] {
+	page := PageDTO[BidDTO]{Data: make([]BidDTO, 0, len(bids)), NextCursor: nextCursor}
+	for _, bid := range bids {
+		page.Data = append(page.Data, toBidDTO(bid))
+	}
+	return page
+}
@@ -0,0 +1,52 @@
+package v2_controller
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/usecase/auction_usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuctionDTOUsesMoneyObjectAndEnumNames(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	auction := toAuctionDTO(auction_usecase.AuctionOutputDTO{
+		Id:           "a1",
+		Condition:    entity.Refurbished,
+		Status:       entity.Completed,
+		CurrentPrice: entity.NewMoney(150050, entity.USD),
+		Currency:     entity.USD,
+		Timestamp:    createdAt,
+	})
+
+	body, err := json.Marshal(auction)
+	assert.Nil(t, err)
+
+	var fields map[string]any
+	assert.Nil(t, json.Unmarshal(body, &fields))
+	assert.Equal(t, "refurbished", fields["condition"])
+	assert.Equal(t, "completed", fields["status"])
+	assert.Equal(t, map[string]any{"amount": "1500.50", "currency": "USD"}, fields["current_price"])
+	assert.Equal(t, "2024-01-01T12:00:00Z", fields["created_at"])
+}
+
+func TestBidInputParsesMoneyObject(t *testing.T) {
+	input, err := toBidInput(BidInputDTO{UserId: "u1", AuctionId: "a1", Amount: MoneyDTO{Amount: "10.5", Currency: entity.USD}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1050), input.Amount.Cents)
+	assert.Equal(t, entity.USD, input.Currency)
+
+	_, err = toBidInput(BidInputDTO{Amount: MoneyDTO{Amount: "10.555"}})
+	assert.ErrorIs(t, err, entity.ErrInvalidMoney)
+}
+
+func TestSearchInputMapsEnumNames(t *testing.T) {
+	input := toSearchInput(AuctionSearchInputDTO{Conditions: []string{"used", "new"}, Status: "active"})
+	assert.Equal(t, []entity.ProductCondition{entity.Used, entity.New}, input.Conditions)
+	assert.Equal(t, entity.Active, *input.Status)
+	assert.Equal(t, entity.Cancelled, *toSearchInput(AuctionSearchInputDTO{Status: "cancelled"}).Status)
+
+	assert.Nil(t, toSearchInput(AuctionSearchInputDTO{}).Status)
+}
@@ -0,0 +1,55 @@
+package wallet_controller
+
+import (
+	"net/http"
+
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/usecase/wallet_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type WalletController struct {
+	walletUseCase *wallet_usecase.WalletUseCase
+}
+
+func NewWalletController(walletUseCase *wallet_usecase.WalletUseCase) *WalletController {
+	return &WalletController{
+		walletUseCase: walletUseCase,
+	}
+}
+
+func (wc *WalletController) FindWallet(c *gin.Context) {
+	output, internalErr := wc.walletUseCase.FindWallet(c.Request.Context(), c.Param("userId"))
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (wc *WalletController) Deposit(c *gin.Context) {
+	var input wallet_usecase.DepositInputDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		problem.RespondBindingError(c, err)
+		return
+	}
+
+	output, internalErr := wc.walletUseCase.Deposit(c.Request.Context(), c.Param("userId"), input)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, output)
+}
+
+func (wc *WalletController) FindLedger(c *gin.Context) {
+	output, internalErr := wc.walletUseCase.FindLedger(c.Request.Context(), c.Param("userId"))
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
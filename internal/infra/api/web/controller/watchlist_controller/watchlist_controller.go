@@ -0,0 +1,55 @@
+package watchlist_controller
+
+import (
+	"net/http"
+
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/usecase/watchlist_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type WatchlistController struct {
+	watchlistUseCase *watchlist_usecase.WatchlistUseCase
+}
+
+func NewWatchlistController(watchlistUseCase *watchlist_usecase.WatchlistUseCase) *WatchlistController {
+	return &WatchlistController{
+		watchlistUseCase: watchlistUseCase,
+	}
+}
+
+func (wc *WatchlistController) AddToWatchlist(c *gin.Context) {
+	userId := c.Param("userId")
+	auctionId := c.Param("auctionId")
+
+	if internalErr := wc.watchlistUseCase.AddToWatchlist(c.Request.Context(), userId, auctionId); internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (wc *WatchlistController) RemoveFromWatchlist(c *gin.Context) {
+	userId := c.Param("userId")
+	auctionId := c.Param("auctionId")
+
+	if internalErr := wc.watchlistUseCase.RemoveFromWatchlist(c.Request.Context(), userId, auctionId); internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (wc *WatchlistController) FindWatchlist(c *gin.Context) {
+	userId := c.Param("userId")
+
+	output, internalErr := wc.watchlistUseCase.FindWatchlist(c.Request.Context(), userId)
+	if internalErr != nil {
+		problem.Respond(c, internalErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
@@ -0,0 +1,171 @@
+package health
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+)
+
+// Check é uma dependência verificada pelo /readyz e pelo /status
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Handler responde os endpoints usados pelo orquestrador e pelo monitoramento
+type Handler struct {
+	timeout   time.Duration
+	checker   entity.ExpirationCheckerInterface
+	checks    []Check
+	startedAt time.Time
+	now       func() time.Time
+}
+
+// NewHandler cria o handler; cada verificação tem até timeout para responder
+func NewHandler(timeout time.Duration, checker entity.ExpirationCheckerInterface, checks ...Check) *Handler {
+	return &Handler{
+		timeout:   timeout,
+		checker:   checker,
+		checks:    checks,
+		startedAt: time.Now(),
+		now:       time.Now,
+	}
+}
+
+type CheckResultDTO struct {
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+type ReadinessDTO struct {
+	Status string                    `json:"status"`
+	Checks map[string]CheckResultDTO `json:"checks"`
+}
+
+type ExpirationCheckerDTO struct {
+	Status          string     `json:"status"`
+	Leader          bool       `json:"leader"`
+	IntervalSeconds float64    `json:"interval_seconds"`
+	LastRunAt       *time.Time `json:"last_run_at"`
+	LastSuccessAt   *time.Time `json:"last_success_at"`
+	LastError       string     `json:"last_error,omitempty"`
+	LagSeconds      float64    `json:"lag_seconds"`
+}
+
+type StatusDTO struct {
+	Status            string                    `json:"status"`
+	StartedAt         time.Time                 `json:"started_at"`
+	UptimeSeconds     int64                     `json:"uptime_seconds"`
+	Checks            map[string]CheckResultDTO `json:"checks"`
+	ExpirationChecker ExpirationCheckerDTO      `json:"expiration_checker"`
+}
+
+// Liveness indica apenas que o processo está de pé e respondendo
+func (h *Handler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": StatusOK})
+}
+
+// Readiness verifica as dependências; com qualquer uma falhando responde 503 para
+// que a réplica saia do balanceamento
+func (h *Handler) Readiness(c *gin.Context) {
+	status, checks := h.runChecks(c.Request.Context())
+
+	c.JSON(httpStatus(status), ReadinessDTO{Status: status, Checks: checks})
+}
+
+// Status detalha as dependências e o verificador de leilões expirados. Responde 503
+// se alguma dependência falhar e "degraded" se o verificador estiver atrasado.
+func (h *Handler) Status(c *gin.Context) {
+	ctx := c.Request.Context()
+	status, checks := h.runChecks(ctx)
+
+	checker := h.checkerStatus(ctx)
+	if status == StatusOK && checker.Status != StatusOK {
+		status = StatusDegraded
+	}
+
+	now := h.now()
+	c.JSON(httpStatus(status), StatusDTO{
+		Status:            status,
+		StartedAt:         h.startedAt.UTC(),
+		UptimeSeconds:     int64(now.Sub(h.startedAt).Seconds()),
+		Checks:            checks,
+		ExpirationChecker: checker,
+	})
+}
+
+func (h *Handler) runChecks(ctx context.Context) (string, map[string]CheckResultDTO) {
+	status := StatusOK
+	results := make(map[string]CheckResultDTO, len(h.checks))
+
+	for _, check := range h.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, h.timeout)
+		started := h.now()
+		err := check.Run(checkCtx)
+		cancel()
+
+		result := CheckResultDTO{Status: StatusOK, DurationMs: h.now().Sub(started).Milliseconds()}
+		if err != nil {
+			status = StatusDown
+			result.Status = StatusDown
+			result.Error = err.Error()
+		}
+		results[check.Name] = result
+	}
+
+	return status, results
+}
+
+func (h *Handler) checkerStatus(ctx context.Context) ExpirationCheckerDTO {
+	checkCtx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	status, err := h.checker.ExpirationCheckerStatus(checkCtx)
+
+	dto := ExpirationCheckerDTO{
+		Status:          StatusOK,
+		Leader:          status.Leader,
+		IntervalSeconds: status.Interval.Seconds(),
+		LastRunAt:       optionalTime(status.LastRunAt),
+		LastSuccessAt:   optionalTime(status.LastSuccessAt),
+		LastError:       status.LastError,
+		LagSeconds:      math.Round(status.Lag.Seconds()*1000) / 1000,
+	}
+
+	if err != nil {
+		dto.Status = StatusDown
+		dto.LastError = err.Error()
+	} else if !status.Healthy(h.now()) {
+		dto.Status = StatusDegraded
+	}
+
+	return dto
+}
+
+func optionalTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+	utc := value.UTC()
+	return &utc
+}
+
+// httpStatus responde 503 apenas quando uma dependência está fora; atraso do
+// verificador não tira a réplica do ar
+func httpStatus(status string) int {
+	if status == StatusDown {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,87 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChecker struct {
+	status entity.ExpirationCheckerStatus
+}
+
+func (f fakeChecker) ExpirationCheckerStatus(ctx context.Context) (entity.ExpirationCheckerStatus, error) {
+	return f.status, nil
+}
+
+func get(handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", handler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	return recorder
+}
+
+func TestReadinessFailsWhenACheckFails(t *testing.T) {
+	now := time.Now()
+	checker := fakeChecker{entity.ExpirationCheckerStatus{Interval: time.Second, StartedAt: now}}
+
+	ok := NewHandler(time.Second, checker, Check{Name: "mongodb", Run: func(ctx context.Context) error { return nil }})
+	assert.Equal(t, http.StatusOK, get(ok.Readiness).Code)
+
+	failing := NewHandler(time.Second, checker,
+		Check{Name: "mongodb", Run: func(ctx context.Context) error { return nil }},
+		Check{Name: "migrations", Run: func(ctx context.Context) error { return errors.New("2 pending migration(s)") }},
+	)
+	recorder := get(failing.Readiness)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	var body ReadinessDTO
+	require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, StatusDown, body.Status)
+	assert.Equal(t, StatusOK, body.Checks["mongodb"].Status)
+	assert.Equal(t, "2 pending migration(s)", body.Checks["migrations"].Error)
+}
+
+func TestReadinessTimesOutSlowChecks(t *testing.T) {
+	handler := NewHandler(10*time.Millisecond, fakeChecker{}, Check{Name: "mongodb", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	assert.Equal(t, http.StatusServiceUnavailable, get(handler.Readiness).Code)
+}
+
+func TestStatusReportsLaggingChecker(t *testing.T) {
+	now := time.Now()
+	handler := NewHandler(time.Second, fakeChecker{entity.ExpirationCheckerStatus{
+		Leader:        true,
+		Interval:      10 * time.Second,
+		StartedAt:     now.Add(-time.Hour),
+		LastRunAt:     now.Add(-5 * time.Second),
+		LastSuccessAt: now.Add(-5 * time.Second),
+		Lag:           2 * time.Minute,
+	}})
+
+	recorder := get(handler.Status)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var body StatusDTO
+	require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, StatusDegraded, body.Status)
+	assert.Equal(t, StatusDegraded, body.ExpirationChecker.Status)
+	assert.True(t, body.ExpirationChecker.Leader)
+	assert.Equal(t, float64(120), body.ExpirationChecker.LagSeconds)
+	assert.NotNil(t, body.ExpirationChecker.LastSuccessAt)
+}
@@ -0,0 +1,174 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	KeyHeader      = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+
+	// lockDuration é quanto tempo uma requisição em andamento segura a chave antes
+	// que outra possa assumi-la (caso o processo tenha caído sem responder)
+	lockDuration = time.Minute
+)
+
+// Middleware torna a rota idempotente quando o cliente envia Idempotency-Key. A chave é
+// escopada pelo usuário (campo userField do corpo JSON) e pela operação ("auction", "bid"),
+// que é a mesma em todas as versões da API e no gRPC; a primeira resposta é guardada e
+// reenviada nas repetições. Reutilizar a chave com outro corpo, em outra versão ou pelo gRPC
+// retorna 422 e uma repetição enquanto a primeira ainda executa retorna 409. A resposta fica
+// guardada por ttl.
+func Middleware(repository entity.IdempotencyRepositoryInterface, operation, userField string, ttl time.Duration, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(KeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxKeyLength {
+			problem.Respond(c, internal_error.NewBadRequestError("Idempotency-Key must have at most 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			problem.Respond(c, internal_error.NewBadRequestError("could not read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		record := entity.IdempotencyRecord{
+			Id:          operation + ":" + userFromBody(body, userField) + ":" + key,
+			Owner:       uuid.New().String(),
+			RequestHash: requestHash(c.Request.Method, versionedPath(c.FullPath()), body),
+			LockedUntil: now.Add(lockDuration),
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		existing, err := repository.Reserve(c.Request.Context(), record)
+		if err != nil {
+			problem.Respond(c, internal_error.FromError(err))
+			return
+		}
+
+		if existing != nil {
+			replay(c, record, existing)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Usa um contexto próprio para gravar o resultado mesmo se o cliente desconectar
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Second)
+		defer cancel()
+
+		// Erros internos não são guardados, para que o cliente possa repetir a requisição
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := repository.Release(ctx, record.Id, record.Owner); err != nil {
+				logger.ErrorContext(ctx, "Error releasing idempotency key", slog.String("idempotency_key", record.Id), logging.Err(err))
+			}
+			return
+		}
+
+		err = repository.Complete(ctx, record.Id, record.Owner, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		if errors.Is(err, entity.ErrIdempotencyLockLost) {
+			logger.WarnContext(ctx, "Idempotency key taken over before the response was stored", slog.String("idempotency_key", record.Id))
+			return
+		}
+		if err != nil {
+			logger.ErrorContext(ctx, "Error storing idempotent response", slog.String("idempotency_key", record.Id), logging.Err(err))
+		}
+	}
+}
+
+func replay(c *gin.Context, record entity.IdempotencyRecord, existing *entity.IdempotencyRecord) {
+	if existing.RequestHash != record.RequestHash {
+		problem.Respond(c, &internal_error.InternalError{
+			Message: "Idempotency-Key was already used with a different request",
+			Err:     "idempotency_key_reused",
+			Code:    http.StatusUnprocessableEntity,
+		})
+		return
+	}
+
+	if existing.Status != entity.IdempotencyCompleted {
+		problem.Respond(c, &internal_error.InternalError{
+			Message: "a request with this Idempotency-Key is still being processed",
+			Err:     "idempotency_request_in_progress",
+			Code:    http.StatusConflict,
+		})
+		return
+	}
+
+	c.Header(ReplayedHeader, "true")
+	c.Data(existing.ResponseStatus, existing.ContentType, existing.ResponseBody)
+	c.Abort()
+}
+
+// userFromBody extrai o usuário dono da requisição; sem usuário a chave vale só pela rota
+func userFromBody(body []byte, userField string) string {
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return ""
+	}
+
+	user, _ := fields[userField].(string)
+	return user
+}
+
+// versionedPath trata os caminhos sem versão como os da v1, dos quais são aliases, para que a
+// repetição por um alias receba a resposta guardada; a v2 responde em outro formato e
+// continua sendo outra requisição
+func versionedPath(path string) string {
+	if strings.HasPrefix(path, "/v1/") || strings.HasPrefix(path, "/v2/") {
+		return path
+	}
+	return "/v1" + path
+}
+
+func requestHash(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copia o corpo da resposta enquanto ele é enviado ao cliente
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(data string) (int, error) {
+	r.body.WriteString(data)
+	return r.ResponseWriter.WriteString(data)
+}
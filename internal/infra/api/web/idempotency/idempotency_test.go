@@ -0,0 +1,186 @@
+package idempotency
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryRepository struct {
+	mu      sync.Mutex
+	records map[string]entity.IdempotencyRecord
+}
+
+func (m *memoryRepository) Reserve(ctx context.Context, record entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.records[record.Id]; ok {
+		return &existing, nil
+	}
+
+	record.Status = entity.IdempotencyProcessing
+	m.records[record.Id] = record
+	return nil, nil
+}
+
+func (m *memoryRepository) Complete(ctx context.Context, id, owner string, status int, contentType string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.records[id]
+	if !ok || record.Owner != owner || record.Status != entity.IdempotencyProcessing {
+		return entity.ErrIdempotencyLockLost
+	}
+	record.Status = entity.IdempotencyCompleted
+	record.ResponseStatus = status
+	record.ContentType = contentType
+	record.ResponseBody = body
+	m.records[id] = record
+	return nil
+}
+
+func (m *memoryRepository) Release(ctx context.Context, id, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if record, ok := m.records[id]; ok && record.Owner == owner {
+		delete(m.records, id)
+	}
+	return nil
+}
+
+func newRouter(executions *int32, delay time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	repository := &memoryRepository{records: make(map[string]entity.IdempotencyRecord)}
+
+	// Como em cmd/auction, a mesma operação em todas as versões e no alias sem versão
+	for _, path := range []string{"/bid", "/v1/bid", "/v2/bid"} {
+		router.POST(path, Middleware(repository, "bid", "user_id", time.Hour, slog.Default()), func(c *gin.Context) {
+			count := atomic.AddInt32(executions, 1)
+			time.Sleep(delay)
+			c.JSON(http.StatusCreated, gin.H{"execution": count})
+		})
+	}
+
+	return router
+}
+
+func post(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	return postTo(router, "/bid", key, body)
+}
+
+func postTo(router *gin.Engine, path, key, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request.Header.Set(KeyHeader, key)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestReplaysStoredResponse(t *testing.T) {
+	var executions int32
+	router := newRouter(&executions, 0)
+
+	first := post(router, "key-1", `{"user_id":"u1","amount":"10.00"}`)
+	second := post(router, "key-1", `{"user_id":"u1","amount":"10.00"}`)
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get(ReplayedHeader))
+	assert.Equal(t, int32(1), executions)
+
+	// A mesma chave de outro usuário é independente
+	assert.Equal(t, http.StatusCreated, post(router, "key-1", `{"user_id":"u2","amount":"10.00"}`).Code)
+	assert.Equal(t, int32(2), executions)
+}
+
+func TestRejectsKeyReuseWithDifferentBody(t *testing.T) {
+	var executions int32
+	router := newRouter(&executions, 0)
+
+	post(router, "key-1", `{"user_id":"u1","amount":"10.00"}`)
+	response := post(router, "key-1", `{"user_id":"u1","amount":"99.00"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Contains(t, response.Body.String(), "idempotency_key_reused")
+	assert.Equal(t, int32(1), executions)
+}
+
+func TestKeyIsScopedByOperationAcrossVersions(t *testing.T) {
+	var executions int32
+	router := newRouter(&executions, 0)
+	body := `{"user_id":"u1","amount":"10.00"}`
+
+	assert.Equal(t, http.StatusCreated, postTo(router, "/v1/bid", "key-1", body).Code)
+
+	// O caminho sem versão é alias da v1 e recebe a resposta guardada
+	alias := post(router, "key-1", body)
+	assert.Equal(t, http.StatusCreated, alias.Code)
+	assert.Equal(t, "true", alias.Header().Get(ReplayedHeader))
+
+	// Na v2 a chave já foi usada pela mesma operação, com outra requisição
+	response := postTo(router, "/v2/bid", "key-1", body)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Contains(t, response.Body.String(), "idempotency_key_reused")
+	assert.Equal(t, int32(1), executions)
+}
+
+func TestConcurrentRequestsExecuteOnce(t *testing.T) {
+	var executions int32
+	router := newRouter(&executions, 50*time.Millisecond)
+
+	var wg sync.WaitGroup
+	codes := make([]int, 5)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = post(router, "key-1", `{"user_id":"u1","amount":"10.00"}`).Code
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), executions)
+	assert.Contains(t, codes, http.StatusCreated)
+	for _, code := range codes {
+		assert.Contains(t, []int{http.StatusCreated, http.StatusConflict}, code)
+	}
+}
+
+func TestLateRequestDoesNotOverwriteTakenOverKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	repository := &memoryRepository{records: make(map[string]entity.IdempotencyRecord)}
+
+	// Enquanto a requisição executa, a reserva vence e é assumida por outra
+	router.POST("/bid", Middleware(repository, "bid", "user_id", time.Hour, slog.Default()), func(c *gin.Context) {
+		repository.mu.Lock()
+		for id, record := range repository.records {
+			record.Owner = "another-request"
+			repository.records[id] = record
+		}
+		repository.mu.Unlock()
+		c.JSON(http.StatusCreated, gin.H{"execution": 1})
+	})
+
+	assert.Equal(t, http.StatusCreated, post(router, "key-1", `{"user_id":"u1","amount":"10.00"}`).Code)
+
+	for _, record := range repository.records {
+		assert.Equal(t, "another-request", record.Owner)
+		assert.Equal(t, entity.IdempotencyProcessing, record.Status)
+		assert.Empty(t, record.ResponseBody)
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation marca as respostas de uma versão obsoleta da API com os cabeçalhos
+// Deprecation (RFC 9745), Sunset (RFC 8594) e o Link para a versão sucessora
+func Deprecation(deprecatedAt, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	link := "<" + successor + ">; rel=\"successor-version\""
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetDate)
+		c.Writer.Header().Add("Link", link)
+		c.Next()
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIdHeader = "X-Request-Id"
+	requestIdKey    = "request_id"
+)
+
+// RequestID reaproveita o X-Request-Id enviado pelo cliente (ou gera um novo),
+// devolve o valor no cabeçalho da resposta e o guarda no contexto do Gin e no
+// contexto da requisição, de onde os logs dos use cases e repositórios o leem
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(RequestIdHeader)
+		if requestId == "" || len(requestId) > 128 {
+			requestId = uuid.New().String()
+		}
+
+		c.Set(requestIdKey, requestId)
+		c.Request = c.Request.WithContext(logging.WithRequestId(c.Request.Context(), requestId))
+		c.Header(RequestIdHeader, requestId)
+		c.Next()
+	}
+}
+
+// GetRequestID retorna o id da requisição atual, ou "" fora do middleware
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIdKey)
+}
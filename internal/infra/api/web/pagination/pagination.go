@@ -0,0 +1,43 @@
+package pagination
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// ParsePageRequest lê os parâmetros limit, cursor, sort e order da query string
+func ParsePageRequest(c *gin.Context) (entity.PageRequest, error) {
+	page := entity.PageRequest{
+		Cursor: c.Query("cursor"),
+		SortBy: c.Query("sort"),
+		Order:  entity.SortOrder(c.Query("order")),
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 || value > entity.MaxPageLimit {
+			return page, errors.New("limit must be a number between 1 and " + strconv.Itoa(entity.MaxPageLimit))
+		}
+		page.Limit = value
+	}
+
+	return page, nil
+}
+
+// SetNextPageHeaders expõe o cursor da próxima página nos cabeçalhos X-Next-Cursor e Link
+func SetNextPageHeaders(c *gin.Context, nextCursor string) {
+	if nextCursor == "" {
+		return
+	}
+
+	next := *c.Request.URL
+	query := next.Query()
+	query.Set("cursor", nextCursor)
+	next.RawQuery = query.Encode()
+
+	c.Header("X-Next-Cursor", nextCursor)
+	c.Header("Link", "<"+next.RequestURI()+">; rel=\"next\"")
+}
@@ -0,0 +1,177 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/web/middleware"
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	ContentType = "application/problem+json"
+
+	// ValidationFailedCode é o código dos erros de binding com detalhes por campo
+	ValidationFailedCode = "validation_failed"
+	invalidRequestCode   = "invalid_request"
+)
+
+// Details é o corpo de erro no formato RFC 7807, acrescido do código estável do erro,
+// do id da requisição e, em erros de validação, dos campos inválidos
+type Details struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestId string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func init() {
+	// Os erros de validação usam o nome do campo em JSON/query em vez do nome do struct
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterTagNameFunc(fieldName)
+	}
+}
+
+// Respond escreve o erro da aplicação como application/problem+json. Erros internos
+// não expõem a mensagem original, que fica registrada no log com o id da requisição.
+// Como é chamado de todos os controllers, usa o logger padrão (slog.SetDefault no main).
+func Respond(c *gin.Context, internalErr *internal_error.InternalError) {
+	detail := internalErr.Message
+	if internalErr.Code >= http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "Internal error",
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("error", internalErr.Message))
+		detail = "an unexpected error occurred"
+	}
+
+	write(c, internalErr.Code, internalErr.Err, detail, nil)
+}
+
+// RespondBindingError traduz os erros de binding do Gin em um problema de validação
+func RespondBindingError(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields = append(fields, FieldError{
+				Field:   fieldPath(fieldErr),
+				Rule:    fieldErr.Tag(),
+				Message: ruleMessage(fieldErr),
+			})
+		}
+		write(c, http.StatusBadRequest, ValidationFailedCode, "request has invalid fields", fields)
+		return
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		fields := []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: "must be of type " + typeErr.Type.String(),
+		}}
+		write(c, http.StatusBadRequest, ValidationFailedCode, "request has invalid fields", fields)
+		return
+	}
+
+	var domainErr *entity.DomainError
+	if errors.As(err, &domainErr) {
+		Respond(c, internal_error.FromError(err))
+		return
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		write(c, http.StatusBadRequest, invalidRequestCode, "request body is not valid JSON", nil)
+		return
+	}
+
+	write(c, http.StatusBadRequest, invalidRequestCode, err.Error(), nil)
+}
+
+// NotFound responde rotas inexistentes no mesmo formato dos demais erros
+func NotFound(c *gin.Context) {
+	write(c, http.StatusNotFound, "route_not_found", "route "+c.Request.Method+" "+c.Request.URL.Path+" does not exist", nil)
+}
+
+// Recovery converte panics em erro interno no formato problem+json
+func Recovery(c *gin.Context, recovered any) {
+	Respond(c, internal_error.NewInternalServerError(fmt.Sprint(recovered)))
+}
+
+func write(c *gin.Context, status int, code, detail string, fields []FieldError) {
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(status, Details{
+		Type:      "/problems/" + code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestId: middleware.GetRequestID(c),
+		Errors:    fields,
+	})
+}
+
+// fieldPath remove o nome do struct do namespace ("BidInputDTO.amount" vira "amount")
+func fieldPath(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+func ruleMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fieldErr.Param()), ", ")
+	case "min", "gte":
+		return "must be at least " + fieldErr.Param()
+	case "max", "lte":
+		return "must be at most " + fieldErr.Param()
+	case "gt":
+		return "must be greater than " + fieldErr.Param()
+	case "email":
+		return "must be a valid email address"
+	case "nefield":
+		return "must be different from " + fieldErr.Param()
+	default:
+		return "failed the " + fieldErr.Tag() + " rule"
+	}
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,81 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/web/middleware"
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type bidInput struct {
+	AuctionId string          `json:"auction_id" binding:"required"`
+	Currency  entity.Currency `json:"currency" binding:"omitempty,oneof=BRL USD"`
+}
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID())
+
+	router.POST("/bid", func(c *gin.Context) {
+		var input bidInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			RespondBindingError(c, err)
+			return
+		}
+		Respond(c, internal_error.FromError(entity.ErrBidTooLow))
+	})
+	router.GET("/boom", func(c *gin.Context) {
+		Respond(c, internal_error.NewInternalServerError("mongo: connection refused"))
+	})
+
+	return router
+}
+
+func perform(router *gin.Engine, method, path, body string) (*httptest.ResponseRecorder, Details) {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set(middleware.RequestIdHeader, "req-123")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var details Details
+	_ = json.Unmarshal(recorder.Body.Bytes(), &details)
+	return recorder, details
+}
+
+func TestRespondBindingErrorListsInvalidFields(t *testing.T) {
+	recorder, details := perform(newRouter(), http.MethodPost, "/bid", `{"currency":"EUR"}`)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, ContentType, recorder.Header().Get("Content-Type"))
+	assert.Equal(t, ValidationFailedCode, details.Code)
+	assert.Equal(t, "req-123", details.RequestId)
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "auction_id", Rule: "required", Message: "is required"},
+		{Field: "currency", Rule: "oneof", Message: "must be one of: BRL, USD"},
+	}, details.Errors)
+}
+
+func TestRespondMapsDomainErrors(t *testing.T) {
+	recorder, details := perform(newRouter(), http.MethodPost, "/bid", `{"auction_id":"a1"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "bid_too_low", details.Code)
+	assert.Equal(t, "/problems/bid_too_low", details.Type)
+	assert.Equal(t, "/bid", details.Instance)
+}
+
+func TestRespondHidesInternalErrors(t *testing.T) {
+	recorder, details := perform(newRouter(), http.MethodGet, "/boom", "")
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "internal_server_error", details.Code)
+	assert.NotContains(t, details.Detail, "mongo")
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+)
+
+// sweepInterval é a frequência com que buckets já cheios (inativos) são descartados
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	bucket entity.RateLimitBucket
+	policy entity.RateLimitPolicy
+}
+
+// MemoryStore guarda os buckets na memória do processo. Cada réplica tem seus
+// próprios limites; para compartilhar entre réplicas use o store do MongoDB.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*memoryEntry),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (ms *MemoryStore) Take(ctx context.Context, key string, policy entity.RateLimitPolicy) (entity.RateLimitResult, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := ms.now()
+	if now.Sub(ms.lastSweep) >= sweepInterval {
+		ms.sweep(now)
+	}
+
+	entry, ok := ms.buckets[key]
+	if !ok {
+		entry = &memoryEntry{bucket: entity.NewRateLimitBucket(policy, now)}
+		ms.buckets[key] = entry
+	}
+	entry.policy = policy
+
+	return entry.bucket.Take(policy, now), nil
+}
+
+// sweep remove os buckets que já teriam voltado a ficar cheios, que equivalem a um bucket novo
+func (ms *MemoryStore) sweep(now time.Time) {
+	for key, entry := range ms.buckets {
+		if now.Sub(entry.bucket.UpdatedAt) >= entry.policy.FullAfter() {
+			delete(ms.buckets, key)
+		}
+	}
+	ms.lastSweep = now
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	LimitHeader      = "RateLimit-Limit"
+	RemainingHeader  = "RateLimit-Remaining"
+	ResetHeader      = "RateLimit-Reset"
+	PolicyHeader     = "RateLimit-Policy"
+	RetryAfterHeader = "Retry-After"
+)
+
+// KeyFunc identifica quem está fazendo a requisição; "" deixa a requisição fora do limite
+type KeyFunc func(c *gin.Context) string
+
+// ClientIP identifica a requisição pelo IP do cliente (respeitando os proxies confiáveis do Gin)
+func ClientIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// UserFromBody identifica a requisição pelo usuário informado no campo userField do corpo JSON
+func UserFromBody(userField string) KeyFunc {
+	return func(c *gin.Context) string {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return ""
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var fields map[string]any
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return ""
+		}
+
+		user, _ := fields[userField].(string)
+		return user
+	}
+}
+
+// Middleware aplica a política ao bucket de cada identidade na rota. name separa os
+// buckets de rotas e identidades diferentes (por exemplo "bid:user" e "bid:ip").
+// Se o backend falhar a requisição segue sem limite, para não derrubar os lances.
+func Middleware(limiter entity.RateLimiterInterface, name string, policy entity.RateLimitPolicy, key KeyFunc, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !policy.Enabled() {
+			c.Next()
+			return
+		}
+
+		identity := key(c)
+		if identity == "" {
+			c.Next()
+			return
+		}
+
+		result, err := limiter.Take(c.Request.Context(), name+":"+identity, policy)
+		if err != nil {
+			logger.ErrorContext(c.Request.Context(), "Error checking rate limit", slog.String("limit", name), logging.Err(err))
+			c.Next()
+			return
+		}
+
+		writeHeaders(c, policy, result)
+
+		if !result.Allowed {
+			c.Header(RetryAfterHeader, strconv.FormatInt(seconds(result.RetryAfter), 10))
+			problem.Respond(c, &internal_error.InternalError{
+				Message: "too many requests, retry later",
+				Err:     "rate_limited",
+				Code:    http.StatusTooManyRequests,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// writeHeaders publica os cabeçalhos RateLimit-*. Com mais de um limite na mesma rota,
+// prevalece o que tem menos requisições restantes.
+func writeHeaders(c *gin.Context, policy entity.RateLimitPolicy, result entity.RateLimitResult) {
+	if current := c.Writer.Header().Get(RemainingHeader); current != "" {
+		if remaining, err := strconv.ParseInt(current, 10, 64); err == nil && remaining <= result.Remaining && result.Allowed {
+			return
+		}
+	}
+
+	c.Header(LimitHeader, strconv.FormatInt(result.Limit, 10))
+	c.Header(RemainingHeader, strconv.FormatInt(result.Remaining, 10))
+	c.Header(ResetHeader, strconv.FormatInt(seconds(result.ResetAfter), 10))
+	c.Header(PolicyHeader, fmt.Sprintf("%d;w=%d", policy.Capacity, seconds(policy.FullAfter())))
+}
+
+// seconds arredonda para cima, para o cliente não repetir antes da hora
+func seconds(duration time.Duration) int64 {
+	return int64(math.Ceil(duration.Seconds()))
+}
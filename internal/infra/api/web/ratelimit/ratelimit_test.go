@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRouter(store *MemoryStore, policy entity.RateLimitPolicy) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/bid",
+		Middleware(store, "bid:ip", policy, ClientIP, slog.Default()),
+		Middleware(store, "bid:user", policy, UserFromBody("user_id"), slog.Default()),
+		func(c *gin.Context) {
+			var body map[string]any
+			if err := c.ShouldBindJSON(&body); err != nil {
+				c.Status(http.StatusBadRequest)
+				return
+			}
+			c.Status(http.StatusCreated)
+		})
+	return router
+}
+
+func post(router *gin.Engine, ip, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/bid", strings.NewReader(body))
+	request.RemoteAddr = ip + ":1234"
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestLimitsPerUserAndRefills(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	router := newRouter(store, entity.RateLimitPolicy{Capacity: 2, RefillEvery: 10 * time.Second})
+
+	first := post(router, "10.0.0.1", `{"user_id":"u1"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, "2", first.Header().Get(LimitHeader))
+	assert.Equal(t, "1", first.Header().Get(RemainingHeader))
+	assert.Equal(t, "2;w=20", first.Header().Get(PolicyHeader))
+
+	// O mesmo usuário vindo de outro IP continua no mesmo bucket de usuário
+	assert.Equal(t, http.StatusCreated, post(router, "10.0.0.2", `{"user_id":"u1"}`).Code)
+
+	limited := post(router, "10.0.0.3", `{"user_id":"u1"}`)
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "10", limited.Header().Get(RetryAfterHeader))
+	assert.Equal(t, "0", limited.Header().Get(RemainingHeader))
+	assert.Contains(t, limited.Body.String(), "rate_limited")
+
+	// O corpo continua disponível para o handler depois da leitura do usuário
+	assert.Equal(t, http.StatusCreated, post(router, "10.0.0.3", `{"user_id":"u2"}`).Code)
+
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, http.StatusCreated, post(router, "10.0.0.4", `{"user_id":"u1"}`).Code)
+}
+
+func TestLimitsPerIP(t *testing.T) {
+	store := NewMemoryStore()
+	router := newRouter(store, entity.RateLimitPolicy{Capacity: 1, RefillEvery: time.Minute})
+
+	assert.Equal(t, http.StatusCreated, post(router, "10.0.0.1", `{"user_id":"u1"}`).Code)
+	assert.Equal(t, http.StatusTooManyRequests, post(router, "10.0.0.1", `{"user_id":"u2"}`).Code)
+	assert.Equal(t, http.StatusCreated, post(router, "10.0.0.2", `{"user_id":"u3"}`).Code)
+}
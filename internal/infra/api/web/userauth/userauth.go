@@ -0,0 +1,63 @@
+package userauth
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/api/web/problem"
+	"github.com/auction-goexpert/internal/internal_error"
+	"github.com/gin-gonic/gin"
+)
+
+type userToken struct {
+	token  []byte
+	userId string
+}
+
+// Middleware exige um "Authorization: Bearer <token>" de tokens, que associa cada token ao
+// usuário. O usuário fica no contexto da requisição como autor (veja UserId) e no log de
+// auditoria. Sem tokens configurados, todas as requisições são recusadas.
+func Middleware(tokens map[string]string) gin.HandlerFunc {
+	known := make([]userToken, 0, len(tokens))
+	for token, userId := range tokens {
+		known = append(known, userToken{token: []byte(token), userId: userId})
+	}
+
+	return func(c *gin.Context) {
+		userId, ok := authenticate(known, c.GetHeader("Authorization"))
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer realm="user"`)
+			problem.Respond(c, internal_error.NewUnauthorizedError("missing or invalid user token"))
+			return
+		}
+
+		c.Request = c.Request.WithContext(entity.WithActor(c.Request.Context(), userId))
+		c.Next()
+	}
+}
+
+// UserId retorna o usuário autenticado por Middleware, ou "" se a rota não exige autenticação
+func UserId(c *gin.Context) string {
+	userId := entity.ActorFromContext(c.Request.Context())
+	if userId == entity.SystemActor {
+		return ""
+	}
+	return userId
+}
+
+// authenticate compara o token com todos os conhecidos em tempo constante, como em adminauth
+func authenticate(known []userToken, header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+
+	var userId string
+	for _, candidate := range known {
+		if subtle.ConstantTimeCompare(candidate.token, []byte(token)) == 1 {
+			userId = candidate.userId
+		}
+	}
+	return userId, userId != ""
+}
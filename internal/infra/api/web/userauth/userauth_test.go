@@ -0,0 +1,39 @@
+package userauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareTakesUserFromToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware(map[string]string{"0123456789abcdef": "user-123"}))
+	router.POST("/auction/:auctionId/settlement/pay", func(c *gin.Context) {
+		c.String(http.StatusOK, UserId(c))
+	})
+
+	perform := func(authorization string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/auction/auction-1/settlement/pay", nil)
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := perform("Bearer 0123456789abcdef")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "user-123", recorder.Body.String())
+
+	for _, authorization := range []string{"", "Bearer wrong-token-value", "Basic 0123456789abcdef", "Bearer "} {
+		recorder := perform(authorization)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, authorization)
+		assert.Equal(t, `Bearer realm="user"`, recorder.Header().Get("WWW-Authenticate"))
+	}
+}
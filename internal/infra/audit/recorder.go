@@ -0,0 +1,170 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// pendingRetryInterval é o intervalo entre as tentativas de encadear os registros que
+// ficaram pendentes
+const pendingRetryInterval = 30 * time.Second
+
+// Recorder grava no log de auditoria as mudanças de estado avisadas pelos repositórios
+// e use cases, e as ações administrativas feitas pela API
+type Recorder struct {
+	repository entity.AuditRepositoryInterface
+	logger     *slog.Logger
+}
+
+func NewRecorder(repository entity.AuditRepositoryInterface, logger *slog.Logger) *Recorder {
+	return &Recorder{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Start inicia a goroutine que encadeia os registros pendentes a cada pendingRetryInterval
+func (r *Recorder) Start() {
+	go func() {
+		ticker := time.NewTicker(pendingRetryInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			r.retryPending(context.Background())
+		}
+	}()
+}
+
+// retryPending encadeia os registros pendentes do mais antigo para o mais novo. Depois de uma
+// falha, os demais registros do mesmo stream esperam a próxima rodada, para manter a ordem.
+func (r *Recorder) retryPending(ctx context.Context) {
+	entries, err := r.repository.FindPendingEntries(ctx)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error finding pending audit entries", logging.Err(err))
+		return
+	}
+
+	failed := make(map[string]bool)
+	for _, entry := range entries {
+		if failed[entry.Stream] {
+			continue
+		}
+		if _, err := r.repository.Append(ctx, entry); err != nil {
+			failed[entry.Stream] = true
+			r.logger.WarnContext(ctx, "Error appending pending audit entry",
+				slog.String("stream", entry.Stream), slog.String("action", string(entry.Action)), logging.Err(err))
+			continue
+		}
+		if err := r.repository.RemovePending(ctx, entry.Id); err != nil {
+			r.logger.ErrorContext(ctx, "Error removing pending audit entry",
+				slog.String("stream", entry.Stream), slog.String("action", string(entry.Action)), logging.Err(err))
+		}
+	}
+}
+
+// OnAuctionCreated registra a criação do leilão, com o vendedor como autor
+func (r *Recorder) OnAuctionCreated(ctx context.Context, auction entity.Auction) {
+	actor := auction.SellerId
+	if actor == "" {
+		actor = entity.AnonymousActor
+	}
+	r.record(ctx, auction.Id, entity.AuditAuctionCreated, actor, nil, entity.AuctionSnapshot(auction), "")
+}
+
+// OnAuctionStatusChanged registra a mudança de status; o autor vem do contexto
+// (o verificador de expiração se identifica como system:expiration-checker)
+func (r *Recorder) OnAuctionStatusChanged(ctx context.Context, before, after entity.Auction) {
+	r.record(ctx, after.Id, entity.AuditAuctionStatusChanged, entity.ActorFromContext(ctx),
+		entity.AuctionSnapshot(before), entity.AuctionSnapshot(after), "")
+}
+
+// OnBidPlaced registra o lance aceito; o snapshot anterior é o maior lance que ele superou
+func (r *Recorder) OnBidPlaced(ctx context.Context, bid entity.Bid, previousHighest *entity.Bid) {
+	var before json.RawMessage
+	if previousHighest != nil {
+		before = entity.BidSnapshot(*previousHighest)
+	}
+	r.record(ctx, bid.AuctionId, entity.AuditBidAccepted, bid.UserId, before, entity.BidSnapshot(bid), "")
+}
+
+// OnBidRejected registra o lance recusado com o código do erro como motivo
+func (r *Recorder) OnBidRejected(ctx context.Context, rejection entity.BidRejection) {
+	attempt := entity.BidSnapshot(entity.Bid{
+		UserId:    rejection.UserId,
+		AuctionId: rejection.AuctionId,
+		Amount:    rejection.Amount,
+	})
+	r.record(ctx, rejection.AuctionId, entity.AuditBidRejected, rejection.UserId, nil, attempt, rejection.Reason)
+}
+
+// AdminActions registra cada requisição que altera dados nas rotas administrativas, com
+// o operador autenticado por adminauth.Middleware, o corpo enviado e o status da resposta
+func (r *Recorder) AdminActions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		actor := entity.ActorFromContext(c.Request.Context())
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			body = nil
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		c.Next()
+
+		action := struct {
+			Method string          `json:"method"`
+			Route  string          `json:"route"`
+			Path   string          `json:"path"`
+			Status int             `json:"status"`
+			Body   json.RawMessage `json:"body,omitempty"`
+		}{
+			Method: c.Request.Method,
+			Route:  c.FullPath(),
+			Path:   c.Request.URL.Path,
+			Status: c.Writer.Status(),
+		}
+		if json.Valid(body) {
+			action.Body = body
+		}
+		after, _ := json.Marshal(action)
+
+		r.record(c.Request.Context(), entity.AuditAdminStream, entity.AuditAdminAction, actor, nil, after, "")
+	}
+}
+
+// record grava o registro mesmo que a requisição tenha sido cancelada. Falhas não
+// interrompem a operação auditada: se o encadeamento falhar, o registro fica pendente e é
+// encadeado por retryPending.
+func (r *Recorder) record(ctx context.Context, stream string, action entity.AuditAction, actor string, before, after json.RawMessage, reason string) {
+	entry, err := entity.NewAuditEntry(stream, action, actor, before, after, reason)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error recording audit entry",
+			slog.String("stream", stream), slog.String("action", string(action)), logging.Err(err))
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	if _, err := r.repository.Append(ctx, entry); err != nil {
+		r.logger.WarnContext(ctx, "Error appending audit entry, keeping it pending",
+			slog.String("stream", stream), slog.String("action", string(action)), logging.Err(err))
+
+		if err := r.repository.EnqueuePending(ctx, entry); err != nil {
+			r.logger.ErrorContext(ctx, "Error recording audit entry",
+				slog.String("stream", stream), slog.String("action", string(action)), logging.Err(err))
+		}
+	}
+}
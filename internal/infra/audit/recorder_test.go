@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditRepository struct {
+	entity.AuditRepositoryInterface
+	failures int
+	entries  []entity.AuditEntry
+	pending  []entity.AuditEntry
+}
+
+func (r *auditRepository) Append(ctx context.Context, entry entity.AuditEntry) (*entity.AuditEntry, error) {
+	if r.failures > 0 {
+		r.failures--
+		return nil, errors.New("audit log unavailable")
+	}
+	r.entries = append(r.entries, entry)
+	return &entry, nil
+}
+
+func (r *auditRepository) EnqueuePending(ctx context.Context, entry entity.AuditEntry) error {
+	r.pending = append(r.pending, entry)
+	return nil
+}
+
+func (r *auditRepository) FindPendingEntries(ctx context.Context) ([]entity.AuditEntry, error) {
+	return append([]entity.AuditEntry(nil), r.pending...), nil
+}
+
+func (r *auditRepository) RemovePending(ctx context.Context, id string) error {
+	for i, entry := range r.pending {
+		if entry.Id == id {
+			r.pending = append(r.pending[:i], r.pending[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestFailedAuditEntryIsKeptPendingUntilAppended(t *testing.T) {
+	repository := &auditRepository{failures: 1}
+	recorder := NewRecorder(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	recorder.OnAuctionCreated(ctx, entity.Auction{Id: "auction-1", SellerId: "seller-1"})
+	assert.Empty(t, repository.entries)
+	require.Len(t, repository.pending, 1)
+
+	recorder.retryPending(ctx)
+	assert.Empty(t, repository.pending)
+	require.Len(t, repository.entries, 1)
+	assert.Equal(t, entity.AuditAuctionCreated, repository.entries[0].Action)
+	assert.Equal(t, "seller-1", repository.entries[0].Actor)
+}
+
+func TestRetryPendingKeepsTheOrderOfAStream(t *testing.T) {
+	repository := &auditRepository{failures: 3}
+	recorder := NewRecorder(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	recorder.OnAuctionCreated(ctx, entity.Auction{Id: "auction-1", SellerId: "seller-1"})
+	recorder.OnBidPlaced(ctx, entity.Bid{Id: "bid-1", AuctionId: "auction-1", UserId: "user-1"}, nil)
+	recorder.OnAuctionCreated(ctx, entity.Auction{Id: "auction-2", SellerId: "seller-2"})
+	require.Len(t, repository.pending, 3)
+
+	// A criação do primeiro leilão falha de novo: o lance espera, o outro stream segue
+	repository.failures = 1
+	recorder.retryPending(ctx)
+	require.Len(t, repository.entries, 1)
+	assert.Equal(t, "auction-2", repository.entries[0].Stream)
+	require.Len(t, repository.pending, 2)
+
+	recorder.retryPending(ctx)
+	assert.Empty(t, repository.pending)
+	require.Len(t, repository.entries, 3)
+	assert.Equal(t, entity.AuditAuctionCreated, repository.entries[1].Action)
+	assert.Equal(t, entity.AuditBidAccepted, repository.entries[2].Action)
+}
@@ -0,0 +1,42 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/usecase/invoice_usecase"
+)
+
+// Invoicer emite as faturas dos leilões encerrados pelo fechamento automático e as reemite
+// quando o acerto passa para outro comprador. Uma falha é devolvida ao encerramento ou à
+// tentativa de acerto, que ficam pendentes e chamam o Invoicer de novo; emitir e reemitir
+// podem ser repetidos.
+type Invoicer struct {
+	invoiceUseCase *invoice_usecase.InvoiceUseCase
+	logger         *slog.Logger
+}
+
+func NewInvoicer(invoiceUseCase *invoice_usecase.InvoiceUseCase, logger *slog.Logger) *Invoicer {
+	return &Invoicer{
+		invoiceUseCase: invoiceUseCase,
+		logger:         logger,
+	}
+}
+
+// OnAuctionClosed é registrado como AuctionClosedListener no repositório de leilões
+func (i *Invoicer) OnAuctionClosed(ctx context.Context, auction entity.Auction) error {
+	if err := i.invoiceUseCase.IssueInvoices(ctx, auction); err != nil {
+		return fmt.Errorf("issuing auction invoices: %w", err)
+	}
+	return nil
+}
+
+// OnSettlementOffered é registrado como SettlementOfferedListener no use case de acerto
+func (i *Invoicer) OnSettlementOffered(ctx context.Context, settlement entity.Settlement) error {
+	if err := i.invoiceUseCase.ReissueInvoices(ctx, settlement); err != nil {
+		return fmt.Errorf("reissuing invoices of user %s: %w", settlement.WinnerId, err)
+	}
+	return nil
+}
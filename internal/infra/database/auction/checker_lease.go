@@ -0,0 +1,61 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const checkerLeaseId = "auction_expiration_checker"
+
+// checkerLease elege a réplica que encerra os leilões expirados. O documento guarda o dono e
+// quando a concessão expira; o líder renova a cada execução e, se ele morrer, outra réplica
+// assume depois de ttl.
+type checkerLease struct {
+	collection *mongo.Collection
+	owner      string
+	ttl        time.Duration
+}
+
+func newCheckerLease(database *mongo.Database, ttl time.Duration) *checkerLease {
+	hostname, _ := os.Hostname()
+
+	return &checkerLease{
+		collection: database.Collection("auction_checker_lease"),
+		owner:      hostname + "-" + uuid.New().String(),
+		ttl:        ttl,
+	}
+}
+
+// acquire obtém ou renova a concessão e informa se esta réplica é a líder
+func (l *checkerLease) acquire(ctx context.Context, now time.Time) (bool, error) {
+	filter := bson.M{
+		"_id": checkerLeaseId,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$lt": now.UnixMilli()}},
+			bson.M{"owner": l.owner},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"owner":      l.owner,
+			"expires_at": now.Add(l.ttl).UnixMilli(),
+		},
+	}
+
+	_, err := l.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err == nil {
+		return true, nil
+	}
+
+	// Outra réplica detém a concessão: o upsert colide com o _id existente
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return false, err
+}
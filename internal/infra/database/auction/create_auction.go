@@ -2,119 +2,291 @@ package auction
 
 import (
 	"context"
-	"log"
-	"os"
-	"strconv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
 	"sync"
 	"time"
 
 	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/database/event_store"
+	"github.com/auction-goexpert/internal/infra/database/keyset"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/auction-goexpert/internal/infra/metrics"
+	"github.com/auction-goexpert/internal/infra/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// AuctionRepository lê a projeção auctions; as alterações são comandos no agregado do
+// leilão, gravados como eventos no event store
 type AuctionRepository struct {
-	Collection *mongo.Collection
-	mu         sync.RWMutex
+	Collection    *mongo.Collection
+	events        *event_store.AuctionEventStore
+	duration      time.Duration
+	checkInterval time.Duration
+	mu            sync.RWMutex
+	listeners     []entity.AuctionClosedListener
+	// createdListeners e statusListeners só são alterados na inicialização
+	createdListeners []entity.AuctionCreatedListener
+	statusListeners  []entity.AuctionStatusChangedListener
+	checker          checkerState
+	lease            *checkerLease
+	logger           *slog.Logger
 }
 
-func NewAuctionRepository(database *mongo.Database) *AuctionRepository {
+// checkerState guarda as execuções do verificador de leilões expirados, para o /status
+type checkerState struct {
+	mu            sync.Mutex
+	leader        bool
+	startedAt     time.Time
+	lastRunAt     time.Time
+	lastSuccessAt time.Time
+	lastError     string
+}
+
+// NewAuctionRepository cria o repositório; duration é a duração de cada leilão e
+// checkInterval o intervalo da verificação de leilões expirados
+func NewAuctionRepository(database *mongo.Database, events *event_store.AuctionEventStore, duration, checkInterval time.Duration, logger *slog.Logger) *AuctionRepository {
 	repo := &AuctionRepository{
-		Collection: database.Collection("auctions"),
+		Collection:    database.Collection("auctions"),
+		events:        events,
+		duration:      duration,
+		checkInterval: checkInterval,
+		lease:         newCheckerLease(database, 3*checkInterval),
+		logger:        logger,
 	}
 
 	// Inicia a goroutine para verificar leilões expirados
+	repo.checker.startedAt = time.Now()
 	go repo.startAuctionExpirationChecker()
 
 	return repo
 }
 
-// CreateAuction cria um novo leilão e calcula o tempo de expiração
-func (ar *AuctionRepository) CreateAuction(ctx context.Context, auction *entity.Auction) error {
+// AddClosedListener registra uma função chamada a cada leilão encerrado, pelo fechamento
+// automático ou por UpdateAuctionStatus. Enquanto algum listener retornar erro, o
+// verificador de leilões expirados chama todos de novo.
+func (ar *AuctionRepository) AddClosedListener(listener entity.AuctionClosedListener) {
 	ar.mu.Lock()
 	defer ar.mu.Unlock()
 
-	// Calcula o tempo de duração do leilão baseado na variável de ambiente
-	duration := calculateAuctionDuration()
-	auction.ExpiresAt = time.Now().Add(duration)
+	ar.listeners = append(ar.listeners, listener)
+}
+
+// AddCreatedListener registra uma função chamada a cada leilão criado.
+// Deve ser chamado durante a inicialização, antes de o repositório receber leilões.
+func (ar *AuctionRepository) AddCreatedListener(listener entity.AuctionCreatedListener) {
+	ar.createdListeners = append(ar.createdListeners, listener)
+}
 
-	auctionEntityMongo := &entity.AuctionEntityMongo{
-		Id:          auction.Id,
-		ProductName: auction.ProductName,
-		Category:    auction.Category,
-		Description: auction.Description,
-		Condition:   auction.Condition,
-		Status:      auction.Status,
-		Timestamp:   auction.Timestamp.Unix(),
-		ExpiresAt:   auction.ExpiresAt.Unix(),
+// AddStatusChangedListener registra uma função chamada a cada mudança de status, feita
+// por UpdateAuctionStatus ou pelo encerramento automático. Deve ser chamado durante a
+// inicialização.
+func (ar *AuctionRepository) AddStatusChangedListener(listener entity.AuctionStatusChangedListener) {
+	ar.statusListeners = append(ar.statusListeners, listener)
+}
+
+// CreateAuction cria um novo leilão e calcula o tempo de expiração
+func (ar *AuctionRepository) CreateAuction(ctx context.Context, auction *entity.Auction) error {
+	ctx, done := tracing.Repository(ctx, "auction", "CreateAuction")
+	defer done()
+
+	if err := ar.insertAuction(ctx, auction); err != nil {
+		return err
 	}
 
-	_, err := ar.Collection.InsertOne(ctx, auctionEntityMongo)
+	// Os listeners são chamados fora do lock para que possam consultar o repositório
+	for _, listener := range ar.createdListeners {
+		listener(ctx, *auction)
+	}
+
+	return nil
+}
+
+func (ar *AuctionRepository) insertAuction(ctx context.Context, auction *entity.Auction) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	auction.ExpiresAt = time.Now().Add(ar.duration).Truncate(time.Millisecond)
+
+	_, err := ar.events.Execute(ctx, auction.Id, func(aggregate *entity.AuctionAggregate) error {
+		return aggregate.Create(*auction)
+	})
 	if err != nil {
-		log.Printf("Error creating auction: %v", err)
+		ar.logger.ErrorContext(ctx, "Error creating auction",
+			logging.AuctionId(auction.Id), logging.UserId(auction.SellerId), logging.Err(err))
 		return err
 	}
 
-	log.Printf("Auction created successfully: %s, expires at: %s", auction.Id, auction.ExpiresAt.Format(time.RFC3339))
+	metrics.AuctionCreated()
+	ar.logger.InfoContext(ctx, "Auction created",
+		logging.AuctionId(auction.Id), logging.UserId(auction.SellerId), slog.Time("expires_at", auction.ExpiresAt))
 	return nil
 }
 
-// calculateAuctionDuration calcula a duração do leilão baseado na variável de ambiente AUCTION_DURATION
-func calculateAuctionDuration() time.Duration {
-	durationStr := os.Getenv("AUCTION_DURATION")
-	if durationStr == "" {
-		// Valor padrão: 5 minutos
-		return 5 * time.Minute
+// startAuctionExpirationChecker inicia uma goroutine que verifica periodicamente leilões
+// expirados. Só a réplica que detém a concessão encerra os leilões; as demais apenas tentam
+// assumi-la a cada intervalo.
+func (ar *AuctionRepository) startAuctionExpirationChecker() {
+	ticker := time.NewTicker(ar.checkInterval)
+	defer ticker.Stop()
+
+	ar.logger.Info("Auction expiration checker started", slog.String("interval", ar.checkInterval.String()))
+
+	for range ticker.C {
+		// Cada execução é um trace próprio, com os encerramentos e listeners como filhos
+		ctx, span := tracing.Start(entity.WithActor(context.Background(), entity.ExpirationCheckerActor), "AuctionExpirationChecker.Run")
+		leader, err := ar.lease.acquire(ctx, time.Now())
+		if err != nil {
+			ar.logger.ErrorContext(ctx, "Error acquiring expiration checker lease", logging.Err(err))
+		} else if leader {
+			err = ar.closeExpiredAuctions(ctx)
+			if err != nil {
+				ar.logger.ErrorContext(ctx, "Error closing expired auctions", logging.Err(err))
+			}
+		}
+		ar.recordCheckerRun(time.Now(), leader, err)
+		tracing.End(span, err)
 	}
+}
+
+func (ar *AuctionRepository) recordCheckerRun(at time.Time, leader bool, err error) {
+	ar.checker.mu.Lock()
+	defer ar.checker.mu.Unlock()
 
-	durationSeconds, err := strconv.Atoi(durationStr)
+	if leader != ar.checker.leader {
+		ar.logger.Info("Expiration checker leadership changed", slog.Bool("leader", leader))
+	}
+	ar.checker.leader = leader
+	ar.checker.lastRunAt = at
 	if err != nil {
-		log.Printf("Invalid AUCTION_DURATION value, using default 5 minutes")
-		return 5 * time.Minute
+		ar.checker.lastError = err.Error()
+		return
 	}
-
-	return time.Duration(durationSeconds) * time.Second
+	ar.checker.lastSuccessAt = at
+	ar.checker.lastError = ""
 }
 
-// getCheckInterval retorna o intervalo de verificação baseado na variável de ambiente
-func getCheckInterval() time.Duration {
-	intervalStr := os.Getenv("AUCTION_CHECK_INTERVAL")
-	if intervalStr == "" {
-		// Valor padrão: 10 segundos
-		return 10 * time.Second
+// ExpirationCheckerStatus retorna as últimas execuções do verificador e o atraso em
+// relação ao leilão ativo expirado há mais tempo
+func (ar *AuctionRepository) ExpirationCheckerStatus(ctx context.Context) (entity.ExpirationCheckerStatus, error) {
+	ctx, done := tracing.Repository(ctx, "auction", "ExpirationCheckerStatus")
+	defer done()
+
+	ar.checker.mu.Lock()
+	status := entity.ExpirationCheckerStatus{
+		Leader:        ar.checker.leader,
+		Interval:      ar.checkInterval,
+		StartedAt:     ar.checker.startedAt,
+		LastRunAt:     ar.checker.lastRunAt,
+		LastSuccessAt: ar.checker.lastSuccessAt,
+		LastError:     ar.checker.lastError,
+	}
+	ar.checker.mu.Unlock()
+
+	now := time.Now()
+	filter := bson.M{
+		"status":     entity.Active,
+		"expires_at": bson.M{"$lte": now.UnixMilli()},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "expires_at", Value: 1}}).SetProjection(bson.M{"expires_at": 1})
+
+	var oldest entity.AuctionEntityMongo
+	err := ar.Collection.FindOne(ctx, filter, opts).Decode(&oldest)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return status, err
+	}
+	if err == nil {
+		status.Lag = now.Sub(time.UnixMilli(oldest.ExpiresAt))
 	}
 
-	intervalSeconds, err := strconv.Atoi(intervalStr)
+	return status, nil
+}
+
+// closeExpiredAuctions busca e fecha todos os leilões que expiraram e retoma os
+// encerramentos cujos listeners falharam
+func (ar *AuctionRepository) closeExpiredAuctions(ctx context.Context) error {
+	closedAuctions, listeners, err := ar.markExpiredAuctionsAsCompleted(ctx)
 	if err != nil {
-		log.Printf("Invalid AUCTION_CHECK_INTERVAL value, using default 10 seconds")
-		return 10 * time.Second
+		return err
+	}
+
+	// Os listeners são chamados fora do lock para que possam consultar o repositório
+	for _, auction := range closedAuctions {
+		before := auction
+		before.Status = entity.Active
+		for _, listener := range ar.statusListeners {
+			listener(ctx, before, auction)
+		}
+
+		ar.handleClosed(ctx, auction, listeners)
 	}
 
-	return time.Duration(intervalSeconds) * time.Second
+	return ar.retryPendingClosings(ctx)
 }
 
-// startAuctionExpirationChecker inicia uma goroutine que verifica periodicamente leilões expirados
-func (ar *AuctionRepository) startAuctionExpirationChecker() {
-	checkInterval := getCheckInterval()
-	ticker := time.NewTicker(checkInterval)
-	defer ticker.Stop()
+// retryPendingClosings chama de novo os listeners dos encerramentos que ainda estão
+// pendentes. Os encerrados há menos de um intervalo ainda podem estar com os listeners
+// rodando (num encerramento manual, por exemplo) e ficam para a próxima execução.
+func (ar *AuctionRepository) retryPendingClosings(ctx context.Context) error {
+	ctx, done := tracing.Repository(ctx, "auction", "RetryPendingClosings")
+	defer done()
+
+	filter := bson.M{
+		"status":             entity.Completed,
+		"closing_pending_at": bson.M{"$lte": time.Now().Add(-ar.checkInterval).UnixMilli()},
+	}
+	pending, err := ar.findAuctionsByFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
 
-	log.Printf("Auction expiration checker started with interval: %v", checkInterval)
+	ar.mu.RLock()
+	listeners := ar.copyClosedListeners()
+	ar.mu.RUnlock()
 
-	for range ticker.C {
-		ctx := context.Background()
-		if err := ar.closeExpiredAuctions(ctx); err != nil {
-			log.Printf("Error closing expired auctions: %v", err)
+	for _, auction := range pending {
+		ar.logger.InfoContext(ctx, "Retrying auction closing listeners", logging.AuctionId(auction.Id))
+		ar.handleClosed(ctx, auction, listeners)
+	}
+	return nil
+}
+
+// handleClosed chama os listeners do encerramento e, se todos terminarem sem erro, remove a
+// marca de encerramento pendente. Com erro a marca fica e o verificador tenta de novo.
+func (ar *AuctionRepository) handleClosed(ctx context.Context, auction entity.Auction, listeners []entity.AuctionClosedListener) {
+	var errs []error
+	for _, listener := range listeners {
+		if err := listener(ctx, auction); err != nil {
+			errs = append(errs, err)
 		}
 	}
+	if err := errors.Join(errs...); err != nil {
+		ar.logger.ErrorContext(ctx, "Error handling closed auction, it will be retried", logging.AuctionId(auction.Id), logging.Err(err))
+		return
+	}
+
+	_, err := ar.Collection.UpdateOne(ctx, bson.M{"_id": auction.Id}, bson.M{"$unset": bson.M{"closing_pending_at": ""}})
+	if err != nil {
+		ar.logger.ErrorContext(ctx, "Error clearing pending auction closing", logging.AuctionId(auction.Id), logging.Err(err))
+	}
 }
 
-// closeExpiredAuctions busca e fecha todos os leilões que expiraram
-func (ar *AuctionRepository) closeExpiredAuctions(ctx context.Context) error {
+// errAuctionExtended indica que o leilão lido como expirado foi prorrogado antes do encerramento
+var errAuctionExtended = errors.New("auction was extended")
+
+// markExpiredAuctionsAsCompleted atualiza o status dos leilões expirados e retorna os que foram fechados
+func (ar *AuctionRepository) markExpiredAuctionsAsCompleted(ctx context.Context) ([]entity.Auction, []entity.AuctionClosedListener, error) {
+	ctx, done := tracing.Repository(ctx, "auction", "CloseExpiredAuctions")
+	defer done()
+
 	ar.mu.Lock()
 	defer ar.mu.Unlock()
 
-	now := time.Now().Unix()
+	now := time.Now().UnixMilli()
 
 	// Busca leilões ativos que já expiraram
 	filter := bson.M{
@@ -124,43 +296,75 @@ func (ar *AuctionRepository) closeExpiredAuctions(ctx context.Context) error {
 
 	cursor, err := ar.Collection.Find(ctx, filter)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	defer cursor.Close(ctx)
 
 	var expiredAuctions []entity.AuctionEntityMongo
 	if err := cursor.All(ctx, &expiredAuctions); err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Fecha cada leilão expirado
+	var closedAuctions []entity.Auction
 	for _, auction := range expiredAuctions {
-		update := bson.M{
-			"$set": bson.M{
-				"status": entity.Completed,
-			},
+		aggregate, err := ar.events.Execute(ctx, auction.Id, func(aggregate *entity.AuctionAggregate) error {
+			// Um lance de última hora pode ter adiado o fim depois da leitura da projeção
+			at := time.Now()
+			if aggregate.Auction.ExpiresAt.After(at) {
+				return errAuctionExtended
+			}
+			return aggregate.Close(at)
+		})
+		if errors.Is(err, entity.ErrAuctionNotActive) || errors.Is(err, errAuctionExtended) {
+			// Já encerrado ou prorrogado no stream, mas a projeção ficou para trás (a gravação
+			// dela falhou depois do evento). Projetar de novo marca um encerramento pendente,
+			// que é retomado com os demais.
+			if err := ar.events.Reproject(ctx, auction.Id); err != nil {
+				ar.logger.ErrorContext(ctx, "Error reprojecting auction", logging.AuctionId(auction.Id), logging.Err(err))
+			}
+			continue
 		}
-
-		_, err := ar.Collection.UpdateOne(ctx, bson.M{"_id": auction.Id}, update)
 		if err != nil {
-			log.Printf("Error updating auction %s status: %v", auction.Id, err)
+			ar.logger.ErrorContext(ctx, "Error closing expired auction", logging.AuctionId(auction.Id), logging.Err(err))
 			continue
 		}
 
-		log.Printf("Auction %s closed automatically (expired at: %s)", 
-			auction.Id, 
-			time.Unix(auction.ExpiresAt, 0).Format(time.RFC3339))
+		metrics.AuctionClosed(time.UnixMilli(auction.ExpiresAt), time.Now())
+
+		ar.logger.InfoContext(ctx, "Auction closed automatically",
+			logging.AuctionId(auction.Id), slog.Time("expires_at", time.UnixMilli(auction.ExpiresAt)))
+
+		closedAuctions = append(closedAuctions, aggregate.Auction)
 	}
 
 	if len(expiredAuctions) > 0 {
-		log.Printf("Closed %d expired auction(s)", len(expiredAuctions))
+		ar.logger.InfoContext(ctx, "Expired auctions closed", slog.Int("count", len(closedAuctions)))
 	}
 
-	return nil
+	return closedAuctions, ar.copyClosedListeners(), nil
+}
+
+// copyClosedListeners copia os listeners de encerramento; deve ser chamado com ar.mu travado
+func (ar *AuctionRepository) copyClosedListeners() []entity.AuctionClosedListener {
+	listeners := make([]entity.AuctionClosedListener, len(ar.listeners))
+	copy(listeners, ar.listeners)
+	return listeners
+}
+
+// CountActiveAuctions conta os leilões ativos, para o gauge de métricas
+func (ar *AuctionRepository) CountActiveAuctions(ctx context.Context) (int64, error) {
+	ctx, done := tracing.Repository(ctx, "auction", "CountActiveAuctions")
+	defer done()
+
+	return ar.Collection.CountDocuments(ctx, bson.M{"status": entity.Active})
 }
 
 // FindAuctionById busca um leilão pelo ID
 func (ar *AuctionRepository) FindAuctionById(ctx context.Context, id string) (*entity.Auction, error) {
+	ctx, done := tracing.Repository(ctx, "auction", "FindAuctionById")
+	defer done()
+
 	ar.mu.RLock()
 	defer ar.mu.RUnlock()
 
@@ -176,22 +380,49 @@ func (ar *AuctionRepository) FindAuctionById(ctx context.Context, id string) (*e
 	}
 
 	return &entity.Auction{
-		Id:          auctionEntityMongo.Id,
-		ProductName: auctionEntityMongo.ProductName,
-		Category:    auctionEntityMongo.Category,
-		Description: auctionEntityMongo.Description,
-		Condition:   auctionEntityMongo.Condition,
-		Status:      auctionEntityMongo.Status,
-		Timestamp:   time.Unix(auctionEntityMongo.Timestamp, 0),
-		ExpiresAt:   time.Unix(auctionEntityMongo.ExpiresAt, 0),
+		Id:           auctionEntityMongo.Id,
+		ProductName:  auctionEntityMongo.ProductName,
+		Category:     auctionEntityMongo.Category,
+		Description:  auctionEntityMongo.Description,
+		Condition:    auctionEntityMongo.Condition,
+		Status:       auctionEntityMongo.Status,
+		Currency:     auctionEntityMongo.Currency.OrDefault(),
+		CurrentPrice: entity.NewMoney(auctionEntityMongo.CurrentPrice, auctionEntityMongo.Currency.OrDefault()),
+		ReservePrice: entity.NewMoney(auctionEntityMongo.ReservePrice, auctionEntityMongo.Currency.OrDefault()),
+		SellerId:     auctionEntityMongo.SellerId,
+		WinningBidId: auctionEntityMongo.WinningBidId,
+		Timestamp:    time.UnixMilli(auctionEntityMongo.Timestamp),
+		ExpiresAt:    time.UnixMilli(auctionEntityMongo.ExpiresAt),
 	}, nil
 }
 
-// FindAuctions busca leilões com filtros opcionais
-func (ar *AuctionRepository) FindAuctions(ctx context.Context, status entity.AuctionStatus, category, productName string) ([]entity.Auction, error) {
-	ar.mu.RLock()
-	defer ar.mu.RUnlock()
+// FindAuctions busca uma página de leilões com filtros opcionais e retorna o cursor da próxima página
+func (ar *AuctionRepository) FindAuctions(ctx context.Context, status entity.AuctionStatus, category, productName string, page entity.PageRequest) ([]entity.Auction, string, error) {
+	ctx, done := tracing.Repository(ctx, "auction", "FindAuctions")
+	defer done()
 
+	page = page.WithDefaults("expires_at", entity.Ascending)
+	filter, opts, err := keyset.Query(auctionListFilter(status, category, productName), page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	auctions, err := ar.findAuctionsByFilter(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !keyset.HasMore(page, len(auctions)) {
+		return auctions, "", nil
+	}
+
+	auctions = auctions[:page.Limit]
+	last := auctions[len(auctions)-1]
+	return auctions, keyset.NextCursor(page, auctionSortValue(last, page.SortBy), last.Id), nil
+}
+
+// auctionListFilter monta o filtro de FindAuctions; status negativo significa todos os status
+func auctionListFilter(status entity.AuctionStatus, category, productName string) bson.M {
 	filter := bson.M{}
 
 	if status >= 0 {
@@ -203,7 +434,189 @@ func (ar *AuctionRepository) FindAuctions(ctx context.Context, status entity.Auc
 	}
 
 	if productName != "" {
-		filter["product_name"] = bson.M{"$regex": productName, "$options": "i"}
+		// O texto é escapado para que o usuário não consiga injetar expressões regulares
+		filter["product_name"] = bson.M{"$regex": regexp.QuoteMeta(productName), "$options": "i"}
+	}
+
+	return filter
+}
+
+// SearchAuctions busca leilões por texto e filtros combinados. A ordenação por relevância
+// usa cursor de deslocamento; as demais usam keyset como FindAuctions.
+func (ar *AuctionRepository) SearchAuctions(ctx context.Context, search entity.AuctionSearchFilter, page entity.PageRequest) ([]entity.Auction, string, error) {
+	ctx, done := tracing.Repository(ctx, "auction", "SearchAuctions")
+	defer done()
+
+	filter := bson.M{}
+
+	if search.Text != "" {
+		filter["$text"] = bson.M{"$search": search.Text}
+	}
+
+	if search.Status != nil {
+		filter["status"] = *search.Status
+	}
+
+	if len(search.Categories) > 0 {
+		filter["category"] = bson.M{"$in": search.Categories}
+	}
+
+	if len(search.Conditions) > 0 {
+		filter["condition"] = bson.M{"$in": search.Conditions}
+	}
+
+	if search.SellerId != "" {
+		filter["seller_id"] = search.SellerId
+	}
+
+	if search.Currency != nil {
+		filter["currency"] = *search.Currency
+	}
+
+	price := bson.M{}
+	if search.MinPrice != nil {
+		price["$gte"] = search.MinPrice.Cents
+	}
+	if search.MaxPrice != nil {
+		price["$lte"] = search.MaxPrice.Cents
+	}
+	if len(price) > 0 {
+		filter["current_price"] = price
+	}
+
+	expiresAt := bson.M{}
+	if search.EndingAfter != nil {
+		expiresAt["$gte"] = search.EndingAfter.UnixMilli()
+	}
+	if search.EndingBefore != nil {
+		expiresAt["$lte"] = search.EndingBefore.UnixMilli()
+	}
+	if len(expiresAt) > 0 {
+		filter["expires_at"] = expiresAt
+	}
+
+	if page.SortBy == entity.SortByRelevance {
+		return ar.searchAuctionsByRelevance(ctx, filter, page)
+	}
+
+	filter, opts, err := keyset.Query(filter, page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	auctions, err := ar.findAuctionsByFilter(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !keyset.HasMore(page, len(auctions)) {
+		return auctions, "", nil
+	}
+
+	auctions = auctions[:page.Limit]
+	last := auctions[len(auctions)-1]
+	return auctions, keyset.NextCursor(page, auctionSortValue(last, page.SortBy), last.Id), nil
+}
+
+// searchAuctionsByRelevance ordena pela pontuação do índice de texto
+func (ar *AuctionRepository) searchAuctionsByRelevance(ctx context.Context, filter bson.M, page entity.PageRequest) ([]entity.Auction, string, error) {
+	offset, err := keyset.Offset(page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}}).
+		SetSkip(offset).
+		SetLimit(int64(page.Limit) + 1)
+
+	auctions, err := ar.findAuctionsByFilter(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !keyset.HasMore(page, len(auctions)) {
+		return auctions, "", nil
+	}
+
+	return auctions[:page.Limit], keyset.NextOffsetCursor(page, offset+int64(page.Limit)), nil
+}
+
+// auctionSortValue retorna o valor, como gravado no MongoDB, do campo usado na ordenação
+func auctionSortValue(auction entity.Auction, sortBy string) float64 {
+	switch sortBy {
+	case "timestamp":
+		return float64(auction.Timestamp.UnixMilli())
+	case "current_price":
+		return float64(auction.CurrentPrice.Cents)
+	default:
+		return float64(auction.ExpiresAt.UnixMilli())
+	}
+}
+
+// UpdateAuctionStatus encerra (Completed) ou cancela (Cancelled) o leilão; um leilão
+// encerrado não volta a ficar ativo. O encerramento avisa os mesmos listeners do fechamento
+// automático; o cancelamento só é avisado aos listeners de mudança de status.
+func (ar *AuctionRepository) UpdateAuctionStatus(ctx context.Context, id string, status entity.AuctionStatus) error {
+	ctx, done := tracing.Repository(ctx, "auction", "UpdateAuctionStatus")
+	defer done()
+
+	// Cada status corresponde a um comando do agregado; o estado anterior vem do stream
+	var before entity.Auction
+	aggregate, err := ar.events.Execute(ctx, id, func(aggregate *entity.AuctionAggregate) error {
+		before = aggregate.Auction
+		if !aggregate.Exists() || aggregate.Auction.Status == status {
+			return nil
+		}
+
+		switch status {
+		case entity.Completed:
+			return aggregate.Close(time.Now())
+		case entity.Cancelled:
+			return aggregate.Cancel("")
+		}
+		return fmt.Errorf("auction %s cannot change from status %d to %d", id, aggregate.Auction.Status, status)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Leilão inexistente ou já no status pedido
+	if len(aggregate.Changes()) == 0 {
+		return nil
+	}
+
+	metrics.AuctionStatusChanged(aggregate.Auction.Status)
+
+	for _, listener := range ar.statusListeners {
+		listener(ctx, before, aggregate.Auction)
+	}
+
+	if aggregate.Auction.Status == entity.Completed {
+		ar.mu.RLock()
+		listeners := ar.copyClosedListeners()
+		ar.mu.RUnlock()
+
+		// O leilão já foi encerrado: uma falha dos listeners é retomada pelo verificador
+		ar.handleClosed(ctx, aggregate.Auction, listeners)
+	}
+
+	return nil
+}
+
+// FindExpiredAuctions busca leilões que expiraram
+func (ar *AuctionRepository) FindExpiredAuctions(ctx context.Context) ([]entity.Auction, error) {
+	ctx, done := tracing.Repository(ctx, "auction", "FindExpiredAuctions")
+	defer done()
+
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	now := time.Now().UnixMilli()
+
+	filter := bson.M{
+		"status":     entity.Active,
+		"expires_at": bson.M{"$lte": now},
 	}
 
 	cursor, err := ar.Collection.Find(ctx, filter)
@@ -220,49 +633,56 @@ func (ar *AuctionRepository) FindAuctions(ctx context.Context, status entity.Auc
 	var auctions []entity.Auction
 	for _, auctionMongo := range auctionEntitiesMongo {
 		auctions = append(auctions, entity.Auction{
-			Id:          auctionMongo.Id,
-			ProductName: auctionMongo.ProductName,
-			Category:    auctionMongo.Category,
-			Description: auctionMongo.Description,
-			Condition:   auctionMongo.Condition,
-			Status:      auctionMongo.Status,
-			Timestamp:   time.Unix(auctionMongo.Timestamp, 0),
-			ExpiresAt:   time.Unix(auctionMongo.ExpiresAt, 0),
+			Id:           auctionMongo.Id,
+			ProductName:  auctionMongo.ProductName,
+			Category:     auctionMongo.Category,
+			Description:  auctionMongo.Description,
+			Condition:    auctionMongo.Condition,
+			Status:       auctionMongo.Status,
+			Currency:     auctionMongo.Currency.OrDefault(),
+			CurrentPrice: entity.NewMoney(auctionMongo.CurrentPrice, auctionMongo.Currency.OrDefault()),
+			ReservePrice: entity.NewMoney(auctionMongo.ReservePrice, auctionMongo.Currency.OrDefault()),
+			SellerId:     auctionMongo.SellerId,
+			WinningBidId: auctionMongo.WinningBidId,
+			Timestamp:    time.UnixMilli(auctionMongo.Timestamp),
+			ExpiresAt:    time.UnixMilli(auctionMongo.ExpiresAt),
 		})
 	}
 
 	return auctions, nil
 }
 
-// UpdateAuctionStatus atualiza o status de um leilão
-func (ar *AuctionRepository) UpdateAuctionStatus(ctx context.Context, id string, status entity.AuctionStatus) error {
-	ar.mu.Lock()
-	defer ar.mu.Unlock()
+// FindAuctionsByIds busca em uma única consulta os leilões com os IDs informados
+func (ar *AuctionRepository) FindAuctionsByIds(ctx context.Context, ids []string) ([]entity.Auction, error) {
+	ctx, done := tracing.Repository(ctx, "auction", "FindAuctionsByIds")
+	defer done()
 
-	filter := bson.M{"_id": id}
-	update := bson.M{
-		"$set": bson.M{
-			"status": status,
-		},
+	if len(ids) == 0 {
+		return nil, nil
 	}
 
-	_, err := ar.Collection.UpdateOne(ctx, filter, update)
-	return err
+	return ar.findAuctionsByFilter(ctx, bson.M{"_id": bson.M{"$in": ids}})
 }
 
-// FindExpiredAuctions busca leilões que expiraram
-func (ar *AuctionRepository) FindExpiredAuctions(ctx context.Context) ([]entity.Auction, error) {
-	ar.mu.RLock()
-	defer ar.mu.RUnlock()
-
-	now := time.Now().Unix()
+// FindAuctionsEndingBetween busca leilões ativos que expiram dentro do intervalo informado
+func (ar *AuctionRepository) FindAuctionsEndingBetween(ctx context.Context, from, to time.Time) ([]entity.Auction, error) {
+	ctx, done := tracing.Repository(ctx, "auction", "FindAuctionsEndingBetween")
+	defer done()
 
 	filter := bson.M{
 		"status":     entity.Active,
-		"expires_at": bson.M{"$lte": now},
+		"expires_at": bson.M{"$gt": from.UnixMilli(), "$lte": to.UnixMilli()},
 	}
 
-	cursor, err := ar.Collection.Find(ctx, filter)
+	return ar.findAuctionsByFilter(ctx, filter)
+}
+
+// findAuctionsByFilter executa a consulta e converte os documentos em entidades
+func (ar *AuctionRepository) findAuctionsByFilter(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]entity.Auction, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	cursor, err := ar.Collection.Find(ctx, filter, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -276,14 +696,19 @@ func (ar *AuctionRepository) FindExpiredAuctions(ctx context.Context) ([]entity.
 	var auctions []entity.Auction
 	for _, auctionMongo := range auctionEntitiesMongo {
 		auctions = append(auctions, entity.Auction{
-			Id:          auctionMongo.Id,
-			ProductName: auctionMongo.ProductName,
-			Category:    auctionMongo.Category,
-			Description: auctionMongo.Description,
-			Condition:   auctionMongo.Condition,
-			Status:      auctionMongo.Status,
-			Timestamp:   time.Unix(auctionMongo.Timestamp, 0),
-			ExpiresAt:   time.Unix(auctionMongo.ExpiresAt, 0),
+			Id:           auctionMongo.Id,
+			ProductName:  auctionMongo.ProductName,
+			Category:     auctionMongo.Category,
+			Description:  auctionMongo.Description,
+			Condition:    auctionMongo.Condition,
+			Status:       auctionMongo.Status,
+			Currency:     auctionMongo.Currency.OrDefault(),
+			CurrentPrice: entity.NewMoney(auctionMongo.CurrentPrice, auctionMongo.Currency.OrDefault()),
+			ReservePrice: entity.NewMoney(auctionMongo.ReservePrice, auctionMongo.Currency.OrDefault()),
+			SellerId:     auctionMongo.SellerId,
+			WinningBidId: auctionMongo.WinningBidId,
+			Timestamp:    time.UnixMilli(auctionMongo.Timestamp),
+			ExpiresAt:    time.UnixMilli(auctionMongo.ExpiresAt),
 		})
 	}
 
@@ -2,11 +2,13 @@ package auction
 
 import (
 	"context"
-	"os"
+	"errors"
+	"log/slog"
 	"testing"
 	"time"
 
 	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/database/event_store"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -24,11 +26,13 @@ func setupTestDB(t *testing.T) (*mongo.Database, func()) {
 
 	database := client.Database("auctions_test")
 
-	// Limpa a coleção antes dos testes
+	// Limpa as coleções antes dos testes
 	database.Collection("auctions").Drop(ctx)
+	database.Collection("auction_events").Drop(ctx)
 
 	cleanup := func() {
 		database.Collection("auctions").Drop(ctx)
+		database.Collection("auction_events").Drop(ctx)
 		client.Disconnect(ctx)
 	}
 
@@ -39,11 +43,7 @@ func TestCreateAuction(t *testing.T) {
 	database, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	// Define duração de teste
-	os.Setenv("AUCTION_DURATION", "5")
-	defer os.Unsetenv("AUCTION_DURATION")
-
-	repo := NewAuctionRepository(database)
+	repo := NewAuctionRepository(database, event_store.NewAuctionEventStore(database, slog.Default()), 5*time.Second, 10*time.Second, slog.Default())
 	ctx := context.Background()
 
 	auction, err := entity.CreateAuction(
@@ -66,10 +66,7 @@ func TestFindAuctionById(t *testing.T) {
 	database, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	os.Setenv("AUCTION_DURATION", "300")
-	defer os.Unsetenv("AUCTION_DURATION")
-
-	repo := NewAuctionRepository(database)
+	repo := NewAuctionRepository(database, event_store.NewAuctionEventStore(database, slog.Default()), 5*time.Minute, 10*time.Second, slog.Default())
 	ctx := context.Background()
 
 	// Cria um leilão
@@ -94,14 +91,8 @@ func TestAuctionAutomaticClosure(t *testing.T) {
 	database, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	// Define duração curta para teste (3 segundos)
-	os.Setenv("AUCTION_DURATION", "3")
-	// Define intervalo de verificação curto (1 segundo)
-	os.Setenv("AUCTION_CHECK_INTERVAL", "1")
-	defer os.Unsetenv("AUCTION_DURATION")
-	defer os.Unsetenv("AUCTION_CHECK_INTERVAL")
-
-	repo := NewAuctionRepository(database)
+	// Duração curta para teste (3 segundos) e verificação a cada segundo
+	repo := NewAuctionRepository(database, event_store.NewAuctionEventStore(database, slog.Default()), 3*time.Second, time.Second, slog.Default())
 	ctx := context.Background()
 
 	// Cria um leilão
@@ -143,10 +134,7 @@ func TestFindExpiredAuctions(t *testing.T) {
 	database, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	os.Setenv("AUCTION_DURATION", "1")
-	defer os.Unsetenv("AUCTION_DURATION")
-
-	repo := NewAuctionRepository(database)
+	repo := NewAuctionRepository(database, event_store.NewAuctionEventStore(database, slog.Default()), time.Second, 10*time.Second, slog.Default())
 	ctx := context.Background()
 
 	// Cria um leilão que expirará rapidamente
@@ -172,10 +160,7 @@ func TestUpdateAuctionStatus(t *testing.T) {
 	database, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	os.Setenv("AUCTION_DURATION", "300")
-	defer os.Unsetenv("AUCTION_DURATION")
-
-	repo := NewAuctionRepository(database)
+	repo := NewAuctionRepository(database, event_store.NewAuctionEventStore(database, slog.Default()), 5*time.Minute, 10*time.Second, slog.Default())
 	ctx := context.Background()
 
 	// Cria um leilão
@@ -198,14 +183,40 @@ func TestUpdateAuctionStatus(t *testing.T) {
 	assert.Equal(t, entity.Completed, updatedAuction.Status)
 }
 
-func TestConcurrentAuctionCreation(t *testing.T) {
+func TestUpdateAuctionStatusNotifiesClosedListeners(t *testing.T) {
 	database, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	os.Setenv("AUCTION_DURATION", "300")
-	defer os.Unsetenv("AUCTION_DURATION")
+	repo := NewAuctionRepository(database, event_store.NewAuctionEventStore(database, slog.Default()), 5*time.Minute, 10*time.Second, slog.Default())
+	ctx := context.Background()
+
+	var closed []entity.Auction
+	repo.AddClosedListener(func(ctx context.Context, auction entity.Auction) error {
+		closed = append(closed, auction)
+		return nil
+	})
+
+	completed, _ := entity.CreateAuction("Test Product", "Test", "Closed manually by an operator", entity.New, 0)
+	cancelled, _ := entity.CreateAuction("Test Product", "Test", "Cancelled manually by an operator", entity.New, 0)
+	assert.NoError(t, repo.CreateAuction(ctx, completed))
+	assert.NoError(t, repo.CreateAuction(ctx, cancelled))
+
+	// O encerramento manual avisa os mesmos listeners do fechamento automático, uma única vez
+	assert.NoError(t, repo.UpdateAuctionStatus(ctx, completed.Id, entity.Completed))
+	assert.NoError(t, repo.UpdateAuctionStatus(ctx, completed.Id, entity.Completed))
+	assert.NoError(t, repo.UpdateAuctionStatus(ctx, cancelled.Id, entity.Cancelled))
+
+	if assert.Len(t, closed, 1) {
+		assert.Equal(t, completed.Id, closed[0].Id)
+		assert.Equal(t, entity.Completed, closed[0].Status)
+	}
+}
+
+func TestConcurrentAuctionCreation(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
 
-	repo := NewAuctionRepository(database)
+	repo := NewAuctionRepository(database, event_store.NewAuctionEventStore(database, slog.Default()), 5*time.Minute, 10*time.Second, slog.Default())
 	ctx := context.Background()
 
 	// Cria múltiplos leilões concorrentemente
@@ -233,67 +244,34 @@ func TestConcurrentAuctionCreation(t *testing.T) {
 	}
 
 	// Verifica se todos os leilões foram criados
-	auctions, err := repo.FindAuctions(ctx, entity.Active, "", "")
+	auctions, _, err := repo.FindAuctions(ctx, entity.Active, "", "", entity.PageRequest{Limit: numAuctions})
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(auctions), numAuctions)
 }
 
-func TestCalculateAuctionDuration(t *testing.T) {
-	tests := []struct {
-		name     string
-		envValue string
-		expected time.Duration
-	}{
-		{
-			name:     "Valid duration",
-			envValue: "300",
-			expected: 300 * time.Second,
-		},
-		{
-			name:     "Empty value - default",
-			envValue: "",
-			expected: 5 * time.Minute,
-		},
-		{
-			name:     "Invalid value - default",
-			envValue: "invalid",
-			expected: 5 * time.Minute,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if tt.envValue != "" {
-				os.Setenv("AUCTION_DURATION", tt.envValue)
-				defer os.Unsetenv("AUCTION_DURATION")
-			}
-
-			duration := calculateAuctionDuration()
-			assert.Equal(t, tt.expected, duration)
-		})
-	}
-}
-
 func TestCloseExpiredAuctionsDirectly(t *testing.T) {
 	database, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewAuctionRepository(database)
+	events := event_store.NewAuctionEventStore(database, slog.Default())
+	repo := NewAuctionRepository(database, events, 5*time.Minute, 10*time.Second, slog.Default())
 	ctx := context.Background()
 
-	// Cria um leilão já expirado manualmente no banco
-	expiredAuction := &entity.AuctionEntityMongo{
+	// Cria um leilão já expirado diretamente no event store
+	expiredAuction := entity.Auction{
 		Id:          "expired-auction-id",
 		ProductName: "Expired Product",
 		Category:    "Test",
 		Description: "This auction is already expired",
 		Condition:   entity.New,
-		Status:      entity.Active,
-		Timestamp:   time.Now().Add(-10 * time.Minute).Unix(),
-		ExpiresAt:   time.Now().Add(-5 * time.Minute).Unix(), // Expirado há 5 minutos
+		Currency:    entity.DefaultCurrency,
+		Timestamp:   time.Now().Add(-10 * time.Minute),
+		ExpiresAt:   time.Now().Add(-5 * time.Minute), // Expirado há 5 minutos
 	}
 
-	_, err := database.Collection("auctions").InsertOne(ctx, expiredAuction)
+	_, err := events.Execute(ctx, expiredAuction.Id, func(aggregate *entity.AuctionAggregate) error {
+		return aggregate.Create(expiredAuction)
+	})
 	assert.NoError(t, err)
 
 	// Chama diretamente o método de fechamento
@@ -306,3 +284,68 @@ func TestCloseExpiredAuctionsDirectly(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, entity.Completed, result.Status)
 }
+
+func TestFailedClosedListenersAreRetried(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAuctionRepository(database, event_store.NewAuctionEventStore(database, slog.Default()), 5*time.Minute, 10*time.Second, slog.Default())
+	ctx := context.Background()
+
+	calls := 0
+	repo.AddClosedListener(func(ctx context.Context, auction entity.Auction) error {
+		calls++
+		if calls == 1 {
+			return errors.New("settlement unavailable")
+		}
+		return nil
+	})
+
+	auction, _ := entity.CreateAuction("Test Product", "Test", "Closed while settlement was down", entity.New, 0)
+	assert.NoError(t, repo.CreateAuction(ctx, auction))
+
+	// O encerramento é gravado mesmo com o listener falhando, e fica pendente
+	assert.NoError(t, repo.UpdateAuctionStatus(ctx, auction.Id, entity.Completed))
+
+	var result entity.AuctionEntityMongo
+	assert.NoError(t, database.Collection("auctions").FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&result))
+	assert.NotZero(t, result.ClosingPendingAt)
+
+	// Simula o encerramento feito há mais de um intervalo
+	_, err := database.Collection("auctions").UpdateOne(ctx, bson.M{"_id": auction.Id},
+		bson.M{"$set": bson.M{"closing_pending_at": time.Now().Add(-time.Minute).UnixMilli()}})
+	assert.NoError(t, err)
+
+	// A próxima verificação chama os listeners de novo e limpa a marca
+	assert.NoError(t, repo.retryPendingClosings(ctx))
+	assert.NoError(t, repo.retryPendingClosings(ctx))
+	assert.Equal(t, 2, calls)
+
+	result = entity.AuctionEntityMongo{}
+	assert.NoError(t, database.Collection("auctions").FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&result))
+	assert.Zero(t, result.ClosingPendingAt)
+}
+
+func TestAuctionListFilterEscapesProductName(t *testing.T) {
+	tests := []struct {
+		name        string
+		productName string
+		pattern     string
+	}{
+		{"plain text", "iphone", "iphone"},
+		{"wildcards", "iphone.*", `iphone\.\*`},
+		{"alternation and groups", "(a|b)+", `\(a\|b\)\+`},
+		{"anchors and classes", "^[a-z]$", `\^\[a-z\]\$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := auctionListFilter(entity.Active, "", tt.productName)
+			assert.Equal(t, bson.M{"$regex": tt.pattern, "$options": "i"}, filter["product_name"])
+			assert.Equal(t, entity.Active, filter["status"])
+		})
+	}
+
+	// Sem nome, categoria ou status (negativo), não há filtro
+	assert.Empty(t, auctionListFilter(-1, "", ""))
+}
@@ -0,0 +1,233 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxAppendAttempts limita as tentativas quando outra réplica grava no mesmo stream
+const maxAppendAttempts = 10
+
+// AuditRepository só insere e lê registros de audit_log; não há atualização nem remoção.
+// Em produção o usuário da aplicação deve ter apenas insert e find em audit_log. As âncoras
+// ficam em audit_heads e os registros à espera de encadeamento em audit_pending.
+type AuditRepository struct {
+	Collection *mongo.Collection
+	Heads      *mongo.Collection
+	Pending    *mongo.Collection
+}
+
+func NewAuditRepository(database *mongo.Database) *AuditRepository {
+	return &AuditRepository{
+		Collection: database.Collection("audit_log"),
+		Heads:      database.Collection("audit_heads"),
+		Pending:    database.Collection("audit_pending"),
+	}
+}
+
+// Append encadeia o registro ao último do stream. O índice único em (stream, sequence)
+// garante a ordem quando duas gravações concorrem: a que perder lê o novo último
+// registro e tenta de novo. Se o registro já foi encadeado (numa tentativa anterior), ele
+// é mantido e só a âncora é avançada.
+func (ar *AuditRepository) Append(ctx context.Context, entry entity.AuditEntry) (*entity.AuditEntry, error) {
+	ctx, done := tracing.Repository(ctx, "audit", "Append")
+	defer done()
+
+	for attempt := 0; attempt < maxAppendAttempts; attempt++ {
+		previous, err := ar.findLastEntry(ctx, entry.Stream)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Chain(previous)
+
+		_, err = ar.Collection.InsertOne(ctx, toAuditEntryMongo(entry))
+		if err == nil {
+			return &entry, ar.advanceHead(ctx, entry)
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+
+		existing, err := ar.findEntry(ctx, entry.Id)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, ar.advanceHead(ctx, *existing)
+		}
+	}
+
+	return nil, errors.New("could not append audit entry: too many concurrent writes to the stream")
+}
+
+// advanceHead move a âncora do stream para o registro, a menos que ela já esteja adiante
+func (ar *AuditRepository) advanceHead(ctx context.Context, entry entity.AuditEntry) error {
+	filter := bson.M{"_id": entry.Stream, "sequence": bson.M{"$lt": entry.Sequence}}
+	update := bson.M{"$set": bson.M{"sequence": entry.Sequence, "hash": entry.Hash, "updated_at": time.Now().UnixMilli()}}
+
+	// Com a âncora adiante, o filtro não encontra o documento e o upsert esbarra no _id
+	_, err := ar.Heads.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func (ar *AuditRepository) FindAuditHead(ctx context.Context, stream string) (*entity.AuditHead, error) {
+	ctx, done := tracing.Repository(ctx, "audit", "FindAuditHead")
+	defer done()
+
+	var headMongo entity.AuditHeadEntityMongo
+	err := ar.Heads.FindOne(ctx, bson.M{"_id": stream}).Decode(&headMongo)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entity.AuditHead{Stream: headMongo.Stream, Sequence: headMongo.Sequence, Hash: headMongo.Hash}, nil
+}
+
+// EnqueuePending guarda o registro sem encadeamento; repetir com o mesmo id não o duplica
+func (ar *AuditRepository) EnqueuePending(ctx context.Context, entry entity.AuditEntry) error {
+	ctx, done := tracing.Repository(ctx, "audit", "EnqueuePending")
+	defer done()
+
+	entry.Sequence, entry.PreviousHash, entry.Hash = 0, "", ""
+	_, err := ar.Pending.InsertOne(ctx, toAuditEntryMongo(entry))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func (ar *AuditRepository) FindPendingEntries(ctx context.Context) ([]entity.AuditEntry, error) {
+	ctx, done := tracing.Repository(ctx, "audit", "FindPendingEntries")
+	defer done()
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := ar.Pending.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entriesMongo []entity.AuditEntryEntityMongo
+	if err := cursor.All(ctx, &entriesMongo); err != nil {
+		return nil, err
+	}
+
+	entries := make([]entity.AuditEntry, 0, len(entriesMongo))
+	for _, entryMongo := range entriesMongo {
+		entries = append(entries, toAuditEntry(entryMongo))
+	}
+	return entries, nil
+}
+
+func (ar *AuditRepository) RemovePending(ctx context.Context, id string) error {
+	ctx, done := tracing.Repository(ctx, "audit", "RemovePending")
+	defer done()
+
+	_, err := ar.Pending.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// FindAuditTrail retorna todos os registros do stream em ordem de sequência
+func (ar *AuditRepository) FindAuditTrail(ctx context.Context, stream string) ([]entity.AuditEntry, error) {
+	ctx, done := tracing.Repository(ctx, "audit", "FindAuditTrail")
+	defer done()
+
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}})
+	cursor, err := ar.Collection.Find(ctx, bson.M{"stream": stream}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entriesMongo []entity.AuditEntryEntityMongo
+	if err := cursor.All(ctx, &entriesMongo); err != nil {
+		return nil, err
+	}
+
+	entries := make([]entity.AuditEntry, 0, len(entriesMongo))
+	for _, entryMongo := range entriesMongo {
+		entries = append(entries, toAuditEntry(entryMongo))
+	}
+
+	return entries, nil
+}
+
+func (ar *AuditRepository) findEntry(ctx context.Context, id string) (*entity.AuditEntry, error) {
+	var entryMongo entity.AuditEntryEntityMongo
+	err := ar.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&entryMongo)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry := toAuditEntry(entryMongo)
+	return &entry, nil
+}
+
+func (ar *AuditRepository) findLastEntry(ctx context.Context, stream string) (*entity.AuditEntry, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}})
+
+	var entryMongo entity.AuditEntryEntityMongo
+	err := ar.Collection.FindOne(ctx, bson.M{"stream": stream}, opts).Decode(&entryMongo)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry := toAuditEntry(entryMongo)
+	return &entry, nil
+}
+
+func toAuditEntryMongo(entry entity.AuditEntry) entity.AuditEntryEntityMongo {
+	return entity.AuditEntryEntityMongo{
+		Id:           entry.Id,
+		Stream:       entry.Stream,
+		Sequence:     entry.Sequence,
+		Action:       entry.Action,
+		Actor:        entry.Actor,
+		Timestamp:    entry.Timestamp.UnixMilli(),
+		Before:       string(entry.Before),
+		After:        string(entry.After),
+		Reason:       entry.Reason,
+		PreviousHash: entry.PreviousHash,
+		Hash:         entry.Hash,
+	}
+}
+
+func toAuditEntry(entryMongo entity.AuditEntryEntityMongo) entity.AuditEntry {
+	entry := entity.AuditEntry{
+		Id:           entryMongo.Id,
+		Stream:       entryMongo.Stream,
+		Sequence:     entryMongo.Sequence,
+		Action:       entryMongo.Action,
+		Actor:        entryMongo.Actor,
+		Timestamp:    time.UnixMilli(entryMongo.Timestamp),
+		Reason:       entryMongo.Reason,
+		PreviousHash: entryMongo.PreviousHash,
+		Hash:         entryMongo.Hash,
+	}
+	if entryMongo.Before != "" {
+		entry.Before = []byte(entryMongo.Before)
+	}
+	if entryMongo.After != "" {
+		entry.After = []byte(entryMongo.After)
+	}
+	return entry
+}
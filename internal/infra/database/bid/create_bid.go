@@ -3,79 +3,123 @@ package bid
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/database/event_store"
+	"github.com/auction-goexpert/internal/infra/database/keyset"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/auction-goexpert/internal/infra/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// BidRepository lê a projeção bids; os lances são comandos no agregado do leilão,
+// gravados como eventos no event store. O valor de cada lance é reservado na carteira do
+// usuário antes de o lance ser gravado. Um lance perto do fim adia o encerramento do
+// leilão por extensionWindow (zero desliga a prorrogação).
 type BidRepository struct {
-	Collection        *mongo.Collection
-	AuctionRepository entity.AuctionRepositoryInterface
+	Collection      *mongo.Collection
+	events          *event_store.AuctionEventStore
+	wallet          entity.WalletRepositoryInterface
+	extensionWindow time.Duration
+	listeners       []entity.BidPlacedListener
+	logger          *slog.Logger
 }
 
-func NewBidRepository(database *mongo.Database, auctionRepo entity.AuctionRepositoryInterface) *BidRepository {
+func NewBidRepository(database *mongo.Database, events *event_store.AuctionEventStore, wallet entity.WalletRepositoryInterface, extensionWindow time.Duration, logger *slog.Logger) *BidRepository {
 	return &BidRepository{
-		Collection:        database.Collection("bids"),
-		AuctionRepository: auctionRepo,
+		Collection:      database.Collection("bids"),
+		events:          events,
+		wallet:          wallet,
+		extensionWindow: extensionWindow,
+		logger:          logger,
 	}
 }
 
-// CreateBid cria um novo lance, validando se o leilão está ativo
+// AddPlacedListener registra uma função chamada sempre que um lance se torna o maior do leilão.
+// Deve ser chamado durante a inicialização, antes de o repositório receber lances.
+func (br *BidRepository) AddPlacedListener(listener entity.BidPlacedListener) {
+	br.listeners = append(br.listeners, listener)
+}
+
+// CreateBid grava o lance no stream do leilão se ele for aceito pelo agregado
 func (br *BidRepository) CreateBid(ctx context.Context, bid *entity.Bid) error {
-	// Valida se o leilão existe e está ativo
-	auction, err := br.AuctionRepository.FindAuctionById(ctx, bid.AuctionId)
+	ctx, done := tracing.Repository(ctx, "bid", "CreateBid")
+	defer done()
+
+	// O agregado valida o leilão (existente, ativo, não expirado), a moeda e o valor contra
+	// o maior lance; um lance concorrente gravado antes faz a validação ser repetida. Só um
+	// lance válido reserva saldo, e a reserva repetida depois de um conflito não é aplicada
+	// de novo. A prorrogação é gravada junto com o lance.
+	var previousHighest *entity.Bid
+	held := false
+	aggregate, err := br.events.Execute(ctx, bid.AuctionId, func(aggregate *entity.AuctionAggregate) error {
+		previousHighest = aggregate.HighestBid
+		if err := aggregate.PlaceBid(bid); err != nil {
+			return err
+		}
+		if err := aggregate.ExtendAfterBid(bid, br.extensionWindow); err != nil {
+			return err
+		}
+		if err := br.wallet.HoldBid(ctx, *bid); err != nil {
+			return err
+		}
+		held = true
+		return nil
+	})
 	if err != nil {
+		// O lance não foi gravado, então a reserva volta a ser a que existia antes dele
+		if held {
+			if revertErr := br.wallet.RevertBidHold(ctx, *bid); revertErr != nil {
+				br.logger.ErrorContext(ctx, "Error reverting bid hold",
+					logging.BidId(bid.Id), logging.AuctionId(bid.AuctionId), logging.UserId(bid.UserId), logging.Err(revertErr))
+			}
+		}
+
+		// Lances recusados pelas regras não são falhas da aplicação
+		var domainErr *entity.DomainError
+		if !errors.As(err, &domainErr) {
+			br.logger.ErrorContext(ctx, "Error creating bid",
+				logging.BidId(bid.Id), logging.AuctionId(bid.AuctionId), logging.UserId(bid.UserId), logging.Err(err))
+		}
 		return err
 	}
 
-	if auction == nil {
-		return errors.New("auction not found")
-	}
+	br.logger.InfoContext(ctx, "Bid created",
+		logging.BidId(bid.Id), logging.AuctionId(bid.AuctionId), logging.UserId(bid.UserId),
+		slog.Int64("amount_cents", bid.Amount.Cents), slog.String("currency", string(bid.Amount.Currency)),
+		slog.Time("expires_at", aggregate.Auction.ExpiresAt))
 
-	// Verifica se o leilão está ativo
-	if auction.Status != entity.Active {
-		return errors.New("auction is not active")
+	for _, listener := range br.listeners {
+		listener(ctx, *bid, previousHighest)
 	}
 
-	// Verifica se o leilão expirou
-	if auction.IsExpired() {
-		return errors.New("auction has expired")
-	}
+	return nil
+}
 
-	bidEntityMongo := &entity.BidEntityMongo{
-		Id:        bid.Id,
-		UserId:    bid.UserId,
-		AuctionId: bid.AuctionId,
-		Amount:    bid.Amount,
-		Timestamp: bid.Timestamp.Unix(),
-	}
+// FindBidByAuctionId busca uma página de lances de um leilão e retorna o cursor da próxima página
+func (br *BidRepository) FindBidByAuctionId(ctx context.Context, auctionId string, page entity.PageRequest) ([]entity.Bid, string, error) {
+	ctx, done := tracing.Repository(ctx, "bid", "FindBidByAuctionId")
+	defer done()
 
-	_, err = br.Collection.InsertOne(ctx, bidEntityMongo)
+	page = page.WithDefaults("timestamp", entity.Ascending)
+	filter, opts, err := keyset.Query(bson.M{"auction_id": auctionId}, page)
 	if err != nil {
-		log.Printf("Error creating bid: %v", err)
-		return err
+		return nil, "", err
 	}
 
-	log.Printf("Bid created successfully: %s for auction: %s", bid.Id, bid.AuctionId)
-	return nil
-}
-
-// FindBidByAuctionId busca todos os lances de um leilão
-func (br *BidRepository) FindBidByAuctionId(ctx context.Context, auctionId string) ([]entity.Bid, error) {
-	filter := bson.M{"auction_id": auctionId}
-
-	cursor, err := br.Collection.Find(ctx, filter)
+	cursor, err := br.Collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer cursor.Close(ctx)
 
 	var bidEntitiesMongo []entity.BidEntityMongo
 	if err := cursor.All(ctx, &bidEntitiesMongo); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var bids []entity.Bid
@@ -84,17 +128,52 @@ func (br *BidRepository) FindBidByAuctionId(ctx context.Context, auctionId strin
 			Id:        bidMongo.Id,
 			UserId:    bidMongo.UserId,
 			AuctionId: bidMongo.AuctionId,
-			Amount:    bidMongo.Amount,
+			Amount:    bidMongo.Money(),
+			Timestamp: time.UnixMilli(bidMongo.Timestamp),
 		})
 	}
 
-	return bids, nil
+	if !keyset.HasMore(page, len(bids)) {
+		return bids, "", nil
+	}
+
+	bids = bids[:page.Limit]
+	last := bids[len(bids)-1]
+	sortValue := float64(last.Amount.Cents)
+	if page.SortBy == "timestamp" {
+		sortValue = float64(last.Timestamp.UnixMilli())
+	}
+
+	return bids, keyset.NextCursor(page, sortValue, last.Id), nil
 }
 
-// FindWinningBidByAuctionId busca o lance vencedor (maior valor) de um leilão
+// winningBidSort ordena pelo maior valor; em caso de empate vence o lance mais antigo
+var winningBidSort = bson.D{{Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}
+
+// FindWinningBidByAuctionId busca o lance vencedor (maior valor) de um leilão. O valor é
+// gravado em centavos inteiros, então a ordenação não sofre com arredondamento.
 func (br *BidRepository) FindWinningBidByAuctionId(ctx context.Context, auctionId string) (*entity.Bid, error) {
+	ctx, done := tracing.Repository(ctx, "bid", "FindWinningBidByAuctionId")
+	defer done()
+
+	return br.findTopBid(ctx, bson.M{"auction_id": auctionId})
+}
+
+// FindNextHighestBid busca o maior lance do leilão entre os usuários que não estão em
+// excludedUserIds, usado para oferecer o item a quem ficou atrás do vencedor
+func (br *BidRepository) FindNextHighestBid(ctx context.Context, auctionId string, excludedUserIds []string) (*entity.Bid, error) {
+	ctx, done := tracing.Repository(ctx, "bid", "FindNextHighestBid")
+	defer done()
+
 	filter := bson.M{"auction_id": auctionId}
-	opts := options.FindOne().SetSort(bson.D{{Key: "amount", Value: -1}})
+	if len(excludedUserIds) > 0 {
+		filter["user_id"] = bson.M{"$nin": excludedUserIds}
+	}
+	return br.findTopBid(ctx, filter)
+}
+
+func (br *BidRepository) findTopBid(ctx context.Context, filter bson.M) (*entity.Bid, error) {
+	opts := options.FindOne().SetSort(winningBidSort)
 
 	var bidEntityMongo entity.BidEntityMongo
 	err := br.Collection.FindOne(ctx, filter, opts).Decode(&bidEntityMongo)
@@ -109,6 +188,52 @@ func (br *BidRepository) FindWinningBidByAuctionId(ctx context.Context, auctionI
 		Id:        bidEntityMongo.Id,
 		UserId:    bidEntityMongo.UserId,
 		AuctionId: bidEntityMongo.AuctionId,
-		Amount:    bidEntityMongo.Amount,
+		Amount:    bidEntityMongo.Money(),
+		Timestamp: time.UnixMilli(bidEntityMongo.Timestamp),
 	}, nil
 }
+
+// FindHighestBidsByAuctionIds busca o maior lance de cada leilão informado com uma única agregação
+func (br *BidRepository) FindHighestBidsByAuctionIds(ctx context.Context, auctionIds []string) (map[string]entity.Bid, error) {
+	ctx, done := tracing.Repository(ctx, "bid", "FindHighestBidsByAuctionIds")
+	defer done()
+
+	highestBids := make(map[string]entity.Bid)
+	if len(auctionIds) == 0 {
+		return highestBids, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"auction_id": bson.M{"$in": auctionIds}}}},
+		{{Key: "$sort", Value: winningBidSort}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$auction_id",
+			"highest": bson.M{"$first": "$$ROOT"},
+		}}},
+	}
+
+	cursor, err := br.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Highest entity.BidEntityMongo `bson:"highest"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		highestBids[result.Highest.AuctionId] = entity.Bid{
+			Id:        result.Highest.Id,
+			UserId:    result.Highest.UserId,
+			AuctionId: result.Highest.AuctionId,
+			Amount:    result.Highest.Money(),
+			Timestamp: time.UnixMilli(result.Highest.Timestamp),
+		}
+	}
+
+	return highestBids, nil
+}
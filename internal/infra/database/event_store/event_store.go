@@ -0,0 +1,232 @@
+package event_store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxCommandAttempts limita as releituras do stream quando outro comando grava primeiro
+const maxCommandAttempts = 5
+
+// AuctionEventStore guarda o stream de eventos de cada leilão na coleção auction_events,
+// a fonte da verdade dos leilões e lances. As coleções auctions e bids são projeções.
+type AuctionEventStore struct {
+	Collection *mongo.Collection
+	projection *AuctionProjection
+	logger     *slog.Logger
+}
+
+func NewAuctionEventStore(database *mongo.Database, logger *slog.Logger) *AuctionEventStore {
+	return &AuctionEventStore{
+		Collection: database.Collection("auction_events"),
+		projection: NewAuctionProjection(database),
+		logger:     logger,
+	}
+}
+
+// Execute carrega o agregado, aplica o comando e grava os eventos gerados. Se outro
+// comando gravar no stream antes, o agregado é recarregado e o comando executado de novo,
+// então ele deve poder ser repetido. Retorna o agregado com os eventos já aplicados.
+func (es *AuctionEventStore) Execute(ctx context.Context, auctionId string, command func(aggregate *entity.AuctionAggregate) error) (*entity.AuctionAggregate, error) {
+	for attempt := 1; ; attempt++ {
+		aggregate, err := es.LoadAggregate(ctx, auctionId)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := command(aggregate); err != nil {
+			return nil, err
+		}
+
+		err = es.commit(ctx, aggregate)
+		if errors.Is(err, entity.ErrConcurrencyConflict) && attempt < maxCommandAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return aggregate, nil
+	}
+}
+
+// LoadAggregate reconstrói o leilão a partir do stream; sem eventos, o agregado não existe
+func (es *AuctionEventStore) LoadAggregate(ctx context.Context, auctionId string) (*entity.AuctionAggregate, error) {
+	events, err := es.Load(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+	return entity.LoadAuctionAggregate(events)
+}
+
+// commit grava os eventos novos e os projeta. Uma falha na projeção não desfaz o comando,
+// que já está gravado: ela fica no log e é corrigida pelo replay.
+func (es *AuctionEventStore) commit(ctx context.Context, aggregate *entity.AuctionAggregate) error {
+	changes := aggregate.Changes()
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := es.Append(ctx, aggregate.Auction.Id, aggregate.Version, changes); err != nil {
+		return err
+	}
+
+	for _, event := range changes {
+		if err := es.projection.Apply(ctx, event); err != nil {
+			es.logger.ErrorContext(ctx, "Error projecting auction event",
+				logging.AuctionId(event.AuctionId), slog.Int64("version", event.Version), slog.String("type", string(event.Type)), logging.Err(err))
+			break
+		}
+	}
+	return nil
+}
+
+// Load retorna os eventos do leilão em ordem de versão
+func (es *AuctionEventStore) Load(ctx context.Context, auctionId string) ([]entity.AuctionEvent, error) {
+	ctx, done := tracing.Repository(ctx, "event_store", "Load")
+	defer done()
+
+	return es.find(ctx, bson.M{"auction_id": auctionId})
+}
+
+// LoadAfter retorna os eventos do leilão gravados depois da versão informada, em ordem de
+// versão; com a versão 0 retorna o stream inteiro
+func (es *AuctionEventStore) LoadAfter(ctx context.Context, auctionId string, version int64) ([]entity.AuctionEvent, error) {
+	ctx, done := tracing.Repository(ctx, "event_store", "LoadAfter")
+	defer done()
+
+	return es.find(ctx, bson.M{"auction_id": auctionId, "version": bson.M{"$gt": version}})
+}
+
+func (es *AuctionEventStore) find(ctx context.Context, filter bson.M) ([]entity.AuctionEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+	cursor, err := es.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []entity.AuctionEvent
+	for cursor.Next(ctx) {
+		event, err := decodeAuctionEvent(cursor)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, cursor.Err()
+}
+
+// Append grava os eventos se a versão atual do stream for expectedVersion. O índice único
+// em (auction_id, version) recusa o primeiro evento quando outro comando já usou a versão.
+// Só a criação gera mais de um evento, e nenhum outro comando grava num stream que ainda
+// não existe, então os eventos de um comando são gravados todos ou nenhum.
+func (es *AuctionEventStore) Append(ctx context.Context, auctionId string, expectedVersion int64, events []entity.AuctionEvent) error {
+	ctx, done := tracing.Repository(ctx, "event_store", "Append")
+	defer done()
+
+	documents := make([]any, 0, len(events))
+	for i, event := range events {
+		if event.AuctionId != auctionId || event.Version != expectedVersion+int64(i)+1 {
+			return fmt.Errorf("event %s does not follow version %d of auction %s", event.Type, expectedVersion+int64(i), auctionId)
+		}
+		documents = append(documents, entity.AuctionEventEntityMongo{
+			Id:         event.Id,
+			AuctionId:  event.AuctionId,
+			Version:    event.Version,
+			Type:       event.Type,
+			OccurredAt: event.OccurredAt.UnixMilli(),
+			Data:       event.Data,
+		})
+	}
+
+	_, err := es.Collection.InsertMany(ctx, documents)
+	if mongo.IsDuplicateKeyError(err) {
+		return entity.ErrConcurrencyConflict
+	}
+	return err
+}
+
+// Reproject aplica de novo todos os eventos do leilão, para corrigir uma projeção que ficou
+// para trás depois de uma falha; as alterações da projeção são idempotentes
+func (es *AuctionEventStore) Reproject(ctx context.Context, auctionId string) error {
+	events, err := es.Load(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := es.projection.Apply(ctx, event); err != nil {
+			return fmt.Errorf("auction %s, version %d: %w", event.AuctionId, event.Version, err)
+		}
+	}
+	return nil
+}
+
+// Replay apaga as projeções e as reconstrói aplicando todos os eventos, leilão por leilão.
+// A API deve estar parada, para que nenhum comando grave durante a reconstrução.
+func (es *AuctionEventStore) Replay(ctx context.Context) (int, error) {
+	ctx, done := tracing.Repository(ctx, "event_store", "Replay")
+	defer done()
+
+	if err := es.projection.Reset(ctx); err != nil {
+		return 0, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "auction_id", Value: 1}, {Key: "version", Value: 1}})
+	cursor, err := es.Collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	count := 0
+	for cursor.Next(ctx) {
+		event, err := decodeAuctionEvent(cursor)
+		if err != nil {
+			return count, err
+		}
+		if err := es.projection.Apply(ctx, event); err != nil {
+			return count, fmt.Errorf("auction %s, version %d: %w", event.AuctionId, event.Version, err)
+		}
+		count++
+	}
+	return count, cursor.Err()
+}
+
+// decodeAuctionEvent decodifica o documento atual do cursor, com o payload do tipo do evento
+func decodeAuctionEvent(cursor *mongo.Cursor) (entity.AuctionEvent, error) {
+	var eventMongo struct {
+		entity.AuctionEventEntityMongo `bson:",inline"`
+		Data                           bson.Raw `bson:"data"`
+	}
+	if err := cursor.Decode(&eventMongo); err != nil {
+		return entity.AuctionEvent{}, err
+	}
+
+	data, err := entity.NewAuctionEventData(eventMongo.Type)
+	if err != nil {
+		return entity.AuctionEvent{}, err
+	}
+	if err := bson.Unmarshal(eventMongo.Data, data); err != nil {
+		return entity.AuctionEvent{}, fmt.Errorf("auction %s, version %d: %w", eventMongo.AuctionId, eventMongo.Version, err)
+	}
+
+	return entity.AuctionEvent{
+		Id:         eventMongo.Id,
+		AuctionId:  eventMongo.AuctionId,
+		Version:    eventMongo.Version,
+		Type:       eventMongo.Type,
+		OccurredAt: time.UnixMilli(eventMongo.OccurredAt),
+		Data:       reflect.ValueOf(data).Elem().Interface(),
+	}, nil
+}
@@ -0,0 +1,112 @@
+package event_store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/auction-goexpert/internal/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuctionProjection mantém as coleções de leitura auctions e bids a partir dos eventos.
+// Comandos concorrentes podem projetar fora de ordem, então cada alteração é idempotente
+// e comutativa: preço, expiração e versão só aumentam ($max), e o status só é alterado
+// pelo evento que encerra o leilão, que é único no stream.
+type AuctionProjection struct {
+	Auctions *mongo.Collection
+	Bids     *mongo.Collection
+}
+
+func NewAuctionProjection(database *mongo.Database) *AuctionProjection {
+	return &AuctionProjection{
+		Auctions: database.Collection("auctions"),
+		Bids:     database.Collection("bids"),
+	}
+}
+
+// Apply projeta um evento nas coleções de leitura
+func (ap *AuctionProjection) Apply(ctx context.Context, event entity.AuctionEvent) error {
+	switch data := event.Data.(type) {
+	case entity.AuctionCreatedData:
+		// Os leilões aceitam lances desde a criação, então já entram como ativos
+		auction := entity.AuctionEntityMongo{
+			Id:           event.AuctionId,
+			ProductName:  data.ProductName,
+			Category:     data.Category,
+			Description:  data.Description,
+			Condition:    data.Condition,
+			Status:       entity.Active,
+			Currency:     data.Currency.OrDefault(),
+			CurrentPrice: 0,
+			ReservePrice: data.ReservePrice,
+			SellerId:     data.SellerId,
+			Timestamp:    event.OccurredAt.UnixMilli(),
+			ExpiresAt:    data.ExpiresAt.UnixMilli(),
+			Version:      event.Version,
+		}
+		_, err := ap.Auctions.UpdateOne(ctx, bson.M{"_id": event.AuctionId},
+			bson.M{"$setOnInsert": auction}, options.Update().SetUpsert(true))
+		return err
+
+	case entity.AuctionStartedData:
+		// O leilão já foi gravado como ativo por Created
+		return ap.updateAuction(ctx, event, bson.M{})
+
+	case entity.BidPlacedData:
+		bid := entity.BidEntityMongo{
+			Id:        data.BidId,
+			UserId:    data.UserId,
+			AuctionId: event.AuctionId,
+			Amount:    data.Amount,
+			Currency:  data.Currency,
+			Timestamp: event.OccurredAt.UnixMilli(),
+		}
+		_, err := ap.Bids.UpdateOne(ctx, bson.M{"_id": data.BidId},
+			bson.M{"$setOnInsert": bid}, options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+		return ap.updateAuction(ctx, event, bson.M{"$max": bson.M{"current_price": data.Amount}})
+
+	case entity.AuctionExtendedData:
+		return ap.updateAuction(ctx, event, bson.M{"$max": bson.M{"expires_at": data.ExpiresAt.UnixMilli()}})
+
+	case entity.AuctionClosedData:
+		// closing_pending_at fica até os listeners do encerramento terminarem; aplicado de novo
+		// (no replay, por exemplo), faz os listeners rodarem outra vez, sem efeito repetido
+		return ap.updateAuction(ctx, event, bson.M{"$set": bson.M{
+			"status":             entity.Completed,
+			"winning_bid_id":     data.WinningBidId,
+			"closing_pending_at": event.OccurredAt.UnixMilli(),
+		}})
+
+	case entity.AuctionCancelledData:
+		return ap.updateAuction(ctx, event, bson.M{"$set": bson.M{"status": entity.Cancelled}})
+	}
+
+	return fmt.Errorf("auction %s: cannot project event %s", event.AuctionId, event.Type)
+}
+
+// updateAuction aplica a alteração e registra a versão do evento no leilão
+func (ap *AuctionProjection) updateAuction(ctx context.Context, event entity.AuctionEvent, update bson.M) error {
+	increases, _ := update["$max"].(bson.M)
+	if increases == nil {
+		increases = bson.M{}
+		update["$max"] = increases
+	}
+	increases["version"] = event.Version
+
+	_, err := ap.Auctions.UpdateOne(ctx, bson.M{"_id": event.AuctionId}, update)
+	return err
+}
+
+// Reset apaga as projeções antes do replay; os índices são mantidos
+func (ap *AuctionProjection) Reset(ctx context.Context) error {
+	if _, err := ap.Auctions.DeleteMany(ctx, bson.M{}); err != nil {
+		return err
+	}
+	_, err := ap.Bids.DeleteMany(ctx, bson.M{})
+	return err
+}
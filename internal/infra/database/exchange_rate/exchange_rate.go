@@ -0,0 +1,128 @@
+package exchange_rate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ExchangeRateRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewExchangeRateRepository(database *mongo.Database) *ExchangeRateRepository {
+	return &ExchangeRateRepository{
+		Collection: database.Collection("exchange_rates"),
+	}
+}
+
+// UpsertExchangeRate grava a cotação; reenviar o mesmo par e data substitui o valor anterior
+func (er *ExchangeRateRepository) UpsertExchangeRate(ctx context.Context, rate entity.ExchangeRate) error {
+	ctx, done := tracing.Repository(ctx, "exchange_rate", "UpsertExchangeRate")
+	defer done()
+
+	rateEntityMongo := &entity.ExchangeRateEntityMongo{
+		Id:          exchangeRateId(rate.From, rate.To, rate.EffectiveAt),
+		From:        rate.From,
+		To:          rate.To,
+		Rate:        rate.Rate,
+		EffectiveAt: rate.EffectiveAt.Unix(),
+	}
+
+	filter := bson.M{"_id": rateEntityMongo.Id}
+	_, err := er.Collection.ReplaceOne(ctx, filter, rateEntityMongo, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindExchangeRates lista todas as cotações cadastradas, agrupadas por par e da mais recente para a mais antiga
+func (er *ExchangeRateRepository) FindExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	ctx, done := tracing.Repository(ctx, "exchange_rate", "FindExchangeRates")
+	defer done()
+
+	opts := options.Find().SetSort(bson.D{{Key: "from", Value: 1}, {Key: "to", Value: 1}, {Key: "effective_at", Value: -1}})
+
+	cursor, err := er.Collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rateEntitiesMongo []entity.ExchangeRateEntityMongo
+	if err := cursor.All(ctx, &rateEntitiesMongo); err != nil {
+		return nil, err
+	}
+
+	var rates []entity.ExchangeRate
+	for _, rateMongo := range rateEntitiesMongo {
+		rates = append(rates, toExchangeRate(rateMongo))
+	}
+
+	return rates, nil
+}
+
+// FindEffectiveRate busca a cotação mais recente vigente em at, recorrendo à inversa do par
+func (er *ExchangeRateRepository) FindEffectiveRate(ctx context.Context, from, to entity.Currency, at time.Time) (*entity.ExchangeRate, error) {
+	ctx, done := tracing.Repository(ctx, "exchange_rate", "FindEffectiveRate")
+	defer done()
+
+	if from == to {
+		return entity.IdentityRate(from, at), nil
+	}
+
+	rate, err := er.findLatestRate(ctx, from, to, at)
+	if err != nil || rate != nil {
+		return rate, err
+	}
+
+	inverse, err := er.findLatestRate(ctx, to, from, at)
+	if err != nil {
+		return nil, err
+	}
+
+	if inverse == nil {
+		return nil, entity.ErrExchangeRateNotFound.WithMessage("exchange rate not found: %s to %s", from, to)
+	}
+
+	converted := inverse.Inverse()
+	return &converted, nil
+}
+
+func (er *ExchangeRateRepository) findLatestRate(ctx context.Context, from, to entity.Currency, at time.Time) (*entity.ExchangeRate, error) {
+	filter := bson.M{
+		"from":         from,
+		"to":           to,
+		"effective_at": bson.M{"$lte": at.Unix()},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "effective_at", Value: -1}})
+
+	var rateEntityMongo entity.ExchangeRateEntityMongo
+	err := er.Collection.FindOne(ctx, filter, opts).Decode(&rateEntityMongo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rate := toExchangeRate(rateEntityMongo)
+	return &rate, nil
+}
+
+func toExchangeRate(rateMongo entity.ExchangeRateEntityMongo) entity.ExchangeRate {
+	return entity.ExchangeRate{
+		From:        rateMongo.From,
+		To:          rateMongo.To,
+		Rate:        rateMongo.Rate,
+		EffectiveAt: time.Unix(rateMongo.EffectiveAt, 0),
+	}
+}
+
+func exchangeRateId(from, to entity.Currency, effectiveAt time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", from, to, effectiveAt.Unix())
+}
@@ -0,0 +1,133 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type IdempotencyRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewIdempotencyRepository(database *mongo.Database) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		Collection: database.Collection("idempotency_keys"),
+	}
+}
+
+// Reserve insere o registro; o _id único garante que só uma requisição reserve a chave.
+// Uma reserva cujo LockedUntil passou (processo que caiu no meio) pode ser assumida.
+func (ir *IdempotencyRepository) Reserve(ctx context.Context, record entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	ctx, done := tracing.Repository(ctx, "idempotency", "Reserve")
+	defer done()
+
+	recordEntityMongo := &entity.IdempotencyRecordEntityMongo{
+		Id:          record.Id,
+		Owner:       record.Owner,
+		RequestHash: record.RequestHash,
+		Status:      entity.IdempotencyProcessing,
+		LockedUntil: record.LockedUntil.UnixMilli(),
+		ExpiresAt:   record.ExpiresAt,
+	}
+
+	var existing *entity.IdempotencyRecord
+	for existing == nil {
+		_, err := ir.Collection.InsertOne(ctx, recordEntityMongo)
+		if err == nil {
+			return nil, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+
+		// Se a reserva foi liberada entre o insert e a busca, tenta inserir de novo
+		existing, err = ir.findRecord(ctx, record.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if existing.Status == entity.IdempotencyProcessing && existing.RequestHash == record.RequestHash && existing.LockedUntil.Before(time.Now()) {
+		filter := bson.M{
+			"_id":          record.Id,
+			"status":       entity.IdempotencyProcessing,
+			"locked_until": existing.LockedUntil.UnixMilli(),
+		}
+		update := bson.M{"$set": bson.M{"owner": record.Owner, "locked_until": record.LockedUntil.UnixMilli(), "expires_at": record.ExpiresAt}}
+
+		result, err := ir.Collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return nil, err
+		}
+		if result.ModifiedCount == 1 {
+			return nil, nil
+		}
+	}
+
+	return existing, nil
+}
+
+// Complete guarda a resposta para ser reenviada nas próximas requisições com a mesma chave.
+// O filtro por owner impede que uma requisição atrasada sobrescreva a reserva assumida por
+// outra.
+func (ir *IdempotencyRepository) Complete(ctx context.Context, id, owner string, status int, contentType string, body []byte) error {
+	ctx, done := tracing.Repository(ctx, "idempotency", "Complete")
+	defer done()
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":          entity.IdempotencyCompleted,
+			"response_status": status,
+			"content_type":    contentType,
+			"response_body":   body,
+		},
+	}
+
+	filter := bson.M{"_id": id, "owner": owner, "status": entity.IdempotencyProcessing}
+	result, err := ir.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return entity.ErrIdempotencyLockLost
+	}
+	return nil
+}
+
+// Release remove a reserva se ela ainda for de owner; uma reserva já assumida por outra
+// requisição é mantida
+func (ir *IdempotencyRepository) Release(ctx context.Context, id, owner string) error {
+	ctx, done := tracing.Repository(ctx, "idempotency", "Release")
+	defer done()
+
+	_, err := ir.Collection.DeleteOne(ctx, bson.M{"_id": id, "owner": owner, "status": entity.IdempotencyProcessing})
+	return err
+}
+
+func (ir *IdempotencyRepository) findRecord(ctx context.Context, id string) (*entity.IdempotencyRecord, error) {
+	var recordEntityMongo entity.IdempotencyRecordEntityMongo
+	err := ir.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&recordEntityMongo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &entity.IdempotencyRecord{
+		Id:             recordEntityMongo.Id,
+		Owner:          recordEntityMongo.Owner,
+		RequestHash:    recordEntityMongo.RequestHash,
+		Status:         recordEntityMongo.Status,
+		ResponseStatus: recordEntityMongo.ResponseStatus,
+		ContentType:    recordEntityMongo.ContentType,
+		ResponseBody:   recordEntityMongo.ResponseBody,
+		LockedUntil:    time.UnixMilli(recordEntityMongo.LockedUntil),
+		ExpiresAt:      recordEntityMongo.ExpiresAt,
+	}, nil
+}
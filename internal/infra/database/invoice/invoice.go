@@ -0,0 +1,173 @@
+package invoice
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InvoiceRepository guarda as faturas dos leilões encerrados. O _id identifica a parte e a
+// tentativa de acerto (veja entity.InvoiceId), então cada fatura é emitida uma única vez.
+type InvoiceRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewInvoiceRepository(database *mongo.Database) *InvoiceRepository {
+	return &InvoiceRepository{
+		Collection: database.Collection("invoices"),
+	}
+}
+
+// CreateInvoices grava as faturas; a inserção não é ordenada, então uma fatura já emitida
+// não impede a gravação das demais
+func (ir *InvoiceRepository) CreateInvoices(ctx context.Context, invoices []entity.Invoice) error {
+	ctx, done := tracing.Repository(ctx, "invoice", "CreateInvoices")
+	defer done()
+
+	if len(invoices) == 0 {
+		return nil
+	}
+
+	documents := make([]any, 0, len(invoices))
+	for _, invoice := range invoices {
+		documents = append(documents, toInvoiceEntityMongo(invoice))
+	}
+
+	_, err := ir.Collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err != nil && !onlyDuplicateKeys(err) {
+		return err
+	}
+	return nil
+}
+
+func (ir *InvoiceRepository) FindInvoiceById(ctx context.Context, invoiceId string) (*entity.Invoice, error) {
+	ctx, done := tracing.Repository(ctx, "invoice", "FindInvoiceById")
+	defer done()
+
+	var invoiceMongo entity.InvoiceEntityMongo
+	err := ir.Collection.FindOne(ctx, bson.M{"_id": invoiceId}).Decode(&invoiceMongo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	invoice := toInvoice(invoiceMongo)
+	return &invoice, nil
+}
+
+// VoidInvoices anula as faturas do leilão que ainda valem e não foram reemitidas para
+// currentAttempt, inclusive as do encerramento. Uma anulação repetida não altera o voided_at
+// já gravado.
+func (ir *InvoiceRepository) VoidInvoices(ctx context.Context, auctionId string, currentAttempt int, at time.Time) error {
+	ctx, done := tracing.Repository(ctx, "invoice", "VoidInvoices")
+	defer done()
+
+	filter := bson.M{
+		"auction_id": auctionId,
+		"status":     entity.InvoiceIssued,
+		"attempt":    bson.M{"$lte": currentAttempt},
+		"_id": bson.M{"$nin": bson.A{
+			entity.ReissuedInvoiceId(auctionId, entity.InvoiceSeller, currentAttempt),
+			entity.ReissuedInvoiceId(auctionId, entity.InvoiceBuyer, currentAttempt),
+		}},
+	}
+	update := bson.M{"$set": bson.M{"status": entity.InvoiceVoided, "voided_at": at.UnixMilli()}}
+
+	_, err := ir.Collection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+func (ir *InvoiceRepository) FindInvoicesIssuedBetween(ctx context.Context, from, to time.Time) ([]entity.Invoice, error) {
+	ctx, done := tracing.Repository(ctx, "invoice", "FindInvoicesIssuedBetween")
+	defer done()
+
+	filter := bson.M{"issued_at": bson.M{"$gte": from.UnixMilli(), "$lte": to.UnixMilli()}}
+	opts := options.Find().SetSort(bson.D{{Key: "issued_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := ir.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var invoiceEntitiesMongo []entity.InvoiceEntityMongo
+	if err := cursor.All(ctx, &invoiceEntitiesMongo); err != nil {
+		return nil, err
+	}
+
+	invoices := make([]entity.Invoice, 0, len(invoiceEntitiesMongo))
+	for _, invoiceMongo := range invoiceEntitiesMongo {
+		invoices = append(invoices, toInvoice(invoiceMongo))
+	}
+	return invoices, nil
+}
+
+// onlyDuplicateKeys informa se todos os erros da inserção são de faturas já emitidas
+func onlyDuplicateKeys(err error) bool {
+	bulkErr, ok := err.(mongo.BulkWriteException)
+	if !ok || bulkErr.WriteConcernError != nil {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if !writeErr.HasErrorCode(11000) {
+			return false
+		}
+	}
+	return true
+}
+
+func toInvoiceEntityMongo(invoice entity.Invoice) entity.InvoiceEntityMongo {
+	lines := make([]entity.InvoiceLineEntityMongo, 0, len(invoice.Lines))
+	for _, line := range invoice.Lines {
+		lines = append(lines, entity.InvoiceLineEntityMongo{Kind: line.Kind, Description: line.Description, Amount: line.Amount.Cents})
+	}
+
+	return entity.InvoiceEntityMongo{
+		Id:        invoice.Id,
+		AuctionId: invoice.AuctionId,
+		Attempt:   invoice.Attempt,
+		Party:     invoice.Party,
+		UserId:    invoice.UserId,
+		Currency:  invoice.Currency,
+		Status:    invoice.Status,
+		Lines:     lines,
+		Subtotal:  invoice.Subtotal.Cents,
+		Tax:       invoice.Tax.Cents,
+		Total:     invoice.Total.Cents,
+		IssuedAt:  invoice.IssuedAt.UnixMilli(),
+	}
+}
+
+func toInvoice(invoiceMongo entity.InvoiceEntityMongo) entity.Invoice {
+	currency := invoiceMongo.Currency
+	lines := make([]entity.InvoiceLine, 0, len(invoiceMongo.Lines))
+	for _, line := range invoiceMongo.Lines {
+		lines = append(lines, entity.InvoiceLine{Kind: line.Kind, Description: line.Description, Amount: entity.NewMoney(line.Amount, currency)})
+	}
+
+	invoice := entity.Invoice{
+		Id:        invoiceMongo.Id,
+		AuctionId: invoiceMongo.AuctionId,
+		Attempt:   invoiceMongo.Attempt,
+		Party:     invoiceMongo.Party,
+		UserId:    invoiceMongo.UserId,
+		Currency:  currency,
+		Status:    invoiceMongo.Status,
+		Lines:     lines,
+		Subtotal:  entity.NewMoney(invoiceMongo.Subtotal, currency),
+		Tax:       entity.NewMoney(invoiceMongo.Tax, currency),
+		Total:     entity.NewMoney(invoiceMongo.Total, currency),
+		IssuedAt:  time.UnixMilli(invoiceMongo.IssuedAt),
+	}
+	if invoiceMongo.VoidedAt != 0 {
+		voidedAt := time.UnixMilli(invoiceMongo.VoidedAt)
+		invoice.VoidedAt = &voidedAt
+	}
+	return invoice
+}
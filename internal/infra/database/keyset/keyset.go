@@ -0,0 +1,117 @@
+package keyset
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/auction-goexpert/internal/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// cursor guarda a posição do último item de uma página. O campo e a direção da
+// ordenação fazem parte do cursor para que ele não seja reutilizado com outra ordenação.
+type cursor struct {
+	SortBy string           `json:"s"`
+	Order  entity.SortOrder `json:"o"`
+	Value  float64          `json:"v"`
+	Id     string           `json:"id"`
+}
+
+// Query monta o filtro e as opções de busca de uma página ordenada por page.SortBy e _id.
+// Uma página já deve ter passado por PageRequest.WithDefaults.
+func Query(filter bson.M, page entity.PageRequest) (bson.M, *options.FindOptions, error) {
+	direction := 1
+	comparison := "$gt"
+	if page.Order == entity.Descending {
+		direction = -1
+		comparison = "$lt"
+	}
+
+	if page.Cursor != "" {
+		after, err := decode(page)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if after.Id == "" {
+			return nil, nil, entity.ErrInvalidCursor
+		}
+
+		position := bson.M{"$or": bson.A{
+			bson.M{page.SortBy: bson.M{comparison: after.Value}},
+			bson.M{page.SortBy: after.Value, "_id": bson.M{comparison: after.Id}},
+		}}
+
+		if len(filter) == 0 {
+			filter = position
+		} else {
+			filter = bson.M{"$and": bson.A{filter, position}}
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: page.SortBy, Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(page.Limit) + 1)
+
+	return filter, opts, nil
+}
+
+// HasMore informa se a consulta retornou mais itens do que o limite da página,
+// o que indica que existe uma próxima página
+func HasMore(page entity.PageRequest, count int) bool {
+	return count > page.Limit
+}
+
+// NextCursor gera o cursor que aponta para depois do último item da página
+func NextCursor(page entity.PageRequest, value float64, id string) string {
+	data, _ := json.Marshal(cursor{
+		SortBy: page.SortBy,
+		Order:  page.Order,
+		Value:  value,
+		Id:     id,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Offset retorna quantos itens devem ser pulados em ordenações que não permitem
+// keyset, como a relevância da busca textual
+func Offset(page entity.PageRequest) (int64, error) {
+	if page.Cursor == "" {
+		return 0, nil
+	}
+
+	after, err := decode(page)
+	if err != nil {
+		return 0, err
+	}
+
+	if after.Id != "" || after.Value < 0 {
+		return 0, entity.ErrInvalidCursor
+	}
+
+	return int64(after.Value), nil
+}
+
+// NextOffsetCursor gera o cursor de uma página baseada em deslocamento
+func NextOffsetCursor(page entity.PageRequest, offset int64) string {
+	return NextCursor(page, float64(offset), "")
+}
+
+func decode(page entity.PageRequest) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(page.Cursor)
+	if err != nil {
+		return nil, entity.ErrInvalidCursor
+	}
+
+	var after cursor
+	if err := json.Unmarshal(data, &after); err != nil {
+		return nil, entity.ErrInvalidCursor
+	}
+
+	if after.SortBy != page.SortBy || after.Order != page.Order {
+		return nil, entity.ErrInvalidCursor
+	}
+
+	return &after, nil
+}
@@ -0,0 +1,48 @@
+package keyset
+
+import (
+	"testing"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestQueryWithoutCursor(t *testing.T) {
+	page := entity.PageRequest{}.WithDefaults("expires_at", entity.Ascending)
+
+	filter, opts, err := Query(bson.M{"status": entity.Active}, page)
+	assert.NoError(t, err)
+	assert.Equal(t, bson.M{"status": entity.Active}, filter)
+	assert.Equal(t, int64(entity.DefaultPageLimit+1), *opts.Limit)
+	assert.Equal(t, bson.D{{Key: "expires_at", Value: 1}, {Key: "_id", Value: 1}}, opts.Sort)
+}
+
+func TestQueryAfterCursor(t *testing.T) {
+	page := entity.PageRequest{SortBy: "amount", Order: entity.Descending, Limit: 10}
+	page.Cursor = NextCursor(page, 150.5, "bid-1")
+
+	filter, opts, err := Query(bson.M{"auction_id": "auction-1"}, page)
+	assert.NoError(t, err)
+	assert.Equal(t, bson.M{"$and": bson.A{
+		bson.M{"auction_id": "auction-1"},
+		bson.M{"$or": bson.A{
+			bson.M{"amount": bson.M{"$lt": 150.5}},
+			bson.M{"amount": 150.5, "_id": bson.M{"$lt": "bid-1"}},
+		}},
+	}}, filter)
+	assert.Equal(t, bson.D{{Key: "amount", Value: -1}, {Key: "_id", Value: -1}}, opts.Sort)
+}
+
+func TestQueryRejectsInvalidCursor(t *testing.T) {
+	page := entity.PageRequest{SortBy: "amount", Order: entity.Descending, Limit: 10}
+
+	page.Cursor = "not-a-cursor"
+	_, _, err := Query(bson.M{}, page)
+	assert.ErrorIs(t, err, entity.ErrInvalidCursor)
+
+	// Cursor gerado para outra ordenação
+	page.Cursor = NextCursor(entity.PageRequest{SortBy: "timestamp", Order: entity.Ascending}, 1, "bid-1")
+	_, _, err = Query(bson.M{}, page)
+	assert.ErrorIs(t, err, entity.ErrInvalidCursor)
+}
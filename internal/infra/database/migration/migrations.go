@@ -0,0 +1,562 @@
+package migration
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migrations é a lista de migrations da aplicação. Novas migrations devem ser
+// adicionadas ao final com a próxima versão; versões aplicadas nunca são alteradas.
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "create auction indexes",
+		Up:          createAuctionIndexes,
+	},
+	{
+		Version:     2,
+		Description: "create bid indexes",
+		Up:          createBidIndexes,
+	},
+	{
+		Version:     3,
+		Description: "create notification and watchlist indexes",
+		Up:          createNotificationIndexes,
+	},
+	{
+		Version:     4,
+		Description: "backfill auction current_price from bids",
+		Up:          backfillAuctionCurrentPrice,
+	},
+	{
+		Version:     5,
+		Description: "convert float amounts to integer cents",
+		Up:          convertAmountsToCents,
+	},
+	{
+		Version:     6,
+		Description: "add auction currency and exchange rate indexes",
+		Up:          addAuctionCurrency,
+	},
+	{
+		Version:     7,
+		Description: "store timestamps in milliseconds and index bid tie-break",
+		Up:          convertTimestampsToMillis,
+	},
+	{
+		Version:     8,
+		Description: "create idempotency key ttl index",
+		Up:          createIdempotencyIndexes,
+	},
+	{
+		Version:     9,
+		Description: "create rate limit ttl index",
+		Up:          createRateLimitIndexes,
+	},
+	{
+		Version:     10,
+		Description: "create audit log chain index",
+		Up:          createAuditLogIndexes,
+	},
+	{
+		Version:     11,
+		Description: "create auction event store and import existing auctions",
+		Up:          backfillAuctionEvents,
+	},
+	{
+		Version:     12,
+		Description: "create settlement indexes",
+		Up:          createSettlementIndexes,
+	},
+	{
+		Version:     13,
+		Description: "create second-chance offer indexes",
+		Up:          createOfferIndexes,
+	},
+	{
+		Version:     14,
+		Description: "create wallet ledger indexes",
+		Up:          createLedgerIndexes,
+	},
+	{
+		Version:     15,
+		Description: "create invoice indexes",
+		Up:          createInvoiceIndexes,
+	},
+	{
+		Version:     16,
+		Description: "index settlements with a pending expiry",
+		Up:          createSettlementExpiryIndex,
+	},
+	{
+		Version:     17,
+		Description: "index auctions and settlements with pending post-close work",
+		Up:          createPendingWorkIndexes,
+	},
+	{
+		Version:     18,
+		Description: "anchor audit chain heads and index pending audit entries",
+		Up:          anchorAuditHeads,
+	},
+}
+
+func createAuctionIndexes(ctx context.Context, database *mongo.Database) error {
+	// status + expires_at atende o fechamento automático e os lembretes de fim de leilão
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+	}
+
+	// Índices das listagens paginadas, com e sem filtro de status
+	for _, field := range []string{"expires_at", "timestamp", "current_price"} {
+		indexes = append(indexes,
+			mongo.IndexModel{Keys: bson.D{{Key: field, Value: 1}, {Key: "_id", Value: 1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: field, Value: 1}, {Key: "_id", Value: 1}}},
+		)
+	}
+
+	indexes = append(indexes,
+		mongo.IndexModel{Keys: bson.D{{Key: "seller_id", Value: 1}, {Key: "expires_at", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "category", Value: 1}, {Key: "expires_at", Value: 1}}},
+		// Índice de texto da busca; sem stemming porque os anúncios misturam idiomas
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "product_name", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().
+				SetName("auction_text").
+				SetWeights(bson.M{"product_name": 3, "description": 1}).
+				SetDefaultLanguage("none"),
+		},
+	)
+
+	_, err := database.Collection("auctions").Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
+func createBidIndexes(ctx context.Context, database *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		// FindWinningBidByAuctionId e FindHighestBidsByAuctionIds
+		{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "amount", Value: -1}}},
+		// FindBidByAuctionId paginado
+		{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "amount", Value: 1}, {Key: "_id", Value: 1}}},
+	}
+
+	_, err := database.Collection("bids").Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
+func createNotificationIndexes(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection("notifications").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "read", Value: 1}, {Key: "timestamp", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = database.Collection("watchlists").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "auction_id", Value: 1}}},
+	})
+	return err
+}
+
+// backfillAuctionCurrentPrice preenche current_price dos leilões criados antes do campo existir
+func backfillAuctionCurrentPrice(ctx context.Context, database *mongo.Database) error {
+	auctions := database.Collection("auctions")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$auction_id",
+			"highest": bson.M{"$max": "$amount"},
+		}}},
+	}
+
+	cursor, err := database.Collection("bids").Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var result struct {
+			AuctionId string  `bson:"_id"`
+			Highest   float64 `bson:"highest"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			return err
+		}
+
+		filter := bson.M{"_id": result.AuctionId, "current_price": bson.M{"$exists": false}}
+		update := bson.M{"$set": bson.M{"current_price": result.Highest}}
+		if _, err := auctions.UpdateOne(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	// Leilões sem lances começam com preço zero
+	_, err = auctions.UpdateMany(ctx,
+		bson.M{"current_price": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"current_price": 0}},
+	)
+	return err
+}
+
+// convertAmountsToCents converte valores gravados como double (reais) para centavos
+// inteiros. Só documentos do tipo double são alterados, então reexecutar é seguro.
+func convertAmountsToCents(ctx context.Context, database *mongo.Database) error {
+	toCents := func(field string) bson.M {
+		return bson.M{"$toLong": bson.M{"$round": bson.A{bson.M{"$multiply": bson.A{"$" + field, 100}}, 0}}}
+	}
+
+	_, err := database.Collection("bids").UpdateMany(ctx,
+		bson.M{"amount": bson.M{"$type": "double"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"amount":   toCents("amount"),
+			"currency": bson.M{"$ifNull": bson.A{"$currency", string(entity.DefaultCurrency)}},
+		}}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.Collection("auctions").UpdateMany(ctx,
+		bson.M{"current_price": bson.M{"$type": "double"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"current_price": toCents("current_price")}}}},
+	)
+	return err
+}
+
+// addAuctionCurrency marca os leilões existentes com a moeda padrão e indexa as cotações
+func addAuctionCurrency(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection("auctions").UpdateMany(ctx,
+		bson.M{"currency": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"currency": entity.DefaultCurrency}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.Collection("exchange_rates").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "from", Value: 1}, {Key: "to", Value: 1}, {Key: "effective_at", Value: -1}},
+	})
+	return err
+}
+
+// convertTimestampsToMillis converte horários gravados em segundos para milissegundos.
+// Valores abaixo de 1e11 só podem estar em segundos (1e11 ms é 1973), então reexecutar é seguro.
+func convertTimestampsToMillis(ctx context.Context, database *mongo.Database) error {
+	fields := map[string][]string{
+		"auctions":      {"timestamp", "expires_at"},
+		"bids":          {"timestamp"},
+		"notifications": {"timestamp"},
+		"watchlists":    {"timestamp"},
+	}
+
+	for collection, names := range fields {
+		for _, field := range names {
+			_, err := database.Collection(collection).UpdateMany(ctx,
+				bson.M{field: bson.M{"$lt": int64(1e11)}},
+				mongo.Pipeline{{{Key: "$set", Value: bson.M{field: bson.M{"$multiply": bson.A{"$" + field, 1000}}}}}},
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Desempate do lance vencedor: maior valor, depois o lance mais antigo
+	_, err := database.Collection("bids").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	return err
+}
+
+// createIdempotencyIndexes remove as respostas guardadas após expires_at
+func createIdempotencyIndexes(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection("idempotency_keys").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// createRateLimitIndexes remove os buckets que já voltaram a ficar cheios
+func createRateLimitIndexes(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection("rate_limits").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// createAuditLogIndexes impede dois registros com a mesma posição na cadeia de um stream
+func createAuditLogIndexes(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection("audit_log").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "stream", Value: 1}, {Key: "sequence", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// backfillAuctionEvents cria o índice de concorrência do event store e gera o stream dos
+// leilões gravados antes dele: Created e Started, um BidPlaced por lance em ordem de
+// horário e Closed para os encerrados (no horário de expiração). Leilões que já têm
+// eventos são ignorados, então reexecutar é seguro.
+func backfillAuctionEvents(ctx context.Context, database *mongo.Database) error {
+	events := database.Collection("auction_events")
+	_, err := events.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "auction_id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	imported, err := events.Distinct(ctx, "auction_id", bson.M{})
+	if err != nil {
+		return err
+	}
+	skip := make(map[string]bool, len(imported))
+	for _, id := range imported {
+		if id, ok := id.(string); ok {
+			skip[id] = true
+		}
+	}
+
+	cursor, err := database.Collection("auctions").Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var auction entity.AuctionEntityMongo
+		if err := cursor.Decode(&auction); err != nil {
+			return err
+		}
+		if skip[auction.Id] {
+			continue
+		}
+
+		stream, err := legacyAuctionStream(ctx, database, auction)
+		if err != nil {
+			return err
+		}
+		if _, err := events.InsertMany(ctx, stream); err != nil {
+			return err
+		}
+
+		_, err = database.Collection("auctions").UpdateOne(ctx,
+			bson.M{"_id": auction.Id},
+			bson.M{"$set": bson.M{"version": int64(len(stream))}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func legacyAuctionStream(ctx context.Context, database *mongo.Database, auction entity.AuctionEntityMongo) ([]any, error) {
+	var stream []any
+	add := func(eventType entity.AuctionEventType, occurredAt int64, data any) {
+		stream = append(stream, entity.AuctionEventEntityMongo{
+			Id:         uuid.New().String(),
+			AuctionId:  auction.Id,
+			Version:    int64(len(stream)) + 1,
+			Type:       eventType,
+			OccurredAt: occurredAt,
+			Data:       data,
+		})
+	}
+
+	add(entity.AuctionCreatedEvent, auction.Timestamp, entity.AuctionCreatedData{
+		ProductName: auction.ProductName,
+		Category:    auction.Category,
+		Description: auction.Description,
+		Condition:   auction.Condition,
+		Currency:    auction.Currency.OrDefault(),
+		SellerId:    auction.SellerId,
+		ExpiresAt:   time.UnixMilli(auction.ExpiresAt),
+	})
+	add(entity.AuctionStartedEvent, auction.Timestamp, entity.AuctionStartedData{})
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := database.Collection("bids").Find(ctx, bson.M{"auction_id": auction.Id}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var bids []entity.BidEntityMongo
+	if err := cursor.All(ctx, &bids); err != nil {
+		return nil, err
+	}
+
+	var winner *entity.BidEntityMongo
+	for i, bid := range bids {
+		add(entity.BidPlacedEvent, bid.Timestamp, entity.BidPlacedData{
+			BidId:    bid.Id,
+			UserId:   bid.UserId,
+			Amount:   bid.Amount,
+			Currency: bid.Currency.OrDefault(),
+		})
+		if winner == nil || bid.Amount > winner.Amount {
+			winner = &bids[i]
+		}
+	}
+
+	if auction.Status == entity.Completed {
+		data := entity.AuctionClosedData{}
+		if winner != nil {
+			data.WinningBidId = winner.Id
+		}
+		add(entity.AuctionClosedEvent, auction.ExpiresAt, data)
+	}
+
+	return stream, nil
+}
+
+// createSettlementIndexes atende a consulta das tentativas de um leilão e a busca de
+// pagamentos vencidos feita pelo agendador
+func createSettlementIndexes(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection("settlements").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "auction_id", Value: 1}, {Key: "attempt", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "payment_deadline", Value: 1}}},
+	})
+	return err
+}
+
+// createSettlementExpiryIndex atende a busca do agendador pelas tentativas expiradas cujo
+// estorno ou passagem ao próximo licitante ainda não terminou
+func createSettlementExpiryIndex(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection("settlements").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "expiry_pending", Value: 1}},
+		Options: options.Index().
+			SetName("expiry_pending_partial").
+			SetPartialFilterExpression(bson.M{"expiry_pending": true}),
+	})
+	return err
+}
+
+// createPendingWorkIndexes atende as buscas dos encerramentos cujos listeners falharam e das
+// tentativas de acerto cuja reserva ou aviso falhou; os índices parciais só guardam as
+// pendentes
+func createPendingWorkIndexes(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection("auctions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "closing_pending_at", Value: 1}},
+		Options: options.Index().
+			SetName("closing_pending_at_partial").
+			SetPartialFilterExpression(bson.M{"closing_pending_at": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = database.Collection("settlements").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().
+			SetName("offer_pending_partial").
+			SetPartialFilterExpression(bson.M{"offer_pending": true}),
+	})
+	return err
+}
+
+// createOfferIndexes atende a listagem das ofertas de um leilão e impede, com um índice único
+// parcial, que duas ofertas do mesmo leilão sejam aceitas
+func createOfferIndexes(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection("second_chance_offers").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{
+			Keys: bson.D{{Key: "auction_id", Value: 1}},
+			Options: options.Index().
+				SetName("auction_id_accepted_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": entity.OfferAccepted}),
+		},
+	})
+	return err
+}
+
+// createLedgerIndexes garante uma única transação por sequência de cada carteira, o que
+// serializa as transações concorrentes, e atende a leitura da reserva de um leilão, a busca
+// das carteiras com reserva num leilão encerrado e o extrato do usuário
+func createLedgerIndexes(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection("ledger_transactions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "wallet_id", Value: 1}, {Key: "sequence", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "wallet_id", Value: 1}, {Key: "auction_id", Value: 1}, {Key: "sequence", Value: -1}}},
+		{Keys: bson.D{{Key: "auction_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	return err
+}
+
+// createInvoiceIndexes atende a exportação das faturas por período de emissão e a busca das
+// faturas de um leilão
+func createInvoiceIndexes(ctx context.Context, database *mongo.Database) error {
+	_, err := database.Collection("invoices").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "issued_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "auction_id", Value: 1}}},
+	})
+	return err
+}
+
+// anchorAuditHeads grava a âncora (última sequência e hash) de cada stream do log de auditoria
+// e indexa os registros pendentes na ordem em que são encadeados. Âncoras que já estão adiante
+// não são alteradas, então reexecutar é seguro.
+func anchorAuditHeads(ctx context.Context, database *mongo.Database) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "stream", Value: 1}, {Key: "sequence", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$stream",
+			"sequence": bson.M{"$first": "$sequence"},
+			"hash":     bson.M{"$first": "$hash"},
+		}}},
+	}
+
+	cursor, err := database.Collection("audit_log").Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	heads := database.Collection("audit_heads")
+	for cursor.Next(ctx) {
+		var head entity.AuditHeadEntityMongo
+		if err := cursor.Decode(&head); err != nil {
+			return err
+		}
+
+		filter := bson.M{"_id": head.Stream, "sequence": bson.M{"$lt": head.Sequence}}
+		update := bson.M{"$set": bson.M{"sequence": head.Sequence, "hash": head.Hash, "updated_at": time.Now().UnixMilli()}}
+		_, err := heads.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	_, err = database.Collection("audit_pending").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	return err
+}
@@ -0,0 +1,15 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationVersionsAreUniqueAndSequential(t *testing.T) {
+	for i, migration := range Migrations {
+		assert.Equal(t, i+1, migration.Version, "migration %q is out of sequence", migration.Description)
+		assert.NotEmpty(t, migration.Description)
+		assert.NotNil(t, migration.Up)
+	}
+}
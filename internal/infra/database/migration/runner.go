@@ -0,0 +1,276 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	lockId        = "schema_migrations"
+	lockTTL       = 5 * time.Minute
+	lockRenewStep = lockTTL / 3
+	lockWaitStep  = 500 * time.Millisecond
+)
+
+// errLockLost interrompe as migrations quando outra réplica assumiu o lock
+var errLockLost = errors.New("migration lock lost")
+
+// Migration é uma alteração versionada do banco (índices ou dados). Up deve ser
+// idempotente, pois pode ser reexecutada caso o processo caia antes do registro.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, database *mongo.Database) error
+}
+
+// MigrationStatus informa se uma migration já foi aplicada
+type MigrationStatus struct {
+	Version     int
+	Description string
+	AppliedAt   *time.Time
+}
+
+type appliedMigrationMongo struct {
+	Version     int    `bson:"_id"`
+	Description string `bson:"description"`
+	AppliedAt   int64  `bson:"applied_at"`
+}
+
+// Runner aplica as migrations pendentes registrando cada uma em schema_migrations.
+// Um lock no banco impede que várias réplicas migrem ao mesmo tempo.
+type Runner struct {
+	database       *mongo.Database
+	collection     *mongo.Collection
+	lockCollection *mongo.Collection
+	migrations     []Migration
+	owner          string
+	logger         *slog.Logger
+}
+
+func NewRunner(database *mongo.Database, migrations []Migration, logger *slog.Logger) *Runner {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	hostname, _ := os.Hostname()
+
+	return &Runner{
+		database:       database,
+		collection:     database.Collection("schema_migrations"),
+		lockCollection: database.Collection("schema_migrations_lock"),
+		migrations:     sorted,
+		owner:          hostname + "-" + uuid.New().String(),
+		logger:         logger,
+	}
+}
+
+// Up aplica, em ordem, todas as migrations ainda não registradas e retorna as que foram aplicadas.
+// O lock é renovado enquanto as migrations rodam; se ele for perdido, Up para com erro.
+func (r *Runner) Up(ctx context.Context) ([]Migration, error) {
+	expiresAt, err := r.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.releaseLock()
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go r.renewLock(ctx, cancel, expiresAt)
+
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var executed []Migration
+	for _, migration := range r.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		r.logger.InfoContext(ctx, "Applying migration",
+			slog.Int("version", migration.Version), slog.String("description", migration.Description))
+
+		if err := migration.Up(ctx, r.database); err != nil {
+			if cause := context.Cause(ctx); errors.Is(cause, errLockLost) {
+				err = cause
+			}
+			return executed, fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+		}
+
+		// Sem o lock, outra réplica pode estar aplicando a mesma migration
+		if cause := context.Cause(ctx); cause != nil {
+			return executed, fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, cause)
+		}
+
+		_, err := r.collection.InsertOne(ctx, appliedMigrationMongo{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now().Unix(),
+		})
+		if err != nil {
+			return executed, err
+		}
+
+		executed = append(executed, migration)
+	}
+
+	return executed, nil
+}
+
+// Status lista todas as migrations conhecidas e quando cada uma foi aplicada
+func (r *Runner) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var status []MigrationStatus
+	for _, migration := range r.migrations {
+		item := MigrationStatus{
+			Version:     migration.Version,
+			Description: migration.Description,
+		}
+
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := time.Unix(record.AppliedAt, 0)
+			item.AppliedAt = &appliedAt
+		}
+
+		status = append(status, item)
+	}
+
+	return status, nil
+}
+
+// Pending retorna quantas migrations ainda não foram aplicadas
+func (r *Runner) Pending(ctx context.Context) (int, error) {
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, migration := range r.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+func (r *Runner) appliedVersions(ctx context.Context) (map[int]appliedMigrationMongo, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []appliedMigrationMongo
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]appliedMigrationMongo, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+// acquireLock espera até obter o lock de migração ou o contexto expirar e retorna quando ele
+// expira. O lock expira sozinho após lockTTL para não travar o deploy se uma réplica morrer.
+func (r *Runner) acquireLock(ctx context.Context) (time.Time, error) {
+	for {
+		now := time.Now()
+		expiresAt := now.Add(lockTTL)
+		filter := bson.M{
+			"_id": lockId,
+			"$or": bson.A{
+				bson.M{"expires_at": bson.M{"$lt": now.Unix()}},
+				bson.M{"owner": r.owner},
+			},
+		}
+		update := bson.M{
+			"$set": bson.M{
+				"owner":      r.owner,
+				"expires_at": expiresAt.Unix(),
+			},
+		}
+
+		_, err := r.lockCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		if err == nil {
+			return expiresAt, nil
+		}
+
+		// Outra réplica detém o lock: o upsert colide com o _id existente
+		if !mongo.IsDuplicateKeyError(err) {
+			return time.Time{}, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return time.Time{}, fmt.Errorf("timed out waiting for migration lock: %w", ctx.Err())
+		case <-time.After(lockWaitStep):
+		}
+	}
+}
+
+// renewLock estende o lock a cada lockRenewStep enquanto ctx estiver ativo. Se outra réplica
+// tiver assumido o lock, ou se ele expirar sem conseguir renovar, cancela ctx com errLockLost.
+func (r *Runner) renewLock(ctx context.Context, cancel context.CancelCauseFunc, expiresAt time.Time) {
+	ticker := time.NewTicker(lockRenewStep)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		renewed := now.Add(lockTTL)
+		result, err := r.lockCollection.UpdateOne(ctx,
+			bson.M{"_id": lockId, "owner": r.owner},
+			bson.M{"$set": bson.M{"expires_at": renewed.Unix()}},
+		)
+		switch {
+		case err == nil && result.MatchedCount == 0:
+			r.logger.Error("Migration lock taken by another replica")
+			cancel(errLockLost)
+			return
+		case err == nil:
+			expiresAt = renewed
+		case ctx.Err() != nil:
+			return
+		case now.After(expiresAt):
+			r.logger.Error("Migration lock expired before it could be renewed", logging.Err(err))
+			cancel(errLockLost)
+			return
+		default:
+			r.logger.Warn("Error renewing migration lock", logging.Err(err))
+		}
+	}
+}
+
+func (r *Runner) releaseLock() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := r.lockCollection.DeleteOne(ctx, bson.M{"_id": lockId, "owner": r.owner}); err != nil {
+		r.logger.Error("Error releasing migration lock", logging.Err(err))
+	}
+}
@@ -0,0 +1,142 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationRepository struct {
+	Collection         *mongo.Collection
+	DeliveryCollection *mongo.Collection
+	logger             *slog.Logger
+}
+
+func NewNotificationRepository(database *mongo.Database, logger *slog.Logger) *NotificationRepository {
+	return &NotificationRepository{
+		Collection:         database.Collection("notifications"),
+		DeliveryCollection: database.Collection("notification_deliveries"),
+		logger:             logger,
+	}
+}
+
+// CreateNotification grava a notificação na caixa de entrada do usuário
+func (nr *NotificationRepository) CreateNotification(ctx context.Context, notification *entity.Notification) error {
+	ctx, done := tracing.Repository(ctx, "notification", "CreateNotification")
+	defer done()
+
+	notificationEntityMongo := &entity.NotificationEntityMongo{
+		Id:        notification.Id,
+		UserId:    notification.UserId,
+		AuctionId: notification.AuctionId,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Message:   notification.Message,
+		Read:      notification.Read,
+		Timestamp: notification.Timestamp.UnixMilli(),
+	}
+
+	_, err := nr.Collection.InsertOne(ctx, notificationEntityMongo)
+	if err != nil {
+		nr.logger.ErrorContext(ctx, "Error creating notification",
+			slog.String("notification_id", notification.Id), logging.UserId(notification.UserId), logging.AuctionId(notification.AuctionId), logging.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+// FindNotificationsByUserId busca as notificações de um usuário, das mais recentes para as mais antigas
+func (nr *NotificationRepository) FindNotificationsByUserId(ctx context.Context, userId string, onlyUnread bool) ([]entity.Notification, error) {
+	ctx, done := tracing.Repository(ctx, "notification", "FindNotificationsByUserId")
+	defer done()
+
+	filter := bson.M{"user_id": userId}
+	if onlyUnread {
+		filter["read"] = false
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+
+	cursor, err := nr.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var notificationEntitiesMongo []entity.NotificationEntityMongo
+	if err := cursor.All(ctx, &notificationEntitiesMongo); err != nil {
+		return nil, err
+	}
+
+	var notifications []entity.Notification
+	for _, notificationMongo := range notificationEntitiesMongo {
+		notifications = append(notifications, entity.Notification{
+			Id:        notificationMongo.Id,
+			UserId:    notificationMongo.UserId,
+			AuctionId: notificationMongo.AuctionId,
+			Type:      notificationMongo.Type,
+			Title:     notificationMongo.Title,
+			Message:   notificationMongo.Message,
+			Read:      notificationMongo.Read,
+			Timestamp: time.UnixMilli(notificationMongo.Timestamp),
+		})
+	}
+
+	return notifications, nil
+}
+
+// MarkNotificationsAsRead marca como lidas as notificações informadas, ou todas do usuário se ids estiver vazio
+func (nr *NotificationRepository) MarkNotificationsAsRead(ctx context.Context, userId string, ids []string) error {
+	ctx, done := tracing.Repository(ctx, "notification", "MarkNotificationsAsRead")
+	defer done()
+
+	filter := bson.M{"user_id": userId, "read": false}
+	if len(ids) > 0 {
+		filter["_id"] = bson.M{"$in": ids}
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"read": true,
+		},
+	}
+
+	_, err := nr.Collection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// RegisterDelivery registra a chave de deduplicação; retorna false se ela já existia
+func (nr *NotificationRepository) RegisterDelivery(ctx context.Context, dedupKey string) (bool, error) {
+	ctx, done := tracing.Repository(ctx, "notification", "RegisterDelivery")
+	defer done()
+
+	_, err := nr.DeliveryCollection.InsertOne(ctx, bson.M{
+		"_id":       dedupKey,
+		"timestamp": time.Now().UnixMilli(),
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseDelivery apaga a chave de deduplicação de uma entrega que falhou
+func (nr *NotificationRepository) ReleaseDelivery(ctx context.Context, dedupKey string) error {
+	ctx, done := tracing.Repository(ctx, "notification", "ReleaseDelivery")
+	defer done()
+
+	_, err := nr.DeliveryCollection.DeleteOne(ctx, bson.M{"_id": dedupKey})
+	return err
+}
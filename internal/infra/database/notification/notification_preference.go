@@ -0,0 +1,66 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationPreferenceRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewNotificationPreferenceRepository(database *mongo.Database) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		Collection: database.Collection("notification_preferences"),
+	}
+}
+
+// FindPreferenceByUserId busca as preferências do usuário; retorna nil se ele nunca as configurou
+func (pr *NotificationPreferenceRepository) FindPreferenceByUserId(ctx context.Context, userId string) (*entity.NotificationPreference, error) {
+	ctx, done := tracing.Repository(ctx, "notification_preference", "FindPreferenceByUserId")
+	defer done()
+
+	filter := bson.M{"_id": userId}
+
+	var preferenceEntityMongo entity.NotificationPreferenceEntityMongo
+	err := pr.Collection.FindOne(ctx, filter).Decode(&preferenceEntityMongo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &entity.NotificationPreference{
+		UserId:          preferenceEntityMongo.UserId,
+		Locale:          preferenceEntityMongo.Locale,
+		Email:           preferenceEntityMongo.Email,
+		Channels:        preferenceEntityMongo.Channels,
+		MutedTypes:      preferenceEntityMongo.MutedTypes,
+		ReminderMinutes: preferenceEntityMongo.ReminderMinutes,
+	}, nil
+}
+
+// UpsertPreference cria ou substitui as preferências do usuário
+func (pr *NotificationPreferenceRepository) UpsertPreference(ctx context.Context, preference *entity.NotificationPreference) error {
+	ctx, done := tracing.Repository(ctx, "notification_preference", "UpsertPreference")
+	defer done()
+
+	preferenceEntityMongo := &entity.NotificationPreferenceEntityMongo{
+		UserId:          preference.UserId,
+		Locale:          preference.Locale,
+		Email:           preference.Email,
+		Channels:        preference.Channels,
+		MutedTypes:      preference.MutedTypes,
+		ReminderMinutes: preference.ReminderMinutes,
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	_, err := pr.Collection.ReplaceOne(ctx, bson.M{"_id": preference.UserId}, preferenceEntityMongo, opts)
+	return err
+}
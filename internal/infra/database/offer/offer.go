@@ -0,0 +1,182 @@
+package offer
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OfferRepository guarda as ofertas de segunda chance. O índice único parcial em auction_id
+// das ofertas aceitas garante que só uma oferta seja aceita por leilão.
+type OfferRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewOfferRepository(database *mongo.Database) *OfferRepository {
+	return &OfferRepository{
+		Collection: database.Collection("second_chance_offers"),
+	}
+}
+
+func (or *OfferRepository) CreateOffers(ctx context.Context, offers []entity.SecondChanceOffer) error {
+	ctx, done := tracing.Repository(ctx, "offer", "CreateOffers")
+	defer done()
+
+	if len(offers) == 0 {
+		return nil
+	}
+
+	documents := make([]any, 0, len(offers))
+	for _, offer := range offers {
+		documents = append(documents, entity.SecondChanceOfferEntityMongo{
+			Id:        offer.Id,
+			AuctionId: offer.AuctionId,
+			SellerId:  offer.SellerId,
+			UserId:    offer.UserId,
+			BidId:     offer.BidId,
+			Amount:    offer.Amount.Cents,
+			Currency:  offer.Amount.Currency,
+			Status:    offer.Status,
+			ExpiresAt: offer.ExpiresAt.UnixMilli(),
+			CreatedAt: offer.CreatedAt.UnixMilli(),
+		})
+	}
+
+	_, err := or.Collection.InsertMany(ctx, documents)
+	return err
+}
+
+// FindOffersByAuctionId busca as ofertas do leilão, das mais recentes para as mais antigas
+func (or *OfferRepository) FindOffersByAuctionId(ctx context.Context, auctionId string) ([]entity.SecondChanceOffer, error) {
+	ctx, done := tracing.Repository(ctx, "offer", "FindOffersByAuctionId")
+	defer done()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}})
+	cursor, err := or.Collection.Find(ctx, bson.M{"auction_id": auctionId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var offerEntitiesMongo []entity.SecondChanceOfferEntityMongo
+	if err := cursor.All(ctx, &offerEntitiesMongo); err != nil {
+		return nil, err
+	}
+
+	var offers []entity.SecondChanceOffer
+	for _, offerMongo := range offerEntitiesMongo {
+		offers = append(offers, toSecondChanceOffer(offerMongo))
+	}
+	return offers, nil
+}
+
+func (or *OfferRepository) FindOfferById(ctx context.Context, offerId string) (*entity.SecondChanceOffer, error) {
+	ctx, done := tracing.Repository(ctx, "offer", "FindOfferById")
+	defer done()
+
+	var offerMongo entity.SecondChanceOfferEntityMongo
+	err := or.Collection.FindOne(ctx, bson.M{"_id": offerId}).Decode(&offerMongo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	offer := toSecondChanceOffer(offerMongo)
+	return &offer, nil
+}
+
+// AcceptOffer aceita a oferta se ela ainda estiver pendente e no prazo. Duas aceitações
+// simultâneas no mesmo leilão são resolvidas pelo índice único parcial.
+func (or *OfferRepository) AcceptOffer(ctx context.Context, offerId string, at time.Time) error {
+	ctx, done := tracing.Repository(ctx, "offer", "AcceptOffer")
+	defer done()
+
+	filter := bson.M{
+		"_id":        offerId,
+		"status":     entity.OfferPending,
+		"expires_at": bson.M{"$gte": at.UnixMilli()},
+	}
+	update := bson.M{"$set": bson.M{"status": entity.OfferAccepted, "responded_at": at.UnixMilli()}}
+
+	result, err := or.Collection.UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return entity.ErrOfferAlreadyAccepted
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return entity.ErrOfferNotPending
+	}
+	return nil
+}
+
+func (or *OfferRepository) DeclineOffer(ctx context.Context, offerId string, at time.Time) error {
+	ctx, done := tracing.Repository(ctx, "offer", "DeclineOffer")
+	defer done()
+
+	filter := bson.M{
+		"_id":        offerId,
+		"status":     entity.OfferPending,
+		"expires_at": bson.M{"$gte": at.UnixMilli()},
+	}
+	update := bson.M{"$set": bson.M{"status": entity.OfferDeclined, "responded_at": at.UnixMilli()}}
+
+	result, err := or.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return entity.ErrOfferNotPending
+	}
+	return nil
+}
+
+// ReopenOffer volta a oferta aceita para pendente, liberando o índice de aceite único do
+// leilão; o prazo original continua valendo
+func (or *OfferRepository) ReopenOffer(ctx context.Context, offerId string) error {
+	ctx, done := tracing.Repository(ctx, "offer", "ReopenOffer")
+	defer done()
+
+	filter := bson.M{"_id": offerId, "status": entity.OfferAccepted}
+	update := bson.M{"$set": bson.M{"status": entity.OfferPending, "responded_at": int64(0)}}
+
+	_, err := or.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (or *OfferRepository) WithdrawPendingOffers(ctx context.Context, auctionId string, at time.Time) error {
+	ctx, done := tracing.Repository(ctx, "offer", "WithdrawPendingOffers")
+	defer done()
+
+	filter := bson.M{"auction_id": auctionId, "status": entity.OfferPending}
+	update := bson.M{"$set": bson.M{"status": entity.OfferWithdrawn, "responded_at": at.UnixMilli()}}
+
+	_, err := or.Collection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+func toSecondChanceOffer(offerMongo entity.SecondChanceOfferEntityMongo) entity.SecondChanceOffer {
+	offer := entity.SecondChanceOffer{
+		Id:        offerMongo.Id,
+		AuctionId: offerMongo.AuctionId,
+		SellerId:  offerMongo.SellerId,
+		UserId:    offerMongo.UserId,
+		BidId:     offerMongo.BidId,
+		Amount:    entity.NewMoney(offerMongo.Amount, offerMongo.Currency.OrDefault()),
+		Status:    offerMongo.Status,
+		ExpiresAt: time.UnixMilli(offerMongo.ExpiresAt),
+		CreatedAt: time.UnixMilli(offerMongo.CreatedAt),
+	}
+	if offerMongo.RespondedAt > 0 {
+		offer.RespondedAt = time.UnixMilli(offerMongo.RespondedAt)
+	}
+	return offer
+}
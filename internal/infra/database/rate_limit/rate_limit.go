@@ -0,0 +1,69 @@
+package rate_limit
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RateLimitRepository guarda os buckets no MongoDB para que todas as réplicas
+// compartilhem os mesmos limites
+type RateLimitRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewRateLimitRepository(database *mongo.Database) *RateLimitRepository {
+	return &RateLimitRepository{
+		Collection: database.Collection("rate_limits"),
+	}
+}
+
+// Take reabastece e consome o token em uma única atualização atômica no servidor,
+// criando o bucket cheio se ele ainda não existir
+func (rr *RateLimitRepository) Take(ctx context.Context, key string, policy entity.RateLimitPolicy) (entity.RateLimitResult, error) {
+	ctx, done := tracing.Repository(ctx, "rate_limit", "Take")
+	defer done()
+
+	now := time.Now()
+	nowMillis := now.UnixMilli()
+	capacity := float64(policy.Capacity)
+	refillMillis := float64(policy.RefillEvery) / float64(time.Millisecond)
+
+	elapsed := bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{nowMillis, bson.M{"$ifNull": bson.A{"$updated_at", nowMillis}}}}}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"tokens": bson.M{"$min": bson.A{
+				capacity,
+				bson.M{"$add": bson.A{
+					bson.M{"$ifNull": bson.A{"$tokens", capacity}},
+					bson.M{"$divide": bson.A{elapsed, refillMillis}},
+				}},
+			}},
+			"updated_at": bson.M{"$max": bson.A{nowMillis, bson.M{"$ifNull": bson.A{"$updated_at", nowMillis}}}},
+		}}},
+		{{Key: "$set", Value: bson.M{"allowed": bson.M{"$gte": bson.A{"$tokens", 1}}}}},
+		{{Key: "$set", Value: bson.M{
+			"tokens":     bson.M{"$cond": bson.A{"$allowed", bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+			"expires_at": now.Add(policy.FullAfter()),
+		}}},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var bucket entity.RateLimitBucketEntityMongo
+	err := rr.Collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&bucket)
+	if mongo.IsDuplicateKeyError(err) {
+		// Duas réplicas criaram o bucket ao mesmo tempo; a segunda tentativa atualiza o existente
+		err = rr.Collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&bucket)
+	}
+	if err != nil {
+		return entity.RateLimitResult{}, err
+	}
+
+	return entity.NewRateLimitResult(policy, bucket.Tokens, bucket.Allowed), nil
+}
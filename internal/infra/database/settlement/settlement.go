@@ -0,0 +1,162 @@
+package settlement
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SettlementRepository guarda as tentativas de acerto dos leilões encerrados. O _id é
+// "<auction_id>:<attempt>", então cada tentativa é gravada uma única vez.
+type SettlementRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewSettlementRepository(database *mongo.Database) *SettlementRepository {
+	return &SettlementRepository{
+		Collection: database.Collection("settlements"),
+	}
+}
+
+// CreateSettlement grava a tentativa; retorna false se ela já tinha sido criada
+func (sr *SettlementRepository) CreateSettlement(ctx context.Context, settlement *entity.Settlement) (bool, error) {
+	ctx, done := tracing.Repository(ctx, "settlement", "CreateSettlement")
+	defer done()
+
+	_, err := sr.Collection.InsertOne(ctx, toSettlementEntityMongo(settlement))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindSettlementsByAuctionId busca as tentativas do leilão em ordem; a última é a atual
+func (sr *SettlementRepository) FindSettlementsByAuctionId(ctx context.Context, auctionId string) ([]entity.Settlement, error) {
+	ctx, done := tracing.Repository(ctx, "settlement", "FindSettlementsByAuctionId")
+	defer done()
+
+	opts := options.Find().SetSort(bson.D{{Key: "attempt", Value: 1}})
+	return sr.findSettlements(ctx, bson.M{"auction_id": auctionId}, opts)
+}
+
+// UpdateSettlement grava o novo estado somente se a tentativa ainda estiver em
+// expectedStatus, para que duas ações simultâneas não avancem a mesma etapa
+func (sr *SettlementRepository) UpdateSettlement(ctx context.Context, settlement *entity.Settlement, expectedStatus entity.SettlementStatus) (bool, error) {
+	ctx, done := tracing.Repository(ctx, "settlement", "UpdateSettlement")
+	defer done()
+
+	filter := bson.M{"_id": settlement.Id, "status": expectedStatus}
+	update := bson.M{"$set": bson.M{
+		"status":         settlement.Status,
+		"tracking_code":  settlement.TrackingCode,
+		"dispute_reason": settlement.DisputeReason,
+		"disputed_by":    settlement.DisputedBy,
+		"offer_pending":  settlement.OfferPending,
+		"expiry_pending": settlement.ExpiryPending,
+		"updated_at":     settlement.UpdatedAt.UnixMilli(),
+	}}
+
+	result, err := sr.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// FindOverdueSettlements busca as tentativas aguardando pagamento, ou paradas numa captura,
+// com o prazo vencido
+func (sr *SettlementRepository) FindOverdueSettlements(ctx context.Context, now time.Time) ([]entity.Settlement, error) {
+	ctx, done := tracing.Repository(ctx, "settlement", "FindOverdueSettlements")
+	defer done()
+
+	filter := bson.M{
+		"status":           bson.M{"$in": bson.A{entity.SettlementAwaitingPayment, entity.SettlementCapturing}},
+		"payment_deadline": bson.M{"$lt": now.UnixMilli()},
+	}
+	return sr.findSettlements(ctx, filter)
+}
+
+// FindPendingExpiries busca as tentativas expiradas cujo estorno ou passagem ao próximo
+// licitante falhou, para que o agendador as retome
+func (sr *SettlementRepository) FindPendingExpiries(ctx context.Context) ([]entity.Settlement, error) {
+	ctx, done := tracing.Repository(ctx, "settlement", "FindPendingExpiries")
+	defer done()
+
+	return sr.findSettlements(ctx, bson.M{"status": entity.SettlementExpired, "expiry_pending": true})
+}
+
+// FindPendingOffers busca as tentativas cuja reserva ou aviso aos listeners falhou. As abertas
+// depois de createdBefore ainda podem estar sendo avisadas e ficam para a próxima execução.
+func (sr *SettlementRepository) FindPendingOffers(ctx context.Context, createdBefore time.Time) ([]entity.Settlement, error) {
+	ctx, done := tracing.Repository(ctx, "settlement", "FindPendingOffers")
+	defer done()
+
+	return sr.findSettlements(ctx, bson.M{"offer_pending": true, "created_at": bson.M{"$lte": createdBefore.UnixMilli()}})
+}
+
+func (sr *SettlementRepository) findSettlements(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]entity.Settlement, error) {
+	cursor, err := sr.Collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var settlementEntitiesMongo []entity.SettlementEntityMongo
+	if err := cursor.All(ctx, &settlementEntitiesMongo); err != nil {
+		return nil, err
+	}
+
+	var settlements []entity.Settlement
+	for _, settlementMongo := range settlementEntitiesMongo {
+		settlements = append(settlements, entity.Settlement{
+			Id:              settlementMongo.Id,
+			AuctionId:       settlementMongo.AuctionId,
+			Attempt:         settlementMongo.Attempt,
+			SellerId:        settlementMongo.SellerId,
+			WinnerId:        settlementMongo.WinnerId,
+			BidId:           settlementMongo.BidId,
+			Amount:          entity.NewMoney(settlementMongo.Amount, settlementMongo.Currency.OrDefault()),
+			Status:          settlementMongo.Status,
+			PaymentDeadline: time.UnixMilli(settlementMongo.PaymentDeadline),
+			TrackingCode:    settlementMongo.TrackingCode,
+			DisputeReason:   settlementMongo.DisputeReason,
+			DisputedBy:      settlementMongo.DisputedBy,
+			OfferPending:    settlementMongo.OfferPending,
+			ExpiryPending:   settlementMongo.ExpiryPending,
+			CreatedAt:       time.UnixMilli(settlementMongo.CreatedAt),
+			UpdatedAt:       time.UnixMilli(settlementMongo.UpdatedAt),
+		})
+	}
+
+	return settlements, nil
+}
+
+func toSettlementEntityMongo(settlement *entity.Settlement) *entity.SettlementEntityMongo {
+	return &entity.SettlementEntityMongo{
+		Id:              settlement.Id,
+		AuctionId:       settlement.AuctionId,
+		Attempt:         settlement.Attempt,
+		SellerId:        settlement.SellerId,
+		WinnerId:        settlement.WinnerId,
+		BidId:           settlement.BidId,
+		Amount:          settlement.Amount.Cents,
+		Currency:        settlement.Amount.Currency,
+		Status:          settlement.Status,
+		PaymentDeadline: settlement.PaymentDeadline.UnixMilli(),
+		TrackingCode:    settlement.TrackingCode,
+		DisputeReason:   settlement.DisputeReason,
+		DisputedBy:      settlement.DisputedBy,
+		OfferPending:    settlement.OfferPending,
+		ExpiryPending:   settlement.ExpiryPending,
+		CreatedAt:       settlement.CreatedAt.UnixMilli(),
+		UpdatedAt:       settlement.UpdatedAt.UnixMilli(),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -19,6 +20,9 @@ func NewUserRepository(database *mongo.Database) *UserRepository {
 }
 
 func (ur *UserRepository) FindUserById(ctx context.Context, id string) (*entity.User, error) {
+	ctx, done := tracing.Repository(ctx, "user", "FindUserById")
+	defer done()
+
 	filter := bson.M{"_id": id}
 
 	var userEntityMongo entity.UserEntityMongo
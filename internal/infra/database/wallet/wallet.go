@@ -0,0 +1,408 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxWalletAttempts limita as releituras da carteira quando outra transação grava primeiro
+const maxWalletAttempts = 5
+
+// WalletRepository guarda o livro-razão das carteiras na coleção ledger_transactions. Cada
+// transação é um documento com os lançamentos e os saldos resultantes, numerado pela
+// sequência da carteira; o índice único em (wallet_id, sequence) serializa as transações
+// de uma carteira, e o _id determinístico das operações ligadas a lances faz com que uma
+// operação repetida não seja aplicada duas vezes.
+type WalletRepository struct {
+	Collection *mongo.Collection
+	logger     *slog.Logger
+}
+
+func NewWalletRepository(database *mongo.Database, logger *slog.Logger) *WalletRepository {
+	return &WalletRepository{
+		Collection: database.Collection("ledger_transactions"),
+		logger:     logger,
+	}
+}
+
+// Deposit credita o valor no saldo disponível do usuário na moeda do valor
+func (wr *WalletRepository) Deposit(ctx context.Context, userId string, amount entity.Money) (*entity.LedgerTransaction, error) {
+	ctx, done := tracing.Repository(ctx, "wallet", "Deposit")
+	defer done()
+
+	return wr.apply(ctx, uuid.New().String(), userId, amount.Currency, "", func(wallet entity.Wallet, _ entity.Hold) (*entity.LedgerTransaction, error) {
+		return wallet.Deposit(amount, time.Now())
+	})
+}
+
+// HoldBid reserva o valor do lance. O lance já deve ter a moeda do leilão.
+func (wr *WalletRepository) HoldBid(ctx context.Context, bid entity.Bid) error {
+	ctx, done := tracing.Repository(ctx, "wallet", "HoldBid")
+	defer done()
+
+	_, err := wr.apply(ctx, "hold:"+bid.Id, bid.UserId, bid.Amount.Currency, bid.AuctionId, func(wallet entity.Wallet, current entity.Hold) (*entity.LedgerTransaction, error) {
+		return wallet.HoldBid(bid, current, time.Now())
+	})
+	return err
+}
+
+// RevertBidHold volta a reserva do usuário no leilão para a que existia antes do lance
+func (wr *WalletRepository) RevertBidHold(ctx context.Context, bid entity.Bid) error {
+	ctx, done := tracing.Repository(ctx, "wallet", "RevertBidHold")
+	defer done()
+
+	hold, err := wr.findTransaction(ctx, "hold:"+bid.Id)
+	if err != nil || hold == nil {
+		return err
+	}
+
+	_, err = wr.apply(ctx, "revert:"+bid.Id, bid.UserId, hold.Currency, bid.AuctionId, func(wallet entity.Wallet, current entity.Hold) (*entity.LedgerTransaction, error) {
+		return wallet.RestoreHold(bid.AuctionId, bid.Id, current, hold.PreviousHold, time.Now()), nil
+	})
+	return err
+}
+
+// OnBidPlaced é registrado como BidPlacedListener: libera a reserva de quem foi superado.
+// Quando o usuário cobre o próprio lance, a reserva já passou para o lance novo.
+func (wr *WalletRepository) OnBidPlaced(ctx context.Context, bid entity.Bid, previousHighest *entity.Bid) {
+	if previousHighest == nil || previousHighest.UserId == bid.UserId {
+		return
+	}
+
+	ctx, done := tracing.Repository(ctx, "wallet", "ReleaseOutbidHold")
+	defer done()
+
+	outbid := *previousHighest
+	_, err := wr.apply(ctx, "release:"+outbid.Id, outbid.UserId, outbid.Amount.Currency, outbid.AuctionId, func(wallet entity.Wallet, current entity.Hold) (*entity.LedgerTransaction, error) {
+		return wallet.ReleaseHold(outbid.AuctionId, outbid.Id, current, time.Now()), nil
+	})
+	if err != nil {
+		wr.logger.ErrorContext(ctx, "Error releasing outbid hold",
+			logging.AuctionId(outbid.AuctionId), logging.BidId(outbid.Id), logging.UserId(outbid.UserId), logging.Err(err))
+	}
+}
+
+// OnAuctionClosed é registrado como AuctionClosedListener: libera as reservas que ainda
+// restarem dos demais licitantes. A reserva do vencedor, a que cobre o lance vencedor,
+// continua até o acerto: é capturada no pagamento e liberada se a tentativa expirar. Sem
+// lance vencedor, como com o preço de reserva não alcançado, todas as reservas são liberadas.
+// As liberações já feitas não se repetem quando o encerramento é retomado.
+func (wr *WalletRepository) OnAuctionClosed(ctx context.Context, auction entity.Auction) error {
+	ctx, done := tracing.Repository(ctx, "wallet", "SettleAuctionHolds")
+	defer done()
+
+	if err := wr.releaseAuctionHolds(ctx, "close:", auction, auction.WinningBidId); err != nil {
+		return fmt.Errorf("settling auction holds: %w", err)
+	}
+	return nil
+}
+
+// OnAuctionStatusChanged é registrado como AuctionStatusChangedListener: o leilão cancelado
+// não tem vencedor, então todas as reservas dele são liberadas
+func (wr *WalletRepository) OnAuctionStatusChanged(ctx context.Context, before, after entity.Auction) {
+	if after.Status != entity.Cancelled {
+		return
+	}
+
+	ctx, done := tracing.Repository(ctx, "wallet", "ReleaseCancelledAuctionHolds")
+	defer done()
+
+	if err := wr.releaseAuctionHolds(ctx, "cancel:", after, ""); err != nil {
+		wr.logger.ErrorContext(ctx, "Error releasing cancelled auction holds", logging.AuctionId(after.Id), logging.Err(err))
+	}
+}
+
+// releaseAuctionHolds libera as reservas de todos os licitantes do leilão, menos a que cobre
+// keepBidId; o prefixo identifica a operação nos ids das transações
+func (wr *WalletRepository) releaseAuctionHolds(ctx context.Context, prefix string, auction entity.Auction, keepBidId string) error {
+	userIds, err := wr.Collection.Distinct(ctx, "user_id", bson.M{"auction_id": auction.Id})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, value := range userIds {
+		userId, _ := value.(string)
+		_, err := wr.apply(ctx, prefix+auction.Id+":"+userId, userId, auction.Currency, auction.Id, func(wallet entity.Wallet, current entity.Hold) (*entity.LedgerTransaction, error) {
+			if keepBidId != "" && current.BidId == keepBidId {
+				return nil, nil
+			}
+			return wallet.ReleaseHold(auction.Id, "", current, time.Now()), nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", userId, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HoldSettlement reserva o valor da tentativa de acerto para o comprador dela. Quem recebe
+// uma nova tentativa já teve a reserva liberada ao ser superado, então o valor volta a sair
+// do saldo disponível.
+func (wr *WalletRepository) HoldSettlement(ctx context.Context, settlement entity.Settlement) error {
+	ctx, done := tracing.Repository(ctx, "wallet", "HoldSettlement")
+	defer done()
+
+	return wr.holdSettlement(ctx, settlement)
+}
+
+// CaptureSettlement captura o pagamento da tentativa: garante a reserva e a move para a
+// conta de valores capturados. Repetir a captura da mesma tentativa não tem efeito.
+func (wr *WalletRepository) CaptureSettlement(ctx context.Context, settlement entity.Settlement) error {
+	ctx, done := tracing.Repository(ctx, "wallet", "CaptureSettlement")
+	defer done()
+
+	if err := wr.holdSettlement(ctx, settlement); err != nil {
+		return err
+	}
+
+	target := entity.Hold{BidId: settlement.BidId, Amount: settlement.Amount.Cents}
+	_, err := wr.apply(ctx, "capture:"+settlement.Id, settlement.WinnerId, settlement.Amount.Currency, settlement.AuctionId, func(wallet entity.Wallet, current entity.Hold) (*entity.LedgerTransaction, error) {
+		// Outra operação mudou a reserva depois de holdSettlement
+		if current != target {
+			return nil, entity.ErrWalletConflict
+		}
+		return wallet.CaptureHold(settlement.AuctionId, current, time.Now()), nil
+	})
+	return err
+}
+
+// RefundSettlement libera a reserva da tentativa e, se o pagamento chegou a ser capturado,
+// devolve o valor ao saldo disponível do comprador
+func (wr *WalletRepository) RefundSettlement(ctx context.Context, settlement entity.Settlement) error {
+	ctx, done := tracing.Repository(ctx, "wallet", "RefundSettlement")
+	defer done()
+
+	currency := settlement.Amount.Currency
+	_, err := wr.apply(ctx, "settlement-release:"+settlement.Id, settlement.WinnerId, currency, settlement.AuctionId, func(wallet entity.Wallet, current entity.Hold) (*entity.LedgerTransaction, error) {
+		return wallet.ReleaseHold(settlement.AuctionId, settlement.BidId, current, time.Now()), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	capture, err := wr.findTransaction(ctx, "capture:"+settlement.Id)
+	if err != nil || capture == nil {
+		return err
+	}
+
+	_, err = wr.apply(ctx, "refund:"+settlement.Id, settlement.WinnerId, currency, "", func(wallet entity.Wallet, _ entity.Hold) (*entity.LedgerTransaction, error) {
+		return wallet.RefundCapture(settlement.AuctionId, settlement.BidId, capture.PreviousHold.Amount, time.Now()), nil
+	})
+	return err
+}
+
+func (wr *WalletRepository) holdSettlement(ctx context.Context, settlement entity.Settlement) error {
+	_, err := wr.apply(ctx, "settlement-hold:"+settlement.Id, settlement.WinnerId, settlement.Amount.Currency, settlement.AuctionId, func(wallet entity.Wallet, current entity.Hold) (*entity.LedgerTransaction, error) {
+		return wallet.HoldSettlement(settlement, current, time.Now())
+	})
+	return err
+}
+
+// FindWallets retorna o saldo do usuário em cada moeda aceita
+func (wr *WalletRepository) FindWallets(ctx context.Context, userId string) ([]entity.Wallet, error) {
+	ctx, done := tracing.Repository(ctx, "wallet", "FindWallets")
+	defer done()
+
+	wallets := make([]entity.Wallet, 0, len(entity.SupportedCurrencies))
+	for _, currency := range entity.SupportedCurrencies {
+		wallet, err := wr.loadWallet(ctx, userId, currency)
+		if err != nil {
+			return nil, err
+		}
+		wallets = append(wallets, wallet)
+	}
+	return wallets, nil
+}
+
+// FindLedger busca as transações mais recentes do usuário, de todas as moedas
+func (wr *WalletRepository) FindLedger(ctx context.Context, userId string, limit int64) ([]entity.LedgerTransaction, error) {
+	ctx, done := tracing.Repository(ctx, "wallet", "FindLedger")
+	defer done()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "sequence", Value: -1}}).
+		SetLimit(limit)
+	cursor, err := wr.Collection.Find(ctx, bson.M{"user_id": userId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactionEntitiesMongo []entity.LedgerTransactionEntityMongo
+	if err := cursor.All(ctx, &transactionEntitiesMongo); err != nil {
+		return nil, err
+	}
+
+	transactions := make([]entity.LedgerTransaction, 0, len(transactionEntitiesMongo))
+	for _, transactionMongo := range transactionEntitiesMongo {
+		transactions = append(transactions, toLedgerTransaction(transactionMongo))
+	}
+	return transactions, nil
+}
+
+// apply carrega a carteira e a reserva do usuário no leilão (se auctionId não for vazio),
+// monta a transação com build e a grava com o id informado. Se outra transação usar a mesma
+// sequência antes, a carteira é recarregada e build executado de novo. Uma transação com o id
+// já gravado não é aplicada outra vez; nesse caso, e quando build não tem o que gravar,
+// retorna nil.
+func (wr *WalletRepository) apply(
+	ctx context.Context,
+	transactionId string,
+	userId string,
+	currency entity.Currency,
+	auctionId string,
+	build func(wallet entity.Wallet, current entity.Hold) (*entity.LedgerTransaction, error),
+) (*entity.LedgerTransaction, error) {
+	for attempt := 1; ; attempt++ {
+		applied, err := wr.findTransaction(ctx, transactionId)
+		if err != nil || applied != nil {
+			return nil, err
+		}
+
+		wallet, err := wr.loadWallet(ctx, userId, currency)
+		if err != nil {
+			return nil, err
+		}
+
+		var current entity.Hold
+		if auctionId != "" {
+			if current, err = wr.loadHold(ctx, wallet, auctionId); err != nil {
+				return nil, err
+			}
+		}
+
+		transaction, err := build(wallet, current)
+		if err != nil || transaction == nil {
+			return nil, err
+		}
+		transaction.Id = transactionId
+
+		_, err = wr.Collection.InsertOne(ctx, toLedgerTransactionEntityMongo(transaction))
+		if mongo.IsDuplicateKeyError(err) {
+			if attempt < maxWalletAttempts {
+				continue
+			}
+			return nil, entity.ErrWalletConflict
+		}
+		if err != nil {
+			return nil, err
+		}
+		return transaction, nil
+	}
+}
+
+// loadWallet lê os saldos da última transação da carteira; sem transações o saldo é zero
+func (wr *WalletRepository) loadWallet(ctx context.Context, userId string, currency entity.Currency) (entity.Wallet, error) {
+	wallet := entity.Wallet{
+		UserId:    userId,
+		Currency:  currency,
+		Available: entity.NewMoney(0, currency),
+		Held:      entity.NewMoney(0, currency),
+	}
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}})
+	var transactionMongo entity.LedgerTransactionEntityMongo
+	err := wr.Collection.FindOne(ctx, bson.M{"wallet_id": entity.WalletId(userId, currency)}, opts).Decode(&transactionMongo)
+	if err == mongo.ErrNoDocuments {
+		return wallet, nil
+	}
+	if err != nil {
+		return wallet, err
+	}
+
+	wallet.Sequence = transactionMongo.Sequence
+	wallet.Available = entity.NewMoney(transactionMongo.Available, currency)
+	wallet.Held = entity.NewMoney(transactionMongo.Held, currency)
+	return wallet, nil
+}
+
+// loadHold lê a reserva do usuário no leilão na última transação da carteira ligada a ele
+func (wr *WalletRepository) loadHold(ctx context.Context, wallet entity.Wallet, auctionId string) (entity.Hold, error) {
+	filter := bson.M{"wallet_id": entity.WalletId(wallet.UserId, wallet.Currency), "auction_id": auctionId}
+	opts := options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}})
+
+	var transactionMongo entity.LedgerTransactionEntityMongo
+	err := wr.Collection.FindOne(ctx, filter, opts).Decode(&transactionMongo)
+	if err == mongo.ErrNoDocuments {
+		return entity.Hold{}, nil
+	}
+	if err != nil {
+		return entity.Hold{}, err
+	}
+	return entity.Hold{BidId: transactionMongo.HoldBidId, Amount: transactionMongo.HoldAmount}, nil
+}
+
+func (wr *WalletRepository) findTransaction(ctx context.Context, transactionId string) (*entity.LedgerTransaction, error) {
+	var transactionMongo entity.LedgerTransactionEntityMongo
+	err := wr.Collection.FindOne(ctx, bson.M{"_id": transactionId}).Decode(&transactionMongo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	transaction := toLedgerTransaction(transactionMongo)
+	return &transaction, nil
+}
+
+func toLedgerTransactionEntityMongo(transaction *entity.LedgerTransaction) entity.LedgerTransactionEntityMongo {
+	entries := make([]entity.LedgerEntryEntityMongo, 0, len(transaction.Entries))
+	for _, entry := range transaction.Entries {
+		entries = append(entries, entity.LedgerEntryEntityMongo{Account: entry.Account, Amount: entry.Amount})
+	}
+
+	return entity.LedgerTransactionEntityMongo{
+		Id:                 transaction.Id,
+		WalletId:           entity.WalletId(transaction.UserId, transaction.Currency),
+		UserId:             transaction.UserId,
+		Currency:           transaction.Currency,
+		Sequence:           transaction.Sequence,
+		Type:               transaction.Type,
+		AuctionId:          transaction.AuctionId,
+		BidId:              transaction.BidId,
+		Entries:            entries,
+		Available:          transaction.Available.Cents,
+		Held:               transaction.Held.Cents,
+		HoldBidId:          transaction.Hold.BidId,
+		HoldAmount:         transaction.Hold.Amount,
+		PreviousHoldBidId:  transaction.PreviousHold.BidId,
+		PreviousHoldAmount: transaction.PreviousHold.Amount,
+		CreatedAt:          transaction.CreatedAt.UnixMilli(),
+	}
+}
+
+func toLedgerTransaction(transactionMongo entity.LedgerTransactionEntityMongo) entity.LedgerTransaction {
+	entries := make([]entity.LedgerEntry, 0, len(transactionMongo.Entries))
+	for _, entry := range transactionMongo.Entries {
+		entries = append(entries, entity.LedgerEntry{Account: entry.Account, Amount: entry.Amount})
+	}
+
+	return entity.LedgerTransaction{
+		Id:           transactionMongo.Id,
+		UserId:       transactionMongo.UserId,
+		Currency:     transactionMongo.Currency,
+		Sequence:     transactionMongo.Sequence,
+		Type:         transactionMongo.Type,
+		AuctionId:    transactionMongo.AuctionId,
+		BidId:        transactionMongo.BidId,
+		Entries:      entries,
+		Available:    entity.NewMoney(transactionMongo.Available, transactionMongo.Currency),
+		Held:         entity.NewMoney(transactionMongo.Held, transactionMongo.Currency),
+		Hold:         entity.Hold{BidId: transactionMongo.HoldBidId, Amount: transactionMongo.HoldAmount},
+		PreviousHold: entity.Hold{BidId: transactionMongo.PreviousHoldBidId, Amount: transactionMongo.PreviousHoldAmount},
+		CreatedAt:    time.UnixMilli(transactionMongo.CreatedAt),
+	}
+}
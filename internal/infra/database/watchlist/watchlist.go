@@ -0,0 +1,104 @@
+package watchlist
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WatchlistRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewWatchlistRepository(database *mongo.Database) *WatchlistRepository {
+	return &WatchlistRepository{
+		Collection: database.Collection("watchlists"),
+	}
+}
+
+// AddToWatchlist adiciona o leilão à lista do usuário; adicionar duas vezes não tem efeito
+func (wr *WatchlistRepository) AddToWatchlist(ctx context.Context, item *entity.WatchlistItem) error {
+	ctx, done := tracing.Repository(ctx, "watchlist", "AddToWatchlist")
+	defer done()
+
+	itemEntityMongo := &entity.WatchlistItemEntityMongo{
+		Id:        watchlistItemId(item.UserId, item.AuctionId),
+		UserId:    item.UserId,
+		AuctionId: item.AuctionId,
+		Timestamp: item.Timestamp.UnixMilli(),
+	}
+
+	filter := bson.M{"_id": itemEntityMongo.Id}
+	update := bson.M{"$setOnInsert": itemEntityMongo}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := wr.Collection.UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
+// RemoveFromWatchlist remove o leilão da lista do usuário; retorna false se ele não estava na lista
+func (wr *WatchlistRepository) RemoveFromWatchlist(ctx context.Context, userId, auctionId string) (bool, error) {
+	ctx, done := tracing.Repository(ctx, "watchlist", "RemoveFromWatchlist")
+	defer done()
+
+	result, err := wr.Collection.DeleteOne(ctx, bson.M{"_id": watchlistItemId(userId, auctionId)})
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// FindWatchlistByUserId busca os leilões acompanhados por um usuário
+func (wr *WatchlistRepository) FindWatchlistByUserId(ctx context.Context, userId string) ([]entity.WatchlistItem, error) {
+	ctx, done := tracing.Repository(ctx, "watchlist", "FindWatchlistByUserId")
+	defer done()
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	return wr.findItems(ctx, bson.M{"user_id": userId}, opts)
+}
+
+// FindWatchersByAuctionIds busca todos os usuários que acompanham algum dos leilões informados
+func (wr *WatchlistRepository) FindWatchersByAuctionIds(ctx context.Context, auctionIds []string) ([]entity.WatchlistItem, error) {
+	ctx, done := tracing.Repository(ctx, "watchlist", "FindWatchersByAuctionIds")
+	defer done()
+
+	if len(auctionIds) == 0 {
+		return nil, nil
+	}
+
+	return wr.findItems(ctx, bson.M{"auction_id": bson.M{"$in": auctionIds}})
+}
+
+func (wr *WatchlistRepository) findItems(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]entity.WatchlistItem, error) {
+	cursor, err := wr.Collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var itemEntitiesMongo []entity.WatchlistItemEntityMongo
+	if err := cursor.All(ctx, &itemEntitiesMongo); err != nil {
+		return nil, err
+	}
+
+	var items []entity.WatchlistItem
+	for _, itemMongo := range itemEntitiesMongo {
+		items = append(items, entity.WatchlistItem{
+			UserId:    itemMongo.UserId,
+			AuctionId: itemMongo.AuctionId,
+			Timestamp: time.UnixMilli(itemMongo.Timestamp),
+		})
+	}
+
+	return items, nil
+}
+
+func watchlistItemId(userId, auctionId string) string {
+	return userId + ":" + auctionId
+}
@@ -0,0 +1,133 @@
+package fees
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/auction-goexpert/internal/entity"
+)
+
+type tierFile struct {
+	UpTo    *entity.Money `json:"up_to"`
+	Percent string        `json:"percent"`
+}
+
+type finalValueFeeFile struct {
+	Tiers   []tierFile    `json:"tiers"`
+	Minimum *entity.Money `json:"minimum"`
+	Maximum *entity.Money `json:"maximum"`
+}
+
+type scheduleFile struct {
+	ListingFee      *entity.Money                `json:"listing_fee"`
+	FinalValueFee   finalValueFeeFile            `json:"final_value_fee"`
+	Categories      map[string]finalValueFeeFile `json:"categories"`
+	FeeTaxPercent   string                       `json:"fee_tax_percent"`
+	BuyerTaxPercent string                       `json:"buyer_tax_percent"`
+}
+
+// Schedules retorna a tabela de tarifas de cada moeda aceita: a do arquivo em path ou, sem
+// arquivo ou sem a moeda no arquivo, a tabela padrão. O arquivo tem uma tabela por moeda,
+// com valores decimais na moeda e percentuais como strings:
+//
+//	{"BRL": {"listing_fee": "2.00",
+//	         "final_value_fee": {"tiers": [{"up_to": "1000.00", "percent": "10"}, {"percent": "5"}],
+//	                             "minimum": "1.00", "maximum": "500.00"},
+//	         "categories": {"Electronics": {"tiers": [{"percent": "8"}]}},
+//	         "fee_tax_percent": "5", "buyer_tax_percent": "0"}}
+func Schedules(path string) (map[entity.Currency]entity.FeeSchedule, error) {
+	schedules := make(map[entity.Currency]entity.FeeSchedule, len(entity.SupportedCurrencies))
+	for _, currency := range entity.SupportedCurrencies {
+		schedules[currency] = entity.DefaultFeeSchedule()
+	}
+	if path == "" {
+		return schedules, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files map[entity.Currency]scheduleFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, fmt.Errorf("invalid fee schedule file %s: %w", path, err)
+	}
+
+	currencies := make([]string, 0, len(files))
+	for currency := range files {
+		currencies = append(currencies, string(currency))
+	}
+	sort.Strings(currencies)
+
+	for _, name := range currencies {
+		currency := entity.Currency(name)
+		if !currency.IsSupported() {
+			return nil, fmt.Errorf("invalid fee schedule file %s: unsupported currency %s", path, currency)
+		}
+		schedule, err := files[currency].toFeeSchedule()
+		if err == nil {
+			err = schedule.Validate()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee schedule file %s, currency %s: %w", path, currency, err)
+		}
+		schedules[currency] = schedule
+	}
+
+	return schedules, nil
+}
+
+func (f scheduleFile) toFeeSchedule() (entity.FeeSchedule, error) {
+	finalValueFee, err := f.FinalValueFee.toFinalValueFee()
+	if err != nil {
+		return entity.FeeSchedule{}, err
+	}
+
+	schedule := entity.FeeSchedule{
+		ListingFee:    cents(f.ListingFee),
+		FinalValueFee: finalValueFee,
+		Categories:    make(map[string]entity.FinalValueFee, len(f.Categories)),
+	}
+	for category, fee := range f.Categories {
+		if schedule.Categories[category], err = fee.toFinalValueFee(); err != nil {
+			return entity.FeeSchedule{}, fmt.Errorf("category %s: %w", category, err)
+		}
+	}
+	if schedule.FeeTaxRate, err = percent(f.FeeTaxPercent); err != nil {
+		return entity.FeeSchedule{}, fmt.Errorf("fee_tax_percent: %w", err)
+	}
+	if schedule.BuyerTaxRate, err = percent(f.BuyerTaxPercent); err != nil {
+		return entity.FeeSchedule{}, fmt.Errorf("buyer_tax_percent: %w", err)
+	}
+	return schedule, nil
+}
+
+func (f finalValueFeeFile) toFinalValueFee() (entity.FinalValueFee, error) {
+	fee := entity.FinalValueFee{Minimum: cents(f.Minimum), Maximum: cents(f.Maximum)}
+	for i, tier := range f.Tiers {
+		rate, err := percent(tier.Percent)
+		if err != nil {
+			return entity.FinalValueFee{}, fmt.Errorf("tier %d: %w", i+1, err)
+		}
+		fee.Tiers = append(fee.Tiers, entity.FeeTier{UpTo: cents(tier.UpTo), Rate: rate})
+	}
+	return fee, nil
+}
+
+func cents(amount *entity.Money) int64 {
+	if amount == nil {
+		return 0
+	}
+	return amount.Cents
+}
+
+// percent aceita percentual vazio como zero
+func percent(value string) (entity.BasisPoints, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return entity.ParseBasisPoints(value)
+}
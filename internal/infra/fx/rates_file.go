@@ -0,0 +1,27 @@
+package fx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/auction-goexpert/internal/usecase/exchange_rate_usecase"
+)
+
+// ReadRatesFile lê a tabela de cotações de um arquivo JSON local, no mesmo formato
+// aceito pelo endpoint administrativo:
+//
+//	[{"from": "USD", "to": "BRL", "rate": "4.9875", "effective_at": "2024-01-01T00:00:00Z"}]
+func ReadRatesFile(path string) ([]exchange_rate_usecase.ExchangeRateInputDTO, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rates []exchange_rate_usecase.ExchangeRateInputDTO
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("invalid exchange rates file %s: %w", path, err)
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/auction-goexpert/configuration/config"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type requestIdKey struct{}
+
+// WithRequestId guarda o id da requisição no contexto, para que todo log feito com
+// esse contexto (inclusive nos repositórios) traga o campo request_id
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+// RequestIdFromContext retorna o id da requisição, ou "" fora de uma requisição HTTP
+func RequestIdFromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey{}).(string)
+	return requestId
+}
+
+// New cria o logger da aplicação no formato e nível configurados
+func New(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: cfg.Level}
+
+	var handler slog.Handler
+	if cfg.Format == "text" {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.New(contextHandler{handler})
+}
+
+// contextHandler acrescenta a cada registro o request_id e o trace_id/span_id presentes
+// no contexto, o que exige usar os métodos *Context do logger (InfoContext, ErrorContext)
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestId := RequestIdFromContext(ctx); requestId != "" {
+		record.AddAttrs(slog.String("request_id", requestId))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Campos padronizados, para que os mesmos dados tenham o mesmo nome em todos os logs
+
+func AuctionId(id string) slog.Attr {
+	return slog.String("auction_id", id)
+}
+
+func BidId(id string) slog.Attr {
+	return slog.String("bid_id", id)
+}
+
+func UserId(id string) slog.Attr {
+	return slog.String("user_id", id)
+}
+
+func Err(err error) slog.Attr {
+	return slog.Any("error", err)
+}
+
+// Fatal registra o erro e encerra o processo, para falhas na inicialização
+func Fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, Err(err))
+	os.Exit(1)
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/auction-goexpert/configuration/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func decodeLines(t *testing.T, output *bytes.Buffer) []map[string]any {
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var fields map[string]any
+		require.Nil(t, json.Unmarshal([]byte(line), &fields))
+		lines = append(lines, fields)
+	}
+	return lines
+}
+
+func TestLoggerAddsRequestAndTraceIdsFromContext(t *testing.T) {
+	var output bytes.Buffer
+	logger := New(config.LogConfig{Level: slog.LevelInfo, Format: "json"}, &output).With("repository", "bid")
+
+	traceId, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanId, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(WithRequestId(context.Background(), "req-1"),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceId, SpanID: spanId}))
+
+	logger.ErrorContext(ctx, "Error creating bid", AuctionId("auction-1"), BidId("bid-1"), Err(errors.New("boom")))
+	logger.Info("Without request")
+	logger.DebugContext(ctx, "Filtered by level")
+
+	lines := decodeLines(t, &output)
+	require.Len(t, lines, 2)
+
+	assert.Equal(t, "Error creating bid", lines[0]["msg"])
+	assert.Equal(t, "bid", lines[0]["repository"])
+	assert.Equal(t, "req-1", lines[0]["request_id"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", lines[0]["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", lines[0]["span_id"])
+	assert.Equal(t, "auction-1", lines[0]["auction_id"])
+	assert.Equal(t, "bid-1", lines[0]["bid_id"])
+	assert.Equal(t, "boom", lines[0]["error"])
+
+	assert.NotContains(t, lines[1], "request_id")
+	assert.NotContains(t, lines[1], "trace_id")
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// activeAuctionsCollector consulta o total de leilões ativos a cada coleta, para que o
+// valor seja o mesmo em todas as réplicas
+type activeAuctionsCollector struct {
+	count   func(ctx context.Context) (int64, error)
+	timeout time.Duration
+	desc    *prometheus.Desc
+	logger  *slog.Logger
+}
+
+// RegisterActiveAuctions registra o gauge auction_active_auctions com a função de contagem
+// informada. Se a contagem falhar a série é omitida naquela coleta.
+func RegisterActiveAuctions(count func(ctx context.Context) (int64, error), timeout time.Duration, logger *slog.Logger) {
+	Registry.MustRegister(&activeAuctionsCollector{
+		count:   count,
+		timeout: timeout,
+		desc:    prometheus.NewDesc(namespace+"_active_auctions", "Auctions currently active.", nil, nil),
+		logger:  logger,
+	})
+}
+
+func (c *activeAuctionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *activeAuctionsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	count, err := c.count(ctx)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Error counting active auctions for metrics", logging.Err(err))
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count))
+}
@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const namespace = "auction"
+
+// Registry reúne as métricas da aplicação e as do runtime do Go e do processo
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+var (
+	bidsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bids_total",
+		Help:      "Bids processed, by result (accepted or rejected) and rejection reason.",
+	}, []string{"result", "reason"})
+
+	auctionsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auctions_total",
+		Help:      "Auction lifecycle events (created, closed, cancelled).",
+	}, []string{"event"})
+
+	closeDelay = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "close_delay_seconds",
+		Help:      "Time between an auction's expires_at and its automatic closing.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 15, 30, 60, 120, 300},
+	})
+
+	httpDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	repositoryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_operation_duration_seconds",
+		Help:      "Repository method latency, including synchronous listeners, by repository and method.",
+		Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	}, []string{"repository", "method"})
+
+	mongoCommandDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongo_command_duration_seconds",
+		Help:      "MongoDB driver command latency, by command and result.",
+		Buckets:   []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	}, []string{"command", "result"})
+)
+
+// OnBidPlaced conta um lance aceito; é registrado como listener do repositório de lances
+func OnBidPlaced(ctx context.Context, bid entity.Bid, previousHighest *entity.Bid) {
+	bidsTotal.WithLabelValues("accepted", "").Inc()
+}
+
+// OnBidRejected conta um lance recusado pelo use case, pelo código do erro
+// (por exemplo "bid_too_low" ou "auction_closed")
+func OnBidRejected(ctx context.Context, rejection entity.BidRejection) {
+	bidsTotal.WithLabelValues("rejected", rejection.Reason).Inc()
+}
+
+// AuctionCreated conta um leilão criado
+func AuctionCreated() {
+	auctionsTotal.WithLabelValues("created").Inc()
+}
+
+// AuctionClosed conta um leilão encerrado automaticamente e registra o atraso em
+// relação ao horário de expiração
+func AuctionClosed(expiresAt, closedAt time.Time) {
+	auctionsTotal.WithLabelValues("closed").Inc()
+	closeDelay.Observe(closedAt.Sub(expiresAt).Seconds())
+}
+
+// AuctionStatusChanged conta um leilão encerrado ou cancelado por UpdateAuctionStatus; o
+// encerramento automático é contado por AuctionClosed
+func AuctionStatusChanged(status entity.AuctionStatus) {
+	switch status {
+	case entity.Completed:
+		auctionsTotal.WithLabelValues("closed").Inc()
+	case entity.Cancelled:
+		auctionsTotal.WithLabelValues("cancelled").Inc()
+	}
+}
+
+// RepositoryOperation mede um método de repositório inteiro, inclusive os listeners que ele
+// chama; use com defer metrics.RepositoryOperation("auction", "CreateAuction").ObserveDuration()
+func RepositoryOperation(repository, method string) *prometheus.Timer {
+	return prometheus.NewTimer(repositoryDuration.WithLabelValues(repository, method))
+}
+
+// CommandMonitor mede só o tempo de cada comando no driver do MongoDB e repassa os eventos
+// para next (o monitor de tracing, por exemplo), que pode ser nil
+func CommandMonitor(next *event.CommandMonitor) *event.CommandMonitor {
+	if next == nil {
+		next = &event.CommandMonitor{}
+	}
+
+	return &event.CommandMonitor{
+		Started: next.Started,
+		Succeeded: func(ctx context.Context, succeeded *event.CommandSucceededEvent) {
+			mongoCommandDuration.WithLabelValues(succeeded.CommandName, "success").Observe(succeeded.Duration.Seconds())
+			if next.Succeeded != nil {
+				next.Succeeded(ctx, succeeded)
+			}
+		},
+		Failed: func(ctx context.Context, failed *event.CommandFailedEvent) {
+			mongoCommandDuration.WithLabelValues(failed.CommandName, "failure").Observe(failed.Duration.Seconds())
+			if next.Failed != nil {
+				next.Failed(ctx, failed)
+			}
+		},
+	}
+}
+
+// HTTPMiddleware mede a latência das requisições pelo padrão da rota (por exemplo
+// "/v1/auction/:auctionId"), para que IDs não virem séries diferentes
+func HTTPMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(started).Seconds())
+	}
+}
+
+// Handler responde as métricas no formato do Prometheus
+func Handler() gin.HandlerFunc {
+	handler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+	return gin.WrapH(handler)
+}
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestHTTPMiddlewareLabelsByRoutePattern(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(HTTPMiddleware())
+	router.GET("/v1/auction/:auctionId", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	for _, id := range []string{"a", "b"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/auction/"+id, nil))
+	}
+
+	families, err := Registry.Gather()
+	assert.Nil(t, err)
+
+	var routes []string
+	for _, family := range families {
+		if family.GetName() != "auction_http_request_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "route" {
+					routes = append(routes, label.GetValue())
+				}
+			}
+			assert.Equal(t, uint64(2), metric.GetHistogram().GetSampleCount())
+		}
+	}
+	assert.Equal(t, []string{"/v1/auction/:auctionId"}, routes)
+}
+
+func TestBidAndAuctionCounters(t *testing.T) {
+	ctx := context.Background()
+	rejected := testutil.ToFloat64(bidsTotal.WithLabelValues("rejected", "bid_too_low"))
+
+	rejection := entity.BidRejection{AuctionId: "auction-1", Reason: "bid_too_low"}
+	OnBidRejected(ctx, rejection)
+	OnBidRejected(ctx, rejection)
+
+	assert.Equal(t, rejected+2, testutil.ToFloat64(bidsTotal.WithLabelValues("rejected", "bid_too_low")))
+
+	closed := testutil.ToFloat64(auctionsTotal.WithLabelValues("closed"))
+	expiresAt := time.Now()
+	AuctionClosed(expiresAt, expiresAt.Add(3*time.Second))
+	assert.Equal(t, closed+1, testutil.ToFloat64(auctionsTotal.WithLabelValues("closed")))
+
+	cancelled := testutil.ToFloat64(auctionsTotal.WithLabelValues("cancelled"))
+	AuctionStatusChanged(entity.Cancelled)
+	assert.Equal(t, cancelled+1, testutil.ToFloat64(auctionsTotal.WithLabelValues("cancelled")))
+}
+
+func TestCommandMonitorTimesDriverCommands(t *testing.T) {
+	var forwarded int
+	monitor := CommandMonitor(&event.CommandMonitor{
+		Succeeded: func(ctx context.Context, succeeded *event.CommandSucceededEvent) { forwarded++ },
+	})
+
+	before := testutil.CollectAndCount(mongoCommandDuration)
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "insert", Duration: 3 * time.Millisecond},
+	})
+	monitor.Failed(context.Background(), &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "insert", Duration: time.Millisecond},
+	})
+
+	assert.Equal(t, 1, forwarded)
+	assert.Equal(t, before+2, testutil.CollectAndCount(mongoCommandDuration))
+}
+
+func TestActiveAuctionsGaugeSkipsFailedCounts(t *testing.T) {
+	count := int64(7)
+	var countErr error
+	collector := &activeAuctionsCollector{
+		count:   func(ctx context.Context) (int64, error) { return count, countErr },
+		timeout: time.Second,
+		desc:    prometheus.NewDesc("test_active_auctions", "Auctions currently active.", nil, nil),
+		logger:  slog.Default(),
+	}
+
+	expected := "# HELP test_active_auctions Auctions currently active.\n# TYPE test_active_auctions gauge\ntest_active_auctions 7\n"
+	assert.Nil(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+
+	countErr = errors.New("mongo unavailable")
+	assert.Equal(t, 0, testutil.CollectAndCount(collector))
+}
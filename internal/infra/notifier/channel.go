@@ -0,0 +1,199 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/logging"
+)
+
+// Message é uma notificação já renderizada, pronta para ser entregue
+type Message struct {
+	UserId    string
+	AuctionId string
+	Type      entity.NotificationType
+	Subject   string
+	Body      string
+}
+
+// ErrChannelSkipped indica que o canal não se aplica ao usuário (sem email cadastrado, por
+// exemplo); a notificação não conta como entregue por ele, mas também não é uma falha
+var ErrChannelSkipped = errors.New("notification channel skipped")
+
+// Channel é um meio de entrega de notificações (email, caixa de entrada, log...)
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, preference *entity.NotificationPreference, message Message) error
+}
+
+// emailTimeout limita a conversa com o servidor SMTP quando o contexto não tem prazo; o envio
+// roda dentro do fechamento de leilões e não pode travá-lo
+const emailTimeout = 30 * time.Second
+
+// EmailChannel envia notificações por SMTP
+type EmailChannel struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewEmailChannel(host, port, username, password, from string) *EmailChannel {
+	return &EmailChannel{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (ec *EmailChannel) Name() string {
+	return entity.EmailNotificationChannel
+}
+
+func (ec *EmailChannel) Send(ctx context.Context, preference *entity.NotificationPreference, message Message) error {
+	if preference.Email == "" {
+		return ErrChannelSkipped
+	}
+
+	var auth smtp.Auth
+	if ec.username != "" {
+		auth = smtp.PlainAuth("", ec.username, ec.password, ec.host)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", ec.from)
+	fmt.Fprintf(&body, "To: %s\r\n", preference.Email)
+	fmt.Fprintf(&body, "Subject: %s\r\n", encodeSubject(message.Subject))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	body.WriteString(message.Body)
+
+	return ec.sendMail(ctx, auth, preference.Email, []byte(body.String()))
+}
+
+// sendMail faz o mesmo que smtp.SendMail, mas a conexão respeita o cancelamento e o prazo do
+// contexto (ou emailTimeout, se ele não tiver prazo)
+func (ec *EmailChannel) sendMail(ctx context.Context, auth smtp.Auth, to string, msg []byte) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, emailTimeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ec.host, ec.port))
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	// Cancelar o contexto fecha a conexão e interrompe a operação em andamento
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, ec.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: ec.host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(ec.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(msg); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// encodeSubject impede que quebras de linha vindas de dados do usuário (o nome do produto,
+// por exemplo) injetem cabeçalhos e codifica o assunto para que acentos cheguem intactos
+func encodeSubject(subject string) string {
+	subject = strings.Join(strings.FieldsFunc(subject, func(r rune) bool {
+		return r == '\r' || r == '\n'
+	}), " ")
+	return mime.QEncoding.Encode("utf-8", subject)
+}
+
+// InboxChannel grava a notificação na caixa de entrada do usuário dentro da aplicação
+type InboxChannel struct {
+	notificationRepository entity.NotificationRepositoryInterface
+}
+
+func NewInboxChannel(notificationRepository entity.NotificationRepositoryInterface) *InboxChannel {
+	return &InboxChannel{
+		notificationRepository: notificationRepository,
+	}
+}
+
+func (ic *InboxChannel) Name() string {
+	return entity.InboxNotificationChannel
+}
+
+func (ic *InboxChannel) Send(ctx context.Context, preference *entity.NotificationPreference, message Message) error {
+	notification, err := entity.CreateNotification(message.UserId, message.AuctionId, message.Type, message.Subject, message.Body)
+	if err != nil {
+		return err
+	}
+
+	return ic.notificationRepository.CreateNotification(ctx, notification)
+}
+
+// LogChannel apenas registra a notificação no log da aplicação
+type LogChannel struct {
+	logger *slog.Logger
+}
+
+func NewLogChannel(logger *slog.Logger) *LogChannel {
+	return &LogChannel{logger: logger}
+}
+
+func (lc *LogChannel) Name() string {
+	return entity.LogNotificationChannel
+}
+
+func (lc *LogChannel) Send(ctx context.Context, preference *entity.NotificationPreference, message Message) error {
+	lc.logger.InfoContext(ctx, "Notification sent",
+		slog.String("type", string(message.Type)), logging.UserId(message.UserId), logging.AuctionId(message.AuctionId),
+		slog.String("subject", message.Subject))
+	return nil
+}
@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeSubjectStripsLineBreaks(t *testing.T) {
+	assert.Equal(t, "You won the auction for Bike", encodeSubject("You won the auction for Bike"))
+	assert.Equal(t, "Bike Bcc: victim@example.com", encodeSubject("Bike\r\nBcc: victim@example.com"))
+	assert.Equal(t, "=?utf-8?q?Voc=C3=AA_venceu_o_leil=C3=A3o?=", encodeSubject("Você venceu o leilão"))
+}
+
+func TestEmailSendStopsWhenTheContextEnds(t *testing.T) {
+	// Servidor que aceita a conexão e nunca responde
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	channel := NewEmailChannel(host, port, "", "", "auctions@example.com")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	preference := &entity.NotificationPreference{UserId: "user-1", Email: "user@example.com"}
+	assert.Error(t, channel.Send(ctx, preference, Message{Subject: "Bike", Body: "You won"}))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
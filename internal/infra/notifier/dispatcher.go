@@ -0,0 +1,249 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/logging"
+)
+
+// Dispatcher reage a lances e encerramentos de leilões e entrega as notificações
+// pelos canais escolhidos por cada usuário
+type Dispatcher struct {
+	auctionRepository      entity.AuctionRepositoryInterface
+	bidRepository          entity.BidRepositoryInterface
+	notificationRepository entity.NotificationRepositoryInterface
+	preferenceRepository   entity.NotificationPreferenceRepositoryInterface
+	templates              *Templates
+	channels               map[string]Channel
+	logger                 *slog.Logger
+}
+
+func NewDispatcher(
+	auctionRepository entity.AuctionRepositoryInterface,
+	bidRepository entity.BidRepositoryInterface,
+	notificationRepository entity.NotificationRepositoryInterface,
+	preferenceRepository entity.NotificationPreferenceRepositoryInterface,
+	templates *Templates,
+	logger *slog.Logger,
+	channels ...Channel,
+) *Dispatcher {
+	channelsByName := make(map[string]Channel, len(channels))
+	for _, channel := range channels {
+		channelsByName[channel.Name()] = channel
+	}
+
+	return &Dispatcher{
+		auctionRepository:      auctionRepository,
+		bidRepository:          bidRepository,
+		notificationRepository: notificationRepository,
+		preferenceRepository:   preferenceRepository,
+		templates:              templates,
+		channels:               channelsByName,
+		logger:                 logger,
+	}
+}
+
+// OnBidPlaced avisa o autor do maior lance anterior que ele foi superado
+func (d *Dispatcher) OnBidPlaced(ctx context.Context, bid entity.Bid, previousHighest *entity.Bid) {
+	if previousHighest == nil || previousHighest.UserId == bid.UserId {
+		return
+	}
+
+	// A entrega roda em segundo plano para não atrasar a resposta do lance
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		auction, err := d.auctionRepository.FindAuctionById(ctx, bid.AuctionId)
+		if err != nil || auction == nil {
+			d.logger.ErrorContext(ctx, "Error loading auction for outbid notification",
+				logging.AuctionId(bid.AuctionId), logging.BidId(bid.Id), logging.Err(err))
+			return
+		}
+
+		data := TemplateData{
+			AuctionId:   auction.Id,
+			ProductName: auction.ProductName,
+			Amount:      bid.Amount,
+		}
+
+		dedupKey := string(entity.OutbidNotification) + ":" + previousHighest.UserId + ":" + bid.Id
+		if err := d.Notify(ctx, previousHighest.UserId, entity.OutbidNotification, dedupKey, data); err != nil {
+			d.logger.ErrorContext(ctx, "Error sending outbid notification",
+				logging.AuctionId(auction.Id), logging.BidId(bid.Id), logging.UserId(previousHighest.UserId), logging.Err(err))
+		}
+	}()
+}
+
+// OnAuctionClosed avisa o vencedor de um leilão encerrado. Com a reserva não alcançada o
+// leilão termina sem vencedor. Uma falha não faz o encerramento ser retomado; com ele
+// retomado por outro motivo, a notificação já entregue não se repete.
+func (d *Dispatcher) OnAuctionClosed(ctx context.Context, auction entity.Auction) error {
+	if !auction.ReserveMet() {
+		return nil
+	}
+
+	// A entrega roda em segundo plano para não atrasar os encerramentos seguintes
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		winningBid, err := d.bidRepository.FindWinningBidByAuctionId(ctx, auction.Id)
+		if err != nil {
+			d.logger.ErrorContext(ctx, "Error loading winning bid", logging.AuctionId(auction.Id), logging.Err(err))
+			return
+		}
+
+		if winningBid == nil {
+			return
+		}
+
+		data := TemplateData{
+			AuctionId:   auction.Id,
+			ProductName: auction.ProductName,
+			Amount:      winningBid.Amount,
+		}
+
+		dedupKey := string(entity.AuctionWonNotification) + ":" + auction.Id
+		if err := d.Notify(ctx, winningBid.UserId, entity.AuctionWonNotification, dedupKey, data); err != nil {
+			d.logger.ErrorContext(ctx, "Error sending auction won notification",
+				logging.AuctionId(auction.Id), logging.BidId(winningBid.Id), logging.UserId(winningBid.UserId), logging.Err(err))
+		}
+	}()
+	return nil
+}
+
+// OnSettlementOffered avisa o autor do próximo maior lance, ou quem aceitou uma oferta de
+// segunda chance, que o item passou para ele.
+// A primeira tentativa de um leilão que alcançou a reserva já é avisada pela notificação de
+// leilão vencido.
+func (d *Dispatcher) OnSettlementOffered(ctx context.Context, settlement entity.Settlement) error {
+	// A entrega roda em segundo plano para não atrasar o acerto nem o aceite da oferta
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		auction, err := d.auctionRepository.FindAuctionById(ctx, settlement.AuctionId)
+		if err != nil || auction == nil {
+			d.logger.ErrorContext(ctx, "Error loading auction for payment requested notification",
+				logging.AuctionId(settlement.AuctionId), logging.Err(err))
+			return
+		}
+
+		if settlement.Attempt == 1 && auction.ReserveMet() {
+			return
+		}
+
+		data := TemplateData{
+			AuctionId:   auction.Id,
+			ProductName: auction.ProductName,
+			Amount:      settlement.Amount,
+		}
+
+		dedupKey := string(entity.PaymentRequestedNotification) + ":" + settlement.Id
+		if err := d.Notify(ctx, settlement.WinnerId, entity.PaymentRequestedNotification, dedupKey, data); err != nil {
+			d.logger.ErrorContext(ctx, "Error sending payment requested notification",
+				logging.AuctionId(auction.Id), logging.BidId(settlement.BidId), logging.UserId(settlement.WinnerId), logging.Err(err))
+		}
+	}()
+	return nil
+}
+
+// OnSecondChanceOffered avisa o licitante que recebeu uma oferta de segunda chance
+func (d *Dispatcher) OnSecondChanceOffered(ctx context.Context, offer entity.SecondChanceOffer) {
+	// A entrega roda em segundo plano para não atrasar a resposta ao vendedor
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		auction, err := d.auctionRepository.FindAuctionById(ctx, offer.AuctionId)
+		if err != nil || auction == nil {
+			d.logger.ErrorContext(ctx, "Error loading auction for second-chance offer notification",
+				logging.AuctionId(offer.AuctionId), logging.Err(err))
+			return
+		}
+
+		data := TemplateData{
+			AuctionId:   auction.Id,
+			ProductName: auction.ProductName,
+			Amount:      offer.Amount,
+		}
+
+		dedupKey := string(entity.SecondChanceNotification) + ":" + offer.Id
+		if err := d.Notify(ctx, offer.UserId, entity.SecondChanceNotification, dedupKey, data); err != nil {
+			d.logger.ErrorContext(ctx, "Error sending second-chance offer notification",
+				logging.AuctionId(auction.Id), logging.UserId(offer.UserId), logging.Err(err))
+		}
+	}()
+}
+
+// Notify renderiza e entrega uma notificação respeitando as preferências do usuário.
+// Notificações com a mesma dedupKey são entregues apenas uma vez; se nenhum canal entregar,
+// por falha ou porque nenhum se aplica ao usuário, a chave é liberada para que a notificação
+// possa ser enviada de novo.
+func (d *Dispatcher) Notify(ctx context.Context, userId string, notificationType entity.NotificationType, dedupKey string, data TemplateData) error {
+	preference, err := d.preferenceRepository.FindPreferenceByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if preference == nil {
+		preference = &entity.NotificationPreference{
+			UserId:   userId,
+			Channels: entity.DefaultNotificationChannels,
+		}
+	}
+
+	if !preference.Allows(notificationType) {
+		return nil
+	}
+
+	subject, body, err := d.templates.Render(preference.Locale, notificationType, data)
+	if err != nil {
+		return err
+	}
+
+	firstDelivery, err := d.notificationRepository.RegisterDelivery(ctx, dedupKey)
+	if err != nil {
+		return err
+	}
+
+	if !firstDelivery {
+		return nil
+	}
+
+	message := Message{
+		UserId:    userId,
+		AuctionId: data.AuctionId,
+		Type:      notificationType,
+		Subject:   subject,
+		Body:      body,
+	}
+
+	var delivered int
+	var sendErrs []error
+	for _, channelName := range preference.Channels {
+		channel, ok := d.channels[channelName]
+		if !ok {
+			continue
+		}
+
+		err := channel.Send(ctx, preference, message)
+		if errors.Is(err, ErrChannelSkipped) {
+			continue
+		}
+		if err != nil {
+			d.logger.ErrorContext(ctx, "Error delivering notification",
+				logging.UserId(userId), logging.AuctionId(data.AuctionId), slog.String("channel", channelName), logging.Err(err))
+			sendErrs = append(sendErrs, err)
+			continue
+		}
+		delivered++
+	}
+
+	// Basta um canal entregar para a notificação contar como enviada; liberar a chave nesse
+	// caso duplicaria a entrega nos canais que funcionaram
+	if delivered == 0 {
+		if err := d.notificationRepository.ReleaseDelivery(ctx, dedupKey); err != nil {
+			sendErrs = append(sendErrs, err)
+		}
+		return errors.Join(sendErrs...)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deliveryRepository struct {
+	entity.NotificationRepositoryInterface
+	keys map[string]bool
+}
+
+func (r *deliveryRepository) RegisterDelivery(ctx context.Context, dedupKey string) (bool, error) {
+	if r.keys[dedupKey] {
+		return false, nil
+	}
+	r.keys[dedupKey] = true
+	return true, nil
+}
+
+func (r *deliveryRepository) ReleaseDelivery(ctx context.Context, dedupKey string) error {
+	delete(r.keys, dedupKey)
+	return nil
+}
+
+type noPreferences struct {
+	entity.NotificationPreferenceRepositoryInterface
+}
+
+func (noPreferences) FindPreferenceByUserId(ctx context.Context, userId string) (*entity.NotificationPreference, error) {
+	return nil, nil
+}
+
+type flakyChannel struct {
+	failures int
+	sent     int
+}
+
+func (c *flakyChannel) Name() string {
+	return entity.InboxNotificationChannel
+}
+
+func (c *flakyChannel) Send(ctx context.Context, preference *entity.NotificationPreference, message Message) error {
+	if c.failures > 0 {
+		c.failures--
+		return errors.New("inbox unavailable")
+	}
+	c.sent++
+	return nil
+}
+
+func TestNotifyRetriesAfterFailedDelivery(t *testing.T) {
+	templates, err := NewTemplates(LocaleEn)
+	require.NoError(t, err)
+
+	deliveries := &deliveryRepository{keys: map[string]bool{}}
+	channel := &flakyChannel{failures: 1}
+	dispatcher := NewDispatcher(nil, nil, deliveries, noPreferences{}, templates,
+		slog.New(slog.NewTextHandler(io.Discard, nil)), channel)
+
+	data := TemplateData{AuctionId: "auction-1", ProductName: "Bike"}
+
+	// A falha libera a chave em vez de suprimir a notificação para sempre
+	assert.Error(t, dispatcher.Notify(context.Background(), "user-1", entity.AuctionWonNotification, "won:auction-1", data))
+	assert.Empty(t, deliveries.keys)
+
+	require.NoError(t, dispatcher.Notify(context.Background(), "user-1", entity.AuctionWonNotification, "won:auction-1", data))
+	require.NoError(t, dispatcher.Notify(context.Background(), "user-1", entity.AuctionWonNotification, "won:auction-1", data))
+	assert.Equal(t, 1, channel.sent)
+}
+
+func TestNotifySkippedChannelDoesNotConsumeTheKey(t *testing.T) {
+	templates, err := NewTemplates(LocaleEn)
+	require.NoError(t, err)
+
+	deliveries := &deliveryRepository{keys: map[string]bool{}}
+	email := NewEmailChannel("smtp.example.com", "587", "", "", "auctions@example.com")
+	dispatcher := NewDispatcher(nil, nil, deliveries, emailOnly{}, templates,
+		slog.New(slog.NewTextHandler(io.Discard, nil)), email)
+
+	data := TemplateData{AuctionId: "auction-1", ProductName: "Bike"}
+
+	// Sem email cadastrado nada é entregue, então a chave fica livre para uma nova tentativa
+	require.NoError(t, dispatcher.Notify(context.Background(), "user-1", entity.AuctionWonNotification, "won:auction-1", data))
+	assert.Empty(t, deliveries.keys)
+}
+
+type emailOnly struct {
+	entity.NotificationPreferenceRepositoryInterface
+}
+
+func (emailOnly) FindPreferenceByUserId(ctx context.Context, userId string) (*entity.NotificationPreference, error) {
+	return &entity.NotificationPreference{UserId: userId, Channels: []string{entity.EmailNotificationChannel}}, nil
+}
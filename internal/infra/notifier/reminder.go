@@ -0,0 +1,156 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/auction-goexpert/internal/infra/tracing"
+)
+
+// ReminderScheduler avisa periodicamente os observadores de leilões que estão terminando
+type ReminderScheduler struct {
+	auctionRepository    entity.AuctionRepositoryInterface
+	bidRepository        entity.BidRepositoryInterface
+	watchlistRepository  entity.WatchlistRepositoryInterface
+	preferenceRepository entity.NotificationPreferenceRepositoryInterface
+	dispatcher           *Dispatcher
+	checkInterval        time.Duration
+	logger               *slog.Logger
+}
+
+func NewReminderScheduler(
+	auctionRepository entity.AuctionRepositoryInterface,
+	bidRepository entity.BidRepositoryInterface,
+	watchlistRepository entity.WatchlistRepositoryInterface,
+	preferenceRepository entity.NotificationPreferenceRepositoryInterface,
+	dispatcher *Dispatcher,
+	checkInterval time.Duration,
+	logger *slog.Logger,
+) *ReminderScheduler {
+	return &ReminderScheduler{
+		auctionRepository:    auctionRepository,
+		bidRepository:        bidRepository,
+		watchlistRepository:  watchlistRepository,
+		preferenceRepository: preferenceRepository,
+		dispatcher:           dispatcher,
+		checkInterval:        checkInterval,
+		logger:               logger,
+	}
+}
+
+// Start inicia a goroutine que envia os lembretes
+func (rs *ReminderScheduler) Start() {
+	go rs.run()
+}
+
+func (rs *ReminderScheduler) run() {
+	ticker := time.NewTicker(rs.checkInterval)
+	defer ticker.Stop()
+
+	rs.logger.Info("Ending-soon reminder scheduler started", slog.String("interval", rs.checkInterval.String()))
+
+	for range ticker.C {
+		ctx, span := tracing.Start(context.Background(), "ReminderScheduler.Run")
+		err := rs.SendReminders(ctx, time.Now())
+		if err != nil {
+			rs.logger.ErrorContext(ctx, "Error sending ending-soon reminders", logging.Err(err))
+		}
+		tracing.End(span, err)
+	}
+}
+
+// SendReminders envia os lembretes devidos no instante informado. Cada lembrete
+// (usuário, leilão, antecedência) é entregue uma única vez.
+func (rs *ReminderScheduler) SendReminders(ctx context.Context, now time.Time) error {
+	// Apenas os leilões dentro da maior antecedência possível são carregados
+	auctions, err := rs.auctionRepository.FindAuctionsEndingBetween(ctx, now, now.Add(entity.MaxReminderMinutes*time.Minute))
+	if err != nil {
+		return err
+	}
+
+	if len(auctions) == 0 {
+		return nil
+	}
+
+	auctionsById := make(map[string]entity.Auction, len(auctions))
+	auctionIds := make([]string, 0, len(auctions))
+	for _, auction := range auctions {
+		auctionsById[auction.Id] = auction
+		auctionIds = append(auctionIds, auction.Id)
+	}
+
+	watchers, err := rs.watchlistRepository.FindWatchersByAuctionIds(ctx, auctionIds)
+	if err != nil {
+		return err
+	}
+
+	if len(watchers) == 0 {
+		return nil
+	}
+
+	highestBids, err := rs.bidRepository.FindHighestBidsByAuctionIds(ctx, auctionIds)
+	if err != nil {
+		return err
+	}
+
+	preferences := make(map[string]*entity.NotificationPreference)
+	for _, watcher := range watchers {
+		preference, ok := preferences[watcher.UserId]
+		if !ok {
+			preference, err = rs.preferenceRepository.FindPreferenceByUserId(ctx, watcher.UserId)
+			if err != nil {
+				rs.logger.ErrorContext(ctx, "Error loading notification preferences", logging.UserId(watcher.UserId), logging.Err(err))
+				continue
+			}
+			if preference == nil {
+				preference = &entity.NotificationPreference{UserId: watcher.UserId}
+			}
+			preferences[watcher.UserId] = preference
+		}
+
+		auction := auctionsById[watcher.AuctionId]
+		minutes, due := dueReminder(preference.Reminders(), auction.ExpiresAt.Sub(now))
+		if !due {
+			continue
+		}
+
+		data := TemplateData{
+			AuctionId:   auction.Id,
+			ProductName: auction.ProductName,
+			Amount:      highestBids[auction.Id].Amount,
+			Minutes:     minutes,
+		}
+
+		dedupKey := fmt.Sprintf("%s:%s:%s:%d", entity.EndingSoonNotification, watcher.UserId, auction.Id, minutes)
+		if err := rs.dispatcher.Notify(ctx, watcher.UserId, entity.EndingSoonNotification, dedupKey, data); err != nil {
+			rs.logger.ErrorContext(ctx, "Error sending ending-soon reminder",
+				logging.AuctionId(auction.Id), logging.UserId(watcher.UserId), logging.Err(err))
+		}
+	}
+
+	return nil
+}
+
+// dueReminder escolhe a menor antecedência configurada que já foi atingida, para que
+// um leilão adicionado perto do fim não dispare todos os lembretes de uma vez
+func dueReminder(reminderMinutes []int, timeLeft time.Duration) (int, bool) {
+	if timeLeft <= 0 {
+		return 0, false
+	}
+
+	sorted := append([]int(nil), reminderMinutes...)
+	sort.Ints(sorted)
+
+	for _, minutes := range sorted {
+		if timeLeft <= time.Duration(minutes)*time.Minute {
+			return minutes, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDueReminder(t *testing.T) {
+	tests := []struct {
+		name     string
+		reminder []int
+		timeLeft time.Duration
+		minutes  int
+		due      bool
+	}{
+		{name: "Outside every window", reminder: []int{10, 5}, timeLeft: 20 * time.Minute, due: false},
+		{name: "Inside largest window", reminder: []int{10, 5}, timeLeft: 9 * time.Minute, minutes: 10, due: true},
+		{name: "Inside smallest window", reminder: []int{5, 10}, timeLeft: 4 * time.Minute, minutes: 5, due: true},
+		{name: "Already expired", reminder: []int{10}, timeLeft: -time.Second, due: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minutes, due := dueReminder(tt.reminder, tt.timeLeft)
+			assert.Equal(t, tt.due, due)
+			assert.Equal(t, tt.minutes, minutes)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package notifier
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/auction-goexpert/internal/entity"
+)
+
+const (
+	LocalePtBR = "pt-BR"
+	LocaleEn   = "en"
+)
+
+// TemplateData são os dados disponíveis para os templates de notificação
+type TemplateData struct {
+	AuctionId   string
+	ProductName string
+	Amount      entity.Money
+	Minutes     int
+}
+
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+var templateSources = map[string]map[entity.NotificationType][2]string{
+	LocalePtBR: {
+		entity.OutbidNotification: {
+			"Seu lance em {{.ProductName}} foi superado",
+			"Alguém ofereceu {{money .Amount}} pelo leilão {{.ProductName}}. Faça um novo lance para continuar na disputa.",
+		},
+		entity.AuctionWonNotification: {
+			"Você venceu o leilão de {{.ProductName}}",
+			"Parabéns! Seu lance de {{money .Amount}} venceu o leilão {{.ProductName}}.",
+		},
+		entity.EndingSoonNotification: {
+			"O leilão de {{.ProductName}} termina em {{.Minutes}} minutos",
+			"O leilão {{.ProductName}} que você acompanha termina em {{.Minutes}} minutos. Maior lance atual: {{money .Amount}}.",
+		},
+		entity.PaymentRequestedNotification: {
+			"{{.ProductName}} está disponível para você",
+			"O vencedor do leilão {{.ProductName}} não pagou no prazo. Você pode arrematar o item pelo seu lance de {{money .Amount}}; confirme o pagamento antes que o novo prazo termine.",
+		},
+		entity.SecondChanceNotification: {
+			"Oferta de segunda chance para {{.ProductName}}",
+			"O vendedor do leilão {{.ProductName}} oferece o item a você pelo seu lance de {{money .Amount}}. Aceite ou recuse a oferta antes que ela expire.",
+		},
+	},
+	LocaleEn: {
+		entity.OutbidNotification: {
+			"You have been outbid on {{.ProductName}}",
+			"Someone bid {{money .Amount}} on {{.ProductName}}. Place a new bid to stay in the race.",
+		},
+		entity.AuctionWonNotification: {
+			"You won the auction for {{.ProductName}}",
+			"Congratulations! Your bid of {{money .Amount}} won the auction for {{.ProductName}}.",
+		},
+		entity.EndingSoonNotification: {
+			"The auction for {{.ProductName}} ends in {{.Minutes}} minutes",
+			"The auction for {{.ProductName}} you are watching ends in {{.Minutes}} minutes. Current highest bid: {{money .Amount}}.",
+		},
+		entity.PaymentRequestedNotification: {
+			"{{.ProductName}} is available to you",
+			"The winner of the auction for {{.ProductName}} did not pay in time. You can buy the item for your bid of {{money .Amount}}; confirm the payment before the new deadline.",
+		},
+		entity.SecondChanceNotification: {
+			"Second-chance offer for {{.ProductName}}",
+			"The seller of {{.ProductName}} is offering you the item for your bid of {{money .Amount}}. Accept or decline the offer before it expires.",
+		},
+	},
+}
+
+// Templates renderiza as notificações no idioma do usuário
+type Templates struct {
+	defaultLocale string
+	templates     map[string]map[entity.NotificationType]messageTemplate
+}
+
+func NewTemplates(defaultLocale string) (*Templates, error) {
+	t := &Templates{
+		defaultLocale: LocalePtBR,
+		templates:     make(map[string]map[entity.NotificationType]messageTemplate),
+	}
+
+	for locale, sources := range templateSources {
+		funcs := template.FuncMap{"money": moneyFormatter(locale)}
+		t.templates[locale] = make(map[entity.NotificationType]messageTemplate)
+
+		for notificationType, source := range sources {
+			name := locale + "/" + string(notificationType)
+
+			subject, err := template.New(name + "/subject").Funcs(funcs).Parse(source[0])
+			if err != nil {
+				return nil, err
+			}
+
+			body, err := template.New(name + "/body").Funcs(funcs).Parse(source[1])
+			if err != nil {
+				return nil, err
+			}
+
+			t.templates[locale][notificationType] = messageTemplate{subject: subject, body: body}
+		}
+	}
+
+	if defaultLocale != "" {
+		resolved := t.resolveLocale(defaultLocale)
+		if resolved == "" {
+			return nil, fmt.Errorf("unsupported default locale %q", defaultLocale)
+		}
+		t.defaultLocale = resolved
+	}
+
+	return t, nil
+}
+
+// Render gera assunto e corpo da notificação no idioma informado
+func (t *Templates) Render(locale string, notificationType entity.NotificationType, data TemplateData) (string, string, error) {
+	resolved := t.resolveLocale(locale)
+	if resolved == "" {
+		resolved = t.defaultLocale
+	}
+
+	tmpl, ok := t.templates[resolved][notificationType]
+	if !ok {
+		return "", "", fmt.Errorf("no template for notification type %s", notificationType)
+	}
+
+	var subject, body strings.Builder
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+
+	return subject.String(), body.String(), nil
+}
+
+// resolveLocale aceita variações como "pt-br", "pt" ou "en-US"; retorna "" se não houver suporte
+func (t *Templates) resolveLocale(locale string) string {
+	if locale == "" {
+		return ""
+	}
+
+	for supported := range t.templates {
+		if strings.EqualFold(supported, locale) {
+			return supported
+		}
+	}
+
+	language := strings.ToLower(strings.SplitN(strings.ReplaceAll(locale, "_", "-"), "-", 2)[0])
+	for supported := range t.templates {
+		if strings.ToLower(strings.SplitN(supported, "-", 2)[0]) == language {
+			return supported
+		}
+	}
+
+	return ""
+}
+
+// moneyFormatter formata valores com o símbolo da moeda e os separadores do idioma
+func moneyFormatter(locale string) func(entity.Money) string {
+	return func(amount entity.Money) string {
+		cents := amount.Cents
+		negative := cents < 0
+		if negative {
+			cents = -cents
+		}
+
+		integer := strconv.FormatInt(cents/100, 10)
+		fraction := fmt.Sprintf("%02d", cents%100)
+
+		thousands, decimal := ",", "."
+		if locale == LocalePtBR {
+			thousands, decimal = ".", ","
+		}
+
+		var grouped strings.Builder
+		for i, digit := range integer {
+			if i > 0 && (len(integer)-i)%3 == 0 {
+				grouped.WriteString(thousands)
+			}
+			grouped.WriteRune(digit)
+		}
+
+		sign := ""
+		if negative {
+			sign = "-"
+		}
+
+		return sign + currencySymbol(locale, amount.Currency) + grouped.String() + decimal + fraction
+	}
+}
+
+// currencySymbol usa o símbolo curto para a moeda local do idioma e o prefixado pelo país nas demais
+func currencySymbol(locale string, currency entity.Currency) string {
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+
+	switch {
+	case currency == entity.BRL && locale == LocalePtBR:
+		return "R$ "
+	case currency == entity.BRL:
+		return "R$"
+	case currency == entity.USD && locale == LocalePtBR:
+		return "US$ "
+	case currency == entity.USD:
+		return "$"
+	default:
+		return string(currency) + " "
+	}
+}
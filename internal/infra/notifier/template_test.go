@@ -0,0 +1,44 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderLocalizedTemplates(t *testing.T) {
+	templates, err := NewTemplates(LocalePtBR)
+	assert.NoError(t, err)
+
+	data := TemplateData{
+		AuctionId:   "auction-1",
+		ProductName: "iPhone 13",
+		Amount:      entity.NewMoney(250050, entity.USD),
+	}
+
+	subject, body, err := templates.Render("en-US", entity.OutbidNotification, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "You have been outbid on iPhone 13", subject)
+	assert.Contains(t, body, "$2,500.50")
+
+	data.Amount = entity.NewMoney(250050, entity.BRL)
+	subject, body, err = templates.Render("pt-br", entity.AuctionWonNotification, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "Você venceu o leilão de iPhone 13", subject)
+	assert.Contains(t, body, "R$ 2.500,50")
+}
+
+func TestRenderFallsBackToDefaultLocale(t *testing.T) {
+	templates, err := NewTemplates(LocaleEn)
+	assert.NoError(t, err)
+
+	subject, _, err := templates.Render("fr", entity.AuctionWonNotification, TemplateData{ProductName: "Bike"})
+	assert.NoError(t, err)
+	assert.Equal(t, "You won the auction for Bike", subject)
+}
+
+func TestNewTemplatesRejectsUnsupportedDefaultLocale(t *testing.T) {
+	_, err := NewTemplates("fr-FR")
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/infra/logging"
+	"github.com/auction-goexpert/internal/infra/tracing"
+	"github.com/auction-goexpert/internal/usecase/settlement_usecase"
+)
+
+// SettlementScheduler abre o acerto dos leilões encerrados e verifica periodicamente os
+// pagamentos vencidos, passando o item ao próximo maior lance
+type SettlementScheduler struct {
+	settlementUseCase *settlement_usecase.SettlementUseCase
+	checkInterval     time.Duration
+	logger            *slog.Logger
+}
+
+func NewSettlementScheduler(settlementUseCase *settlement_usecase.SettlementUseCase, checkInterval time.Duration, logger *slog.Logger) *SettlementScheduler {
+	return &SettlementScheduler{
+		settlementUseCase: settlementUseCase,
+		checkInterval:     checkInterval,
+		logger:            logger,
+	}
+}
+
+// OnAuctionClosed é registrado como AuctionClosedListener no repositório de leilões; uma
+// falha ao abrir o acerto é retomada com o encerramento
+func (ss *SettlementScheduler) OnAuctionClosed(ctx context.Context, auction entity.Auction) error {
+	if err := ss.settlementUseCase.OnAuctionClosed(ctx, auction); err != nil {
+		return fmt.Errorf("opening auction settlement: %w", err)
+	}
+	return nil
+}
+
+// Start inicia a goroutine que expira os pagamentos vencidos
+func (ss *SettlementScheduler) Start() {
+	go ss.run()
+}
+
+func (ss *SettlementScheduler) run() {
+	ticker := time.NewTicker(ss.checkInterval)
+	defer ticker.Stop()
+
+	ss.logger.Info("Settlement payment deadline scheduler started", slog.String("interval", ss.checkInterval.String()))
+
+	for range ticker.C {
+		ctx, span := tracing.Start(context.Background(), "SettlementScheduler.Run")
+		err := ss.settlementUseCase.ExpireOverduePayments(ctx, time.Now())
+		if err != nil {
+			ss.logger.ErrorContext(ctx, "Error expiring overdue settlement payments", logging.Err(err))
+		}
+		tracing.End(span, err)
+	}
+}
@@ -0,0 +1,24 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// untracedPaths são as rotas consultadas a todo instante por orquestradores e pelo
+// Prometheus, que só gerariam ruído nos traces
+var untracedPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// Middleware abre o span de cada requisição HTTP, continuando o trace recebido no
+// cabeçalho traceparent. O span é nomeado pelo padrão da rota.
+func Middleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !untracedPaths[r.URL.Path]
+	}))
+}
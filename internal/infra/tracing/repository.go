@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/auction-goexpert/internal/infra/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Repository instrumenta um método de repositório: abre o span
+// "<Nome>Repository.<método>" e mede a duração no histograma dos repositórios. Use com
+//
+//	ctx, done := tracing.Repository(ctx, "auction", "CreateAuction")
+//	defer done()
+//
+// para que as consultas feitas com ctx (e outros repositórios chamados com ele)
+// apareçam como filhas do span.
+func Repository(ctx context.Context, repository, method string) (context.Context, func()) {
+	timer := metrics.RepositoryOperation(repository, method)
+	ctx, span := Start(ctx, repositoryName(repository)+"."+method,
+		trace.WithAttributes(attribute.String("db.system", "mongodb")))
+
+	return ctx, func() {
+		span.End()
+		timer.ObserveDuration()
+	}
+}
+
+// repositoryName converte o rótulo das métricas ("notification_preference") no nome
+// do tipo ("NotificationPreferenceRepository")
+func repositoryName(repository string) string {
+	var name strings.Builder
+	for _, part := range strings.Split(repository, "_") {
+		if part == "" {
+			continue
+		}
+		name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	name.WriteString("Repository")
+	return name.String()
+}
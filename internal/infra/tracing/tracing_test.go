@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/auction-goexpert/configuration/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	incomingTraceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+	incomingSpanId  = "00f067aa0ba902b7"
+)
+
+func TestMiddlewareContinuesIncomingTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	_, err := Setup(context.Background(), config.TracingConfig{Exporter: "none"})
+	assert.Nil(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware("auction-test"))
+	router.POST("/v1/bid", func(c *gin.Context) {
+		ctx, done := Repository(c.Request.Context(), "bid", "CreateBid")
+		_, nestedDone := Repository(ctx, "auction", "FindAuctionById")
+		nestedDone()
+		done()
+		c.Status(http.StatusCreated)
+	})
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := httptest.NewRequest(http.MethodPost, "/v1/bid", nil)
+	request.Header.Set("traceparent", "00-"+incomingTraceId+"-"+incomingSpanId+"-01")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spans {
+		assert.Equal(t, incomingTraceId, span.SpanContext().TraceID().String())
+		byName[span.Name()] = span
+	}
+
+	server, repository, nested := byName["/v1/bid"], byName["BidRepository.CreateBid"], byName["AuctionRepository.FindAuctionById"]
+	if assert.NotNil(t, server) && assert.NotNil(t, repository) && assert.NotNil(t, nested) {
+		assert.Equal(t, incomingSpanId, server.Parent().SpanID().String())
+		assert.Equal(t, server.SpanContext().SpanID(), repository.Parent().SpanID())
+		assert.Equal(t, repository.SpanContext().SpanID(), nested.Parent().SpanID())
+	}
+}
+
+func TestRepositoryName(t *testing.T) {
+	assert.Equal(t, "AuctionRepository", repositoryName("auction"))
+	assert.Equal(t, "NotificationPreferenceRepository", repositoryName("notification_preference"))
+}
@@ -1,6 +1,11 @@
 package internal_error
 
-import "net/http"
+import (
+	"errors"
+	"net/http"
+
+	"github.com/auction-goexpert/internal/entity"
+)
 
 type InternalError struct {
 	Message string
@@ -35,3 +40,51 @@ func NewNotFoundError(message string) *InternalError {
 		Code:    http.StatusNotFound,
 	}
 }
+
+func NewUnauthorizedError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "unauthorized",
+		Code:    http.StatusUnauthorized,
+	}
+}
+
+func NewConflictError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "conflict",
+		Code:    http.StatusConflict,
+	}
+}
+
+// statusByKind define o status HTTP de cada tipo de erro de domínio
+var statusByKind = map[entity.ErrorKind]int{
+	entity.InvalidInputError:      http.StatusBadRequest,
+	entity.NotFoundError:          http.StatusNotFound,
+	entity.AuctionClosedError:     http.StatusConflict,
+	entity.BidTooLowError:         http.StatusUnprocessableEntity,
+	entity.InsufficientFundsError: http.StatusUnprocessableEntity,
+	entity.UnauthorizedError:      http.StatusUnauthorized,
+	entity.ForbiddenError:         http.StatusForbidden,
+	entity.ConflictError:          http.StatusConflict,
+}
+
+// FromError converte um erro de domínio no status e código correspondentes;
+// qualquer outro erro é tratado como erro interno
+func FromError(err error) *InternalError {
+	var domainErr *entity.DomainError
+	if !errors.As(err, &domainErr) {
+		return NewInternalServerError(err.Error())
+	}
+
+	code, ok := statusByKind[domainErr.Kind]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+
+	return &InternalError{
+		Message: err.Error(),
+		Err:     domainErr.Code,
+		Code:    code,
+	}
+}
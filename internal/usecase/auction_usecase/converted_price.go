@@ -0,0 +1,48 @@
+package auction_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+)
+
+// ConvertedPriceOutputDTO é o preço atual convertido para a moeda de exibição pedida
+type ConvertedPriceOutputDTO struct {
+	Amount      entity.Money    `json:"amount"`
+	Currency    entity.Currency `json:"currency"`
+	Rate        string          `json:"rate"`
+	EffectiveAt time.Time       `json:"effective_at"`
+}
+
+// convertPrices preenche o preço convertido de cada leilão, buscando uma cotação por moeda de origem
+func (au *FindAuctionUseCase) convertPrices(ctx context.Context, output []AuctionOutputDTO, displayCurrency entity.Currency) *internal_error.InternalError {
+	if displayCurrency == "" {
+		return nil
+	}
+
+	now := time.Now()
+	rates := make(map[entity.Currency]*entity.ExchangeRate)
+
+	for i := range output {
+		rate, ok := rates[output[i].Currency]
+		if !ok {
+			var err error
+			rate, err = au.exchangeRateRepository.FindEffectiveRate(ctx, output[i].Currency, displayCurrency, now)
+			if err != nil {
+				return internal_error.FromError(err)
+			}
+			rates[output[i].Currency] = rate
+		}
+
+		output[i].ConvertedPrice = &ConvertedPriceOutputDTO{
+			Amount:      rate.Convert(output[i].CurrentPrice),
+			Currency:    displayCurrency,
+			Rate:        rate.RateString(),
+			EffectiveAt: rate.EffectiveAt,
+		}
+	}
+
+	return nil
+}
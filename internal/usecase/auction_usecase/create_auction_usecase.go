@@ -6,24 +6,38 @@ import (
 
 	"github.com/auction-goexpert/internal/entity"
 	"github.com/auction-goexpert/internal/internal_error"
+	"go.opentelemetry.io/otel"
 )
 
+// tracer abre os spans dos use cases, filhos do span da requisição
+var tracer = otel.Tracer("github.com/auction-goexpert/internal/usecase/auction_usecase")
+
 type AuctionInputDTO struct {
-	ProductName string                `json:"product_name" binding:"required,min=1"`
-	Category    string                `json:"category" binding:"required,min=2"`
-	Description string                `json:"description" binding:"required,min=10,max=200"`
+	ProductName string                  `json:"product_name" binding:"required,min=1"`
+	Category    string                  `json:"category" binding:"required,min=2"`
+	Description string                  `json:"description" binding:"required,min=10,max=200"`
 	Condition   entity.ProductCondition `json:"condition" binding:"oneof=0 1 2"`
+	SellerId    string                  `json:"seller_id"`
+	Currency    entity.Currency         `json:"currency" binding:"omitempty,oneof=BRL USD"`
+	// ReservePrice é o preço mínimo de venda, na moeda do leilão; vazio significa sem reserva
+	ReservePrice entity.Money `json:"reserve_price"`
 }
 
 type AuctionOutputDTO struct {
-	Id          string                `json:"id"`
-	ProductName string                `json:"product_name"`
-	Category    string                `json:"category"`
-	Description string                `json:"description"`
-	Condition   entity.ProductCondition `json:"condition"`
-	Status      entity.AuctionStatus    `json:"status"`
-	Timestamp   time.Time             `json:"timestamp"`
-	ExpiresAt   time.Time             `json:"expires_at"`
+	Id             string                   `json:"id"`
+	ProductName    string                   `json:"product_name"`
+	Category       string                   `json:"category"`
+	Description    string                   `json:"description"`
+	Condition      entity.ProductCondition  `json:"condition"`
+	Status         entity.AuctionStatus     `json:"status"`
+	CurrentPrice   entity.Money             `json:"current_price"`
+	Currency       entity.Currency          `json:"currency"`
+	SellerId       string                   `json:"seller_id,omitempty"`
+	Timestamp      time.Time                `json:"timestamp"`
+	ExpiresAt      time.Time                `json:"expires_at"`
+	ConvertedPrice *ConvertedPriceOutputDTO `json:"converted_price,omitempty"`
+	// ReserveMet só aparece quando o leilão tem preço de reserva, que não é divulgado
+	ReserveMet *bool `json:"reserve_met,omitempty"`
 }
 
 type CreateAuctionUseCase struct {
@@ -37,6 +51,9 @@ func NewCreateAuctionUseCase(auctionRepository entity.AuctionRepositoryInterface
 }
 
 func (au *CreateAuctionUseCase) Execute(ctx context.Context, input AuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "CreateAuctionUseCase.Execute")
+	defer span.End()
+
 	auction, err := entity.CreateAuction(
 		input.ProductName,
 		input.Category,
@@ -45,21 +62,45 @@ func (au *CreateAuctionUseCase) Execute(ctx context.Context, input AuctionInputD
 		0, // Duration será calculada no repository
 	)
 	if err != nil {
-		return nil, internal_error.NewInternalServerError(err.Error())
+		return nil, internal_error.FromError(err)
 	}
 
+	auction.SellerId = input.SellerId
+	if input.Currency != "" {
+		auction.Currency = input.Currency
+		auction.CurrentPrice = entity.NewMoney(0, input.Currency)
+	}
+
+	if input.ReservePrice.Cents < 0 {
+		return nil, internal_error.FromError(entity.ErrInvalidReservePrice)
+	}
+	auction.ReservePrice = entity.NewMoney(input.ReservePrice.Cents, auction.Currency)
+
 	if err := au.auctionRepository.CreateAuction(ctx, auction); err != nil {
-		return nil, internal_error.NewInternalServerError(err.Error())
+		return nil, internal_error.FromError(err)
 	}
 
 	return &AuctionOutputDTO{
-		Id:          auction.Id,
-		ProductName: auction.ProductName,
-		Category:    auction.Category,
-		Description: auction.Description,
-		Condition:   auction.Condition,
-		Status:      auction.Status,
-		Timestamp:   auction.Timestamp,
-		ExpiresAt:   auction.ExpiresAt,
+		Id:           auction.Id,
+		ProductName:  auction.ProductName,
+		Category:     auction.Category,
+		Description:  auction.Description,
+		Condition:    auction.Condition,
+		Status:       auction.Status,
+		CurrentPrice: auction.CurrentPrice,
+		Currency:     auction.Currency,
+		SellerId:     auction.SellerId,
+		Timestamp:    auction.Timestamp,
+		ExpiresAt:    auction.ExpiresAt,
+		ReserveMet:   reserveMet(*auction),
 	}, nil
 }
+
+// reserveMet retorna nil para leilões sem preço de reserva
+func reserveMet(auction entity.Auction) *bool {
+	if !auction.HasReserve() {
+		return nil
+	}
+	met := auction.ReserveMet()
+	return &met
+}
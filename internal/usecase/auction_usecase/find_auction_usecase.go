@@ -8,56 +8,86 @@ import (
 )
 
 type FindAuctionUseCase struct {
-	auctionRepository entity.AuctionRepositoryInterface
+	auctionRepository      entity.AuctionRepositoryInterface
+	exchangeRateRepository entity.ExchangeRateRepositoryInterface
 }
 
-func NewFindAuctionUseCase(auctionRepository entity.AuctionRepositoryInterface) *FindAuctionUseCase {
+func NewFindAuctionUseCase(
+	auctionRepository entity.AuctionRepositoryInterface,
+	exchangeRateRepository entity.ExchangeRateRepositoryInterface,
+) *FindAuctionUseCase {
 	return &FindAuctionUseCase{
-		auctionRepository: auctionRepository,
+		auctionRepository:      auctionRepository,
+		exchangeRateRepository: exchangeRateRepository,
 	}
 }
 
 func (au *FindAuctionUseCase) FindAuctionById(ctx context.Context, id string) (*AuctionOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "FindAuctionUseCase.FindAuctionById")
+	defer span.End()
+
 	auction, err := au.auctionRepository.FindAuctionById(ctx, id)
 	if err != nil {
-		return nil, internal_error.NewInternalServerError(err.Error())
+		return nil, internal_error.FromError(err)
 	}
 
 	if auction == nil {
-		return nil, internal_error.NewNotFoundError("auction not found")
+		return nil, internal_error.FromError(entity.ErrAuctionNotFound)
 	}
 
 	return &AuctionOutputDTO{
-		Id:          auction.Id,
-		ProductName: auction.ProductName,
-		Category:    auction.Category,
-		Description: auction.Description,
-		Condition:   auction.Condition,
-		Status:      auction.Status,
-		Timestamp:   auction.Timestamp,
-		ExpiresAt:   auction.ExpiresAt,
+		Id:           auction.Id,
+		ProductName:  auction.ProductName,
+		Category:     auction.Category,
+		Description:  auction.Description,
+		Condition:    auction.Condition,
+		Status:       auction.Status,
+		CurrentPrice: auction.CurrentPrice,
+		Currency:     auction.Currency,
+		SellerId:     auction.SellerId,
+		Timestamp:    auction.Timestamp,
+		ExpiresAt:    auction.ExpiresAt,
+		ReserveMet:   reserveMet(*auction),
 	}, nil
 }
 
-func (au *FindAuctionUseCase) FindAuctions(ctx context.Context, status entity.AuctionStatus, category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError) {
-	auctions, err := au.auctionRepository.FindAuctions(ctx, status, category, productName)
+// FindAuctions retorna uma página de leilões e o cursor da próxima página ("" na última).
+// Com displayCurrency, inclui o preço atual convertido pela cotação vigente.
+func (au *FindAuctionUseCase) FindAuctions(ctx context.Context, status entity.AuctionStatus, category, productName string, displayCurrency entity.Currency, page entity.PageRequest) ([]AuctionOutputDTO, string, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "FindAuctionUseCase.FindAuctions")
+	defer span.End()
+
+	page = page.WithDefaults("expires_at", entity.Ascending)
+	if !page.IsSortedBy(entity.AuctionSortFields) {
+		return nil, "", internal_error.NewBadRequestError("invalid sort, allowed fields: expires_at, timestamp, current_price")
+	}
+
+	auctions, nextCursor, err := au.auctionRepository.FindAuctions(ctx, status, category, productName, page)
 	if err != nil {
-		return nil, internal_error.NewInternalServerError(err.Error())
+		return nil, "", internal_error.FromError(err)
 	}
 
-	var output []AuctionOutputDTO
+	output := []AuctionOutputDTO{}
 	for _, auction := range auctions {
 		output = append(output, AuctionOutputDTO{
-			Id:          auction.Id,
-			ProductName: auction.ProductName,
-			Category:    auction.Category,
-			Description: auction.Description,
-			Condition:   auction.Condition,
-			Status:      auction.Status,
-			Timestamp:   auction.Timestamp,
-			ExpiresAt:   auction.ExpiresAt,
+			Id:           auction.Id,
+			ProductName:  auction.ProductName,
+			Category:     auction.Category,
+			Description:  auction.Description,
+			Condition:    auction.Condition,
+			Status:       auction.Status,
+			CurrentPrice: auction.CurrentPrice,
+			Currency:     auction.Currency,
+			SellerId:     auction.SellerId,
+			Timestamp:    auction.Timestamp,
+			ExpiresAt:    auction.ExpiresAt,
+			ReserveMet:   reserveMet(auction),
 		})
 	}
 
-	return output, nil
+	if internalErr := au.convertPrices(ctx, output, displayCurrency); internalErr != nil {
+		return nil, "", internalErr
+	}
+
+	return output, nextCursor, nil
 }
@@ -0,0 +1,159 @@
+package auction_usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+)
+
+type AuctionSearchInputDTO struct {
+	Query        string                    `form:"q" binding:"max=200"`
+	Categories   []string                  `form:"category"`
+	Conditions   []entity.ProductCondition `form:"condition" binding:"dive,oneof=0 1 2"`
+	Status       *entity.AuctionStatus     `form:"status" binding:"omitempty,oneof=0 1 2"`
+	SellerId     string                    `form:"seller"`
+	Currency     entity.Currency           `form:"currency" binding:"omitempty,oneof=BRL USD"`
+	MinPrice     string                    `form:"minPrice"`
+	MaxPrice     string                    `form:"maxPrice"`
+	EndingAfter  *time.Time                `form:"endingAfter" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndingBefore *time.Time                `form:"endingBefore" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	DisplayCurrency entity.Currency `form:"displayCurrency" binding:"omitempty,oneof=BRL USD"`
+}
+
+// SearchAuctions busca leilões por texto e filtros. Sem ordenação explícita, usa
+// relevância quando há texto e os leilões que terminam primeiro caso contrário.
+func (au *FindAuctionUseCase) SearchAuctions(ctx context.Context, input AuctionSearchInputDTO, page entity.PageRequest) ([]AuctionOutputDTO, string, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "FindAuctionUseCase.SearchAuctions")
+	defer span.End()
+
+	// A faixa de preço é na moeda filtrada; sem moeda, vale a padrão e filtra por ela
+	currency := input.Currency
+	if currency == "" && (input.MinPrice != "" || input.MaxPrice != "") {
+		currency = entity.DefaultCurrency
+	}
+
+	minPrice, err := parsePriceFilter(input.MinPrice, currency)
+	if err != nil {
+		return nil, "", internal_error.NewBadRequestError("invalid minPrice: " + err.Error())
+	}
+
+	maxPrice, err := parsePriceFilter(input.MaxPrice, currency)
+	if err != nil {
+		return nil, "", internal_error.NewBadRequestError("invalid maxPrice: " + err.Error())
+	}
+
+	if minPrice != nil && maxPrice != nil && minPrice.GreaterThan(*maxPrice) {
+		return nil, "", internal_error.NewBadRequestError("minPrice must not be greater than maxPrice")
+	}
+
+	if input.EndingAfter != nil && input.EndingBefore != nil && input.EndingAfter.After(*input.EndingBefore) {
+		return nil, "", internal_error.NewBadRequestError("endingAfter must not be later than endingBefore")
+	}
+
+	query := strings.TrimSpace(input.Query)
+
+	if page.SortBy == "" {
+		page.SortBy = entity.SortByEndingSoon
+		if query != "" {
+			page.SortBy = entity.SortByRelevance
+		}
+	}
+
+	switch page.SortBy {
+	case entity.SortByRelevance:
+		if query == "" {
+			return nil, "", internal_error.NewBadRequestError("sort by relevance requires the q parameter")
+		}
+		page.Order = entity.Descending
+	case entity.SortByEndingSoon:
+		page.SortBy = "expires_at"
+		page.Order = entity.Ascending
+	}
+
+	page = page.WithDefaults("expires_at", entity.Ascending)
+	if page.SortBy != entity.SortByRelevance && !page.IsSortedBy(entity.AuctionSortFields) {
+		return nil, "", internal_error.NewBadRequestError("invalid sort, allowed fields: relevance, ending_soon, expires_at, timestamp, current_price")
+	}
+
+	// Aceita tanto category=a&category=b quanto category=a,b
+	var categories []string
+	for _, category := range input.Categories {
+		for _, value := range strings.Split(category, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				categories = append(categories, value)
+			}
+		}
+	}
+
+	filter := entity.AuctionSearchFilter{
+		Text:         query,
+		Status:       input.Status,
+		Categories:   categories,
+		Conditions:   input.Conditions,
+		SellerId:     input.SellerId,
+		Currency:     currencyFilter(currency),
+		MinPrice:     minPrice,
+		MaxPrice:     maxPrice,
+		EndingAfter:  input.EndingAfter,
+		EndingBefore: input.EndingBefore,
+	}
+
+	auctions, nextCursor, err := au.auctionRepository.SearchAuctions(ctx, filter, page)
+	if err != nil {
+		return nil, "", internal_error.FromError(err)
+	}
+
+	output := []AuctionOutputDTO{}
+	for _, auction := range auctions {
+		output = append(output, AuctionOutputDTO{
+			Id:           auction.Id,
+			ProductName:  auction.ProductName,
+			Category:     auction.Category,
+			Description:  auction.Description,
+			Condition:    auction.Condition,
+			Status:       auction.Status,
+			CurrentPrice: auction.CurrentPrice,
+			Currency:     auction.Currency,
+			SellerId:     auction.SellerId,
+			Timestamp:    auction.Timestamp,
+			ExpiresAt:    auction.ExpiresAt,
+			ReserveMet:   reserveMet(auction),
+		})
+	}
+
+	if internalErr := au.convertPrices(ctx, output, input.DisplayCurrency); internalErr != nil {
+		return nil, "", internalErr
+	}
+
+	return output, nextCursor, nil
+}
+
+// parsePriceFilter converte o filtro de preço da query string; vazio significa sem filtro
+func parsePriceFilter(value string, currency entity.Currency) (*entity.Money, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	price, err := entity.ParseMoney(value, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if price.Cents < 0 {
+		return nil, errors.New("must not be negative")
+	}
+
+	return &price, nil
+}
+
+func currencyFilter(currency entity.Currency) *entity.Currency {
+	if currency == "" {
+		return nil
+	}
+	return &currency
+}
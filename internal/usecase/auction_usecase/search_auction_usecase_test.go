@@ -0,0 +1,143 @@
+package auction_usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchRepository guarda o filtro e a página recebidos pela busca
+type searchRepository struct {
+	entity.AuctionRepositoryInterface
+	filter entity.AuctionSearchFilter
+	page   entity.PageRequest
+}
+
+func (r *searchRepository) SearchAuctions(ctx context.Context, search entity.AuctionSearchFilter, page entity.PageRequest) ([]entity.Auction, string, error) {
+	r.filter, r.page = search, page
+	return nil, "", nil
+}
+
+func TestSearchAuctions(t *testing.T) {
+	earlier := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	brl, usd := entity.BRL, entity.USD
+
+	tests := []struct {
+		name    string
+		input   AuctionSearchInputDTO
+		page    entity.PageRequest
+		problem string
+		check   func(t *testing.T, filter entity.AuctionSearchFilter, page entity.PageRequest)
+	}{
+		{
+			name:  "comma separated categories",
+			input: AuctionSearchInputDTO{Categories: []string{"phones, laptops", "tablets", " ,"}},
+			check: func(t *testing.T, filter entity.AuctionSearchFilter, page entity.PageRequest) {
+				assert.Equal(t, []string{"phones", "laptops", "tablets"}, filter.Categories)
+			},
+		},
+		{
+			name:    "min price greater than max price",
+			input:   AuctionSearchInputDTO{MinPrice: "100.00", MaxPrice: "99.99"},
+			problem: "minPrice must not be greater than maxPrice",
+		},
+		{
+			name:    "negative price",
+			input:   AuctionSearchInputDTO{MinPrice: "-1"},
+			problem: "invalid minPrice: must not be negative",
+		},
+		{
+			name:    "ending after later than ending before",
+			input:   AuctionSearchInputDTO{EndingAfter: &later, EndingBefore: &earlier},
+			problem: "endingAfter must not be later than endingBefore",
+		},
+		{
+			name:    "relevance without q",
+			input:   AuctionSearchInputDTO{Query: "   "},
+			page:    entity.PageRequest{SortBy: entity.SortByRelevance},
+			problem: "sort by relevance requires the q parameter",
+		},
+		{
+			name:  "relevance by default with q",
+			input: AuctionSearchInputDTO{Query: " iphone "},
+			check: func(t *testing.T, filter entity.AuctionSearchFilter, page entity.PageRequest) {
+				assert.Equal(t, "iphone", filter.Text)
+				assert.Equal(t, entity.SortByRelevance, page.SortBy)
+				assert.Equal(t, entity.Descending, page.Order)
+			},
+		},
+		{
+			name:  "ending soon by default without q",
+			input: AuctionSearchInputDTO{},
+			check: func(t *testing.T, filter entity.AuctionSearchFilter, page entity.PageRequest) {
+				assert.Equal(t, "expires_at", page.SortBy)
+				assert.Equal(t, entity.Ascending, page.Order)
+			},
+		},
+		{
+			name:  "ending soon sorts by expires_at ascending",
+			input: AuctionSearchInputDTO{Query: "iphone"},
+			page:  entity.PageRequest{SortBy: entity.SortByEndingSoon, Order: entity.Descending},
+			check: func(t *testing.T, filter entity.AuctionSearchFilter, page entity.PageRequest) {
+				assert.Equal(t, "expires_at", page.SortBy)
+				assert.Equal(t, entity.Ascending, page.Order)
+			},
+		},
+		{
+			name:    "unknown sort field",
+			page:    entity.PageRequest{SortBy: "seller_id"},
+			problem: "invalid sort, allowed fields: relevance, ending_soon, expires_at, timestamp, current_price",
+		},
+		{
+			name:  "price filter uses the default currency",
+			input: AuctionSearchInputDTO{MinPrice: "10.50"},
+			check: func(t *testing.T, filter entity.AuctionSearchFilter, page entity.PageRequest) {
+				require.NotNil(t, filter.Currency)
+				assert.Equal(t, brl, *filter.Currency)
+				assert.Equal(t, entity.NewMoney(1050, brl), *filter.MinPrice)
+				assert.Nil(t, filter.MaxPrice)
+			},
+		},
+		{
+			name:  "price filter in the informed currency",
+			input: AuctionSearchInputDTO{Currency: usd, MaxPrice: "20"},
+			check: func(t *testing.T, filter entity.AuctionSearchFilter, page entity.PageRequest) {
+				assert.Equal(t, usd, *filter.Currency)
+				assert.Equal(t, entity.NewMoney(2000, usd), *filter.MaxPrice)
+			},
+		},
+		{
+			name:  "no currency filter without prices",
+			input: AuctionSearchInputDTO{SellerId: "seller-1"},
+			check: func(t *testing.T, filter entity.AuctionSearchFilter, page entity.PageRequest) {
+				assert.Nil(t, filter.Currency)
+				assert.Equal(t, "seller-1", filter.SellerId)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &searchRepository{}
+			useCase := NewFindAuctionUseCase(repository, nil)
+
+			output, _, internalErr := useCase.SearchAuctions(context.Background(), tt.input, tt.page)
+			if tt.problem != "" {
+				require.NotNil(t, internalErr)
+				assert.Equal(t, http.StatusBadRequest, internalErr.Code)
+				assert.Equal(t, tt.problem, internalErr.Message)
+				return
+			}
+
+			require.Nil(t, internalErr)
+			assert.Empty(t, output)
+			tt.check(t, repository.filter, repository.page)
+		})
+	}
+}
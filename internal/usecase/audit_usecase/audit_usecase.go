@@ -0,0 +1,95 @@
+package audit_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+	"go.opentelemetry.io/otel"
+)
+
+// tracer abre os spans dos use cases, filhos do span da requisição
+var tracer = otel.Tracer("github.com/auction-goexpert/internal/usecase/audit_usecase")
+
+type AuditEntryOutputDTO struct {
+	Sequence     int64              `json:"sequence"`
+	Action       entity.AuditAction `json:"action"`
+	Actor        string             `json:"actor"`
+	Timestamp    time.Time          `json:"timestamp"`
+	Before       json.RawMessage    `json:"before,omitempty"`
+	After        json.RawMessage    `json:"after,omitempty"`
+	Reason       string             `json:"reason,omitempty"`
+	PreviousHash string             `json:"previous_hash"`
+	Hash         string             `json:"hash"`
+}
+
+type AuditVerificationOutputDTO struct {
+	Valid    bool   `json:"valid"`
+	Entries  int    `json:"entries"`
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Problem  string `json:"problem,omitempty"`
+}
+
+type AuditTrailOutputDTO struct {
+	Stream       string                     `json:"stream"`
+	Entries      []AuditEntryOutputDTO      `json:"entries"`
+	Verification AuditVerificationOutputDTO `json:"verification"`
+}
+
+type AuditUseCase struct {
+	auditRepository entity.AuditRepositoryInterface
+}
+
+func NewAuditUseCase(auditRepository entity.AuditRepositoryInterface) *AuditUseCase {
+	return &AuditUseCase{
+		auditRepository: auditRepository,
+	}
+}
+
+// FindAuditTrail retorna os registros do stream (o id de um leilão, ou "admin") junto com
+// a verificação da cadeia de hashes, conferida também contra a âncora do stream
+func (au *AuditUseCase) FindAuditTrail(ctx context.Context, stream string) (*AuditTrailOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "AuditUseCase.FindAuditTrail")
+	defer span.End()
+
+	entries, err := au.auditRepository.FindAuditTrail(ctx, stream)
+	if err != nil {
+		return nil, internal_error.NewInternalServerError("error trying to find audit trail")
+	}
+	head, err := au.auditRepository.FindAuditHead(ctx, stream)
+	if err != nil {
+		return nil, internal_error.NewInternalServerError("error trying to find audit trail")
+	}
+	if len(entries) == 0 && head == nil && stream != entity.AuditAdminStream {
+		return nil, internal_error.NewNotFoundError("no audit entries found for this auction")
+	}
+
+	verification := entity.VerifyAuditChain(entries, head)
+	output := &AuditTrailOutputDTO{
+		Stream:  stream,
+		Entries: make([]AuditEntryOutputDTO, 0, len(entries)),
+		Verification: AuditVerificationOutputDTO{
+			Valid:    verification.Valid,
+			Entries:  verification.Entries,
+			BrokenAt: verification.BrokenAt,
+			Problem:  verification.Problem,
+		},
+	}
+	for _, entry := range entries {
+		output.Entries = append(output.Entries, AuditEntryOutputDTO{
+			Sequence:     entry.Sequence,
+			Action:       entry.Action,
+			Actor:        entry.Actor,
+			Timestamp:    entry.Timestamp,
+			Before:       entry.Before,
+			After:        entry.After,
+			Reason:       entry.Reason,
+			PreviousHash: entry.PreviousHash,
+			Hash:         entry.Hash,
+		})
+	}
+
+	return output, nil
+}
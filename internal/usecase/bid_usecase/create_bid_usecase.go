@@ -2,26 +2,38 @@ package bid_usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/auction-goexpert/internal/entity"
 	"github.com/auction-goexpert/internal/internal_error"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer abre os spans dos use cases, filhos do span da requisição
+var tracer = otel.Tracer("github.com/auction-goexpert/internal/usecase/bid_usecase")
+
 type BidInputDTO struct {
-	UserId    string  `json:"user_id" binding:"required"`
-	AuctionId string  `json:"auction_id" binding:"required"`
-	Amount    float64 `json:"amount" binding:"required,gt=0"`
+	UserId    string          `json:"user_id" binding:"required"`
+	AuctionId string          `json:"auction_id" binding:"required"`
+	Amount    entity.Money    `json:"amount"`
+	Currency  entity.Currency `json:"currency" binding:"omitempty,oneof=BRL USD"`
 }
 
 type BidOutputDTO struct {
-	Id        string  `json:"id"`
-	UserId    string  `json:"user_id"`
-	AuctionId string  `json:"auction_id"`
-	Amount    float64 `json:"amount"`
+	Id        string          `json:"id"`
+	UserId    string          `json:"user_id"`
+	AuctionId string          `json:"auction_id"`
+	Amount    entity.Money    `json:"amount"`
+	Currency  entity.Currency `json:"currency"`
+	Timestamp time.Time       `json:"timestamp"`
 }
 
 type CreateBidUseCase struct {
-	bidRepository entity.BidRepositoryInterface
+	bidRepository     entity.BidRepositoryInterface
+	rejectedListeners []entity.BidRejectedListener
 }
 
 func NewCreateBidUseCase(bidRepository entity.BidRepositoryInterface) *CreateBidUseCase {
@@ -30,14 +42,29 @@ func NewCreateBidUseCase(bidRepository entity.BidRepositoryInterface) *CreateBid
 	}
 }
 
+// AddRejectedListener registra uma função chamada a cada lance recusado
+func (bu *CreateBidUseCase) AddRejectedListener(listener entity.BidRejectedListener) {
+	bu.rejectedListeners = append(bu.rejectedListeners, listener)
+}
+
 func (bu *CreateBidUseCase) Execute(ctx context.Context, input BidInputDTO) (*BidOutputDTO, *internal_error.InternalError) {
-	bid, err := entity.CreateBid(input.UserId, input.AuctionId, input.Amount)
+	ctx, span := tracer.Start(ctx, "CreateBidUseCase.Execute", trace.WithAttributes(
+		attribute.String("auction.id", input.AuctionId),
+		attribute.String("user.id", input.UserId),
+	))
+	defer span.End()
+
+	// Sem moeda informada o repositório usa a moeda do leilão
+	amount := input.Amount
+	amount.Currency = input.Currency
+
+	bid, err := entity.CreateBid(input.UserId, input.AuctionId, amount)
 	if err != nil {
-		return nil, internal_error.NewInternalServerError(err.Error())
+		return nil, bu.reject(ctx, input, amount, err)
 	}
 
 	if err := bu.bidRepository.CreateBid(ctx, bid); err != nil {
-		return nil, internal_error.NewBadRequestError(err.Error())
+		return nil, bu.reject(ctx, input, bid.Amount, err)
 	}
 
 	return &BidOutputDTO{
@@ -45,5 +72,27 @@ func (bu *CreateBidUseCase) Execute(ctx context.Context, input BidInputDTO) (*Bi
 		UserId:    bid.UserId,
 		AuctionId: bid.AuctionId,
 		Amount:    bid.Amount,
+		Currency:  bid.Amount.Currency,
+		Timestamp: bid.Timestamp,
 	}, nil
 }
+
+// reject converte o erro, marca o span com o motivo da recusa e avisa os listeners
+func (bu *CreateBidUseCase) reject(ctx context.Context, input BidInputDTO, amount entity.Money, err error) *internal_error.InternalError {
+	internalErr := internal_error.FromError(err)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("bid.rejection_reason", internalErr.Err))
+	span.SetStatus(codes.Error, internalErr.Message)
+
+	rejection := entity.BidRejection{
+		AuctionId: input.AuctionId,
+		UserId:    input.UserId,
+		Amount:    amount,
+		Reason:    internalErr.Err,
+	}
+	for _, listener := range bu.rejectedListeners {
+		listener(ctx, rejection)
+	}
+	return internalErr
+}
@@ -17,29 +17,43 @@ func NewFindBidUseCase(bidRepository entity.BidRepositoryInterface) *FindBidUseC
 	}
 }
 
-func (bu *FindBidUseCase) FindBidByAuctionId(ctx context.Context, auctionId string) ([]BidOutputDTO, *internal_error.InternalError) {
-	bids, err := bu.bidRepository.FindBidByAuctionId(ctx, auctionId)
+// FindBidByAuctionId retorna uma página de lances do leilão e o cursor da próxima página ("" na última)
+func (bu *FindBidUseCase) FindBidByAuctionId(ctx context.Context, auctionId string, page entity.PageRequest) ([]BidOutputDTO, string, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "FindBidUseCase.FindBidByAuctionId")
+	defer span.End()
+
+	page = page.WithDefaults("timestamp", entity.Ascending)
+	if !page.IsSortedBy(entity.BidSortFields) {
+		return nil, "", internal_error.NewBadRequestError("invalid sort, allowed fields: timestamp, amount")
+	}
+
+	bids, nextCursor, err := bu.bidRepository.FindBidByAuctionId(ctx, auctionId, page)
 	if err != nil {
-		return nil, internal_error.NewInternalServerError(err.Error())
+		return nil, "", internal_error.FromError(err)
 	}
 
-	var output []BidOutputDTO
+	output := []BidOutputDTO{}
 	for _, bid := range bids {
 		output = append(output, BidOutputDTO{
 			Id:        bid.Id,
 			UserId:    bid.UserId,
 			AuctionId: bid.AuctionId,
 			Amount:    bid.Amount,
+			Currency:  bid.Amount.Currency,
+			Timestamp: bid.Timestamp,
 		})
 	}
 
-	return output, nil
+	return output, nextCursor, nil
 }
 
 func (bu *FindBidUseCase) FindWinningBidByAuctionId(ctx context.Context, auctionId string) (*BidOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "FindBidUseCase.FindWinningBidByAuctionId")
+	defer span.End()
+
 	bid, err := bu.bidRepository.FindWinningBidByAuctionId(ctx, auctionId)
 	if err != nil {
-		return nil, internal_error.NewInternalServerError(err.Error())
+		return nil, internal_error.FromError(err)
 	}
 
 	if bid == nil {
@@ -51,5 +65,7 @@ func (bu *FindBidUseCase) FindWinningBidByAuctionId(ctx context.Context, auction
 		UserId:    bid.UserId,
 		AuctionId: bid.AuctionId,
 		Amount:    bid.Amount,
+		Currency:  bid.Amount.Currency,
+		Timestamp: bid.Timestamp,
 	}, nil
 }
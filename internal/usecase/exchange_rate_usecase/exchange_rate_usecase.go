@@ -0,0 +1,104 @@
+package exchange_rate_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+	"go.opentelemetry.io/otel"
+)
+
+// tracer abre os spans dos use cases, filhos do span da requisição
+var tracer = otel.Tracer("github.com/auction-goexpert/internal/usecase/exchange_rate_usecase")
+
+type ExchangeRateInputDTO struct {
+	From        entity.Currency `json:"from" binding:"required,oneof=BRL USD"`
+	To          entity.Currency `json:"to" binding:"required,oneof=BRL USD,nefield=From"`
+	Rate        string          `json:"rate" binding:"required"`
+	EffectiveAt *time.Time      `json:"effective_at"`
+}
+
+type ExchangeRateOutputDTO struct {
+	From        entity.Currency `json:"from"`
+	To          entity.Currency `json:"to"`
+	Rate        string          `json:"rate"`
+	EffectiveAt time.Time       `json:"effective_at"`
+}
+
+type ExchangeRateUseCase struct {
+	exchangeRateRepository entity.ExchangeRateRepositoryInterface
+}
+
+func NewExchangeRateUseCase(exchangeRateRepository entity.ExchangeRateRepositoryInterface) *ExchangeRateUseCase {
+	return &ExchangeRateUseCase{
+		exchangeRateRepository: exchangeRateRepository,
+	}
+}
+
+// CreateExchangeRate cadastra uma cotação; sem data de vigência ela vale a partir de agora
+func (eu *ExchangeRateUseCase) CreateExchangeRate(ctx context.Context, input ExchangeRateInputDTO) (*ExchangeRateOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "ExchangeRateUseCase.CreateExchangeRate")
+	defer span.End()
+
+	effectiveAt := time.Now()
+	if input.EffectiveAt != nil {
+		effectiveAt = *input.EffectiveAt
+	}
+
+	rate, err := entity.CreateExchangeRate(input.From, input.To, input.Rate, effectiveAt)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	if err := eu.exchangeRateRepository.UpsertExchangeRate(ctx, *rate); err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	output := toExchangeRateOutputDTO(*rate)
+	return &output, nil
+}
+
+// ImportExchangeRates cadastra várias cotações, como as lidas do arquivo local na inicialização
+func (eu *ExchangeRateUseCase) ImportExchangeRates(ctx context.Context, inputs []ExchangeRateInputDTO) *internal_error.InternalError {
+	ctx, span := tracer.Start(ctx, "ExchangeRateUseCase.ImportExchangeRates")
+	defer span.End()
+
+	for _, input := range inputs {
+		if input.EffectiveAt == nil {
+			return internal_error.NewBadRequestError("effective_at is required when importing exchange rates")
+		}
+
+		if _, internalErr := eu.CreateExchangeRate(ctx, input); internalErr != nil {
+			return internalErr
+		}
+	}
+
+	return nil
+}
+
+func (eu *ExchangeRateUseCase) FindExchangeRates(ctx context.Context) ([]ExchangeRateOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "ExchangeRateUseCase.FindExchangeRates")
+	defer span.End()
+
+	rates, err := eu.exchangeRateRepository.FindExchangeRates(ctx)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	output := []ExchangeRateOutputDTO{}
+	for _, rate := range rates {
+		output = append(output, toExchangeRateOutputDTO(rate))
+	}
+
+	return output, nil
+}
+
+func toExchangeRateOutputDTO(rate entity.ExchangeRate) ExchangeRateOutputDTO {
+	return ExchangeRateOutputDTO{
+		From:        rate.From,
+		To:          rate.To,
+		Rate:        rate.RateString(),
+		EffectiveAt: rate.EffectiveAt,
+	}
+}
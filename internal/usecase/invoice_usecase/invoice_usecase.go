@@ -0,0 +1,186 @@
+package invoice_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer abre os spans dos use cases, filhos do span da requisição
+var tracer = otel.Tracer("github.com/auction-goexpert/internal/usecase/invoice_usecase")
+
+type ExportInvoicesInputDTO struct {
+	From   *time.Time `form:"from" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     *time.Time `form:"to" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	Format string     `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+type InvoiceLineOutputDTO struct {
+	Kind        entity.InvoiceLineKind `json:"kind"`
+	Description string                 `json:"description"`
+	Amount      entity.Money           `json:"amount"`
+}
+
+type InvoiceOutputDTO struct {
+	Id        string                 `json:"id"`
+	AuctionId string                 `json:"auction_id"`
+	Attempt   int                    `json:"attempt"`
+	Party     entity.InvoiceParty    `json:"party"`
+	UserId    string                 `json:"user_id"`
+	Currency  entity.Currency        `json:"currency"`
+	Status    entity.InvoiceStatus   `json:"status"`
+	Lines     []InvoiceLineOutputDTO `json:"lines"`
+	Subtotal  entity.Money           `json:"subtotal"`
+	Tax       entity.Money           `json:"tax"`
+	Total     entity.Money           `json:"total"`
+	IssuedAt  time.Time              `json:"issued_at"`
+	VoidedAt  *time.Time             `json:"voided_at,omitempty"`
+}
+
+type InvoiceUseCase struct {
+	invoiceRepository entity.InvoiceRepositoryInterface
+	auctionRepository entity.AuctionRepositoryInterface
+	bidRepository     entity.BidRepositoryInterface
+	schedules         map[entity.Currency]entity.FeeSchedule
+}
+
+func NewInvoiceUseCase(
+	invoiceRepository entity.InvoiceRepositoryInterface,
+	auctionRepository entity.AuctionRepositoryInterface,
+	bidRepository entity.BidRepositoryInterface,
+	schedules map[entity.Currency]entity.FeeSchedule,
+) *InvoiceUseCase {
+	return &InvoiceUseCase{
+		invoiceRepository: invoiceRepository,
+		auctionRepository: auctionRepository,
+		bidRepository:     bidRepository,
+		schedules:         schedules,
+	}
+}
+
+// IssueInvoices calcula as tarifas do leilão encerrado pela tabela da moeda dele e emite as
+// faturas do vendedor e do comprador. Com a reserva não alcançada não há venda, e o vendedor
+// paga só a tarifa de anúncio. Pode ser repetido: faturas já emitidas são mantidas.
+func (iu *InvoiceUseCase) IssueInvoices(ctx context.Context, auction entity.Auction) error {
+	ctx, span := tracer.Start(ctx, "InvoiceUseCase.IssueInvoices", trace.WithAttributes(
+		attribute.String("auction.id", auction.Id),
+	))
+	defer span.End()
+
+	var winningBid *entity.Bid
+	if auction.ReserveMet() {
+		var err error
+		winningBid, err = iu.bidRepository.FindWinningBidByAuctionId(ctx, auction.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return iu.invoiceRepository.CreateInvoices(ctx, entity.NewAuctionInvoices(auction, winningBid, iu.scheduleFor(auction), time.Now()))
+}
+
+// ReissueInvoices emite as faturas da nova tentativa de acerto, com o comprador e o valor
+// dela, e anula as emitidas antes. A primeira tentativa de um leilão que alcançou a reserva
+// já é faturada no encerramento. Pode ser repetido: as faturas emitidas e anuladas são
+// mantidas.
+func (iu *InvoiceUseCase) ReissueInvoices(ctx context.Context, settlement entity.Settlement) error {
+	ctx, span := tracer.Start(ctx, "InvoiceUseCase.ReissueInvoices", trace.WithAttributes(
+		attribute.String("auction.id", settlement.AuctionId),
+		attribute.Int("settlement.attempt", settlement.Attempt),
+	))
+	defer span.End()
+
+	auction, err := iu.auctionRepository.FindAuctionById(ctx, settlement.AuctionId)
+	if err != nil {
+		return err
+	}
+	if auction == nil {
+		return entity.ErrAuctionNotFound
+	}
+	if settlement.Attempt == 1 && auction.ReserveMet() {
+		return nil
+	}
+
+	now := time.Now()
+	sale := entity.Bid{Id: settlement.BidId, UserId: settlement.WinnerId, AuctionId: settlement.AuctionId, Amount: settlement.Amount}
+	invoices := entity.NewReissuedInvoices(*auction, sale, settlement.Attempt, iu.scheduleFor(*auction), now)
+	if err := iu.invoiceRepository.CreateInvoices(ctx, invoices); err != nil {
+		return err
+	}
+
+	return iu.invoiceRepository.VoidInvoices(ctx, settlement.AuctionId, settlement.Attempt, now)
+}
+
+// scheduleFor é a tabela de tarifas da moeda do leilão
+func (iu *InvoiceUseCase) scheduleFor(auction entity.Auction) entity.FeeSchedule {
+	schedule, ok := iu.schedules[auction.Currency.OrDefault()]
+	if !ok {
+		schedule = entity.DefaultFeeSchedule()
+	}
+	return schedule
+}
+
+func (iu *InvoiceUseCase) FindInvoice(ctx context.Context, invoiceId string) (*InvoiceOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "InvoiceUseCase.FindInvoice")
+	defer span.End()
+
+	invoice, err := iu.invoiceRepository.FindInvoiceById(ctx, invoiceId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+	if invoice == nil {
+		return nil, internal_error.FromError(entity.ErrInvoiceNotFound)
+	}
+
+	output := toInvoiceOutput(*invoice)
+	return &output, nil
+}
+
+// ExportInvoices retorna as faturas emitidas no período, das mais antigas para as mais recentes
+func (iu *InvoiceUseCase) ExportInvoices(ctx context.Context, input ExportInvoicesInputDTO) ([]InvoiceOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "InvoiceUseCase.ExportInvoices")
+	defer span.End()
+
+	if input.From.After(*input.To) {
+		return nil, internal_error.NewBadRequestError("from must not be later than to")
+	}
+
+	invoices, err := iu.invoiceRepository.FindInvoicesIssuedBetween(ctx, *input.From, *input.To)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	output := make([]InvoiceOutputDTO, 0, len(invoices))
+	for _, invoice := range invoices {
+		output = append(output, toInvoiceOutput(invoice))
+	}
+	return output, nil
+}
+
+func toInvoiceOutput(invoice entity.Invoice) InvoiceOutputDTO {
+	lines := make([]InvoiceLineOutputDTO, 0, len(invoice.Lines))
+	for _, line := range invoice.Lines {
+		lines = append(lines, InvoiceLineOutputDTO{Kind: line.Kind, Description: line.Description, Amount: line.Amount})
+	}
+
+	return InvoiceOutputDTO{
+		Id:        invoice.Id,
+		AuctionId: invoice.AuctionId,
+		Attempt:   invoice.Attempt,
+		Party:     invoice.Party,
+		UserId:    invoice.UserId,
+		Currency:  invoice.Currency,
+		Status:    invoice.Status,
+		Lines:     lines,
+		Subtotal:  invoice.Subtotal,
+		Tax:       invoice.Tax,
+		Total:     invoice.Total,
+		IssuedAt:  invoice.IssuedAt,
+		VoidedAt:  invoice.VoidedAt,
+	}
+}
@@ -0,0 +1,133 @@
+package invoice_usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type invoiceRepository struct {
+	entity.InvoiceRepositoryInterface
+	invoices map[string]entity.Invoice
+}
+
+func (r *invoiceRepository) CreateInvoices(ctx context.Context, invoices []entity.Invoice) error {
+	for _, invoice := range invoices {
+		if _, ok := r.invoices[invoice.Id]; !ok {
+			r.invoices[invoice.Id] = invoice
+		}
+	}
+	return nil
+}
+
+func (r *invoiceRepository) VoidInvoices(ctx context.Context, auctionId string, currentAttempt int, at time.Time) error {
+	current := map[string]bool{
+		entity.ReissuedInvoiceId(auctionId, entity.InvoiceSeller, currentAttempt): true,
+		entity.ReissuedInvoiceId(auctionId, entity.InvoiceBuyer, currentAttempt):  true,
+	}
+	for id, invoice := range r.invoices {
+		if invoice.AuctionId == auctionId && invoice.Attempt <= currentAttempt && !current[id] && invoice.Status != entity.InvoiceVoided {
+			invoice.Status = entity.InvoiceVoided
+			invoice.VoidedAt = &at
+			r.invoices[id] = invoice
+		}
+	}
+	return nil
+}
+
+type auctionRepository struct {
+	entity.AuctionRepositoryInterface
+	auction entity.Auction
+}
+
+func (r *auctionRepository) FindAuctionById(ctx context.Context, id string) (*entity.Auction, error) {
+	return &r.auction, nil
+}
+
+type bidRepository struct {
+	entity.BidRepositoryInterface
+	winningBid entity.Bid
+}
+
+func (r *bidRepository) FindWinningBidByAuctionId(ctx context.Context, auctionId string) (*entity.Bid, error) {
+	return &r.winningBid, nil
+}
+
+func TestReissueInvoicesVoidsTheDefaultedBuyer(t *testing.T) {
+	auction := entity.Auction{Id: "auction-1", ProductName: "Bike", Currency: entity.BRL, SellerId: "seller-1"}
+	invoices := &invoiceRepository{invoices: map[string]entity.Invoice{}}
+	bids := &bidRepository{winningBid: entity.Bid{Id: "bid-2", UserId: "winner", AuctionId: auction.Id, Amount: entity.NewMoney(9000, entity.BRL)}}
+	useCase := NewInvoiceUseCase(invoices, &auctionRepository{auction: auction}, bids, nil)
+
+	ctx := context.Background()
+	require.NoError(t, useCase.IssueInvoices(ctx, auction))
+
+	// A primeira tentativa já foi faturada no encerramento
+	first := entity.Settlement{AuctionId: auction.Id, Attempt: 1, WinnerId: "winner", BidId: "bid-2", Amount: entity.NewMoney(9000, entity.BRL)}
+	require.NoError(t, useCase.ReissueInvoices(ctx, first))
+	assert.Len(t, invoices.invoices, 2)
+
+	fallback := entity.Settlement{AuctionId: auction.Id, Attempt: 2, WinnerId: "runner-up", BidId: "bid-1", Amount: entity.NewMoney(8000, entity.BRL)}
+	require.NoError(t, useCase.ReissueInvoices(ctx, fallback))
+	require.NoError(t, useCase.ReissueInvoices(ctx, fallback))
+	require.Len(t, invoices.invoices, 4)
+
+	assert.Equal(t, entity.InvoiceVoided, invoices.invoices["auction-1:buyer"].Status)
+	assert.Equal(t, entity.InvoiceVoided, invoices.invoices["auction-1:seller"].Status)
+	assert.NotNil(t, invoices.invoices["auction-1:buyer"].VoidedAt)
+
+	buyer := invoices.invoices["auction-1:buyer:2"]
+	assert.Equal(t, entity.InvoiceIssued, buyer.Status)
+	assert.Equal(t, "runner-up", buyer.UserId)
+	assert.Equal(t, entity.NewMoney(8000, entity.BRL), buyer.Total)
+	assert.Equal(t, entity.InvoiceIssued, invoices.invoices["auction-1:seller:2"].Status)
+}
+
+func TestReissueInvoicesAfterReserveNotMet(t *testing.T) {
+	auction := entity.Auction{Id: "auction-1", ProductName: "Bike", Currency: entity.BRL, SellerId: "seller-1",
+		CurrentPrice: entity.NewMoney(9000, entity.BRL), ReservePrice: entity.NewMoney(10000, entity.BRL)}
+	invoices := &invoiceRepository{invoices: map[string]entity.Invoice{}}
+	bids := &bidRepository{winningBid: entity.Bid{Id: "bid-2", UserId: "winner", AuctionId: auction.Id, Amount: entity.NewMoney(9000, entity.BRL)}}
+	schedules := map[entity.Currency]entity.FeeSchedule{entity.BRL: {ListingFee: 500}}
+	useCase := NewInvoiceUseCase(invoices, &auctionRepository{auction: auction}, bids, schedules)
+
+	// Sem venda no encerramento, o vendedor paga só a tarifa de anúncio
+	ctx := context.Background()
+	require.NoError(t, useCase.IssueInvoices(ctx, auction))
+	require.Len(t, invoices.invoices, 1)
+	require.Contains(t, invoices.invoices, "auction-1:seller")
+
+	// A oferta aceita abre a tentativa 1, faturada com id próprio
+	accepted := entity.Settlement{AuctionId: auction.Id, Attempt: 1, WinnerId: "winner", BidId: "bid-2", Amount: entity.NewMoney(9000, entity.BRL)}
+	require.NoError(t, useCase.ReissueInvoices(ctx, accepted))
+	require.Len(t, invoices.invoices, 3)
+
+	assert.Equal(t, entity.InvoiceVoided, invoices.invoices["auction-1:seller"].Status)
+	assert.Equal(t, entity.InvoiceIssued, invoices.invoices["auction-1:seller:1"].Status)
+	assert.Equal(t, "winner", invoices.invoices["auction-1:buyer:1"].UserId)
+}
+
+func TestLateReissueKeepsTheNewerAttemptInvoices(t *testing.T) {
+	auction := entity.Auction{Id: "auction-1", ProductName: "Bike", Currency: entity.BRL, SellerId: "seller-1"}
+	invoices := &invoiceRepository{invoices: map[string]entity.Invoice{}}
+	bids := &bidRepository{winningBid: entity.Bid{Id: "bid-3", UserId: "winner", AuctionId: auction.Id, Amount: entity.NewMoney(9000, entity.BRL)}}
+	useCase := NewInvoiceUseCase(invoices, &auctionRepository{auction: auction}, bids, nil)
+
+	ctx := context.Background()
+	require.NoError(t, useCase.IssueInvoices(ctx, auction))
+
+	second := entity.Settlement{AuctionId: auction.Id, Attempt: 2, WinnerId: "runner-up", BidId: "bid-2", Amount: entity.NewMoney(8000, entity.BRL)}
+	third := entity.Settlement{AuctionId: auction.Id, Attempt: 3, WinnerId: "third", BidId: "bid-1", Amount: entity.NewMoney(7000, entity.BRL)}
+	require.NoError(t, useCase.ReissueInvoices(ctx, second))
+	require.NoError(t, useCase.ReissueInvoices(ctx, third))
+
+	// A tentativa 2 retomada depois de a 3 ser aberta não anula as faturas da 3
+	require.NoError(t, useCase.ReissueInvoices(ctx, second))
+	assert.Equal(t, entity.InvoiceIssued, invoices.invoices["auction-1:buyer:3"].Status)
+	assert.Equal(t, entity.InvoiceIssued, invoices.invoices["auction-1:seller:3"].Status)
+	assert.Equal(t, entity.InvoiceVoided, invoices.invoices["auction-1:buyer:2"].Status)
+}
@@ -0,0 +1,75 @@
+package notification_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+	"go.opentelemetry.io/otel"
+)
+
+// tracer abre os spans dos use cases, filhos do span da requisição
+var tracer = otel.Tracer("github.com/auction-goexpert/internal/usecase/notification_usecase")
+
+type NotificationOutputDTO struct {
+	Id        string                  `json:"id"`
+	UserId    string                  `json:"user_id"`
+	AuctionId string                  `json:"auction_id"`
+	Type      entity.NotificationType `json:"type"`
+	Title     string                  `json:"title"`
+	Message   string                  `json:"message"`
+	Read      bool                    `json:"read"`
+	Timestamp time.Time               `json:"timestamp"`
+}
+
+type MarkAsReadInputDTO struct {
+	Ids []string `json:"ids"`
+}
+
+type FindNotificationUseCase struct {
+	notificationRepository entity.NotificationRepositoryInterface
+}
+
+func NewFindNotificationUseCase(notificationRepository entity.NotificationRepositoryInterface) *FindNotificationUseCase {
+	return &FindNotificationUseCase{
+		notificationRepository: notificationRepository,
+	}
+}
+
+func (nu *FindNotificationUseCase) FindNotificationsByUserId(ctx context.Context, userId string, onlyUnread bool) ([]NotificationOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "FindNotificationUseCase.FindNotificationsByUserId")
+	defer span.End()
+
+	notifications, err := nu.notificationRepository.FindNotificationsByUserId(ctx, userId, onlyUnread)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	var output []NotificationOutputDTO
+	for _, notification := range notifications {
+		output = append(output, NotificationOutputDTO{
+			Id:        notification.Id,
+			UserId:    notification.UserId,
+			AuctionId: notification.AuctionId,
+			Type:      notification.Type,
+			Title:     notification.Title,
+			Message:   notification.Message,
+			Read:      notification.Read,
+			Timestamp: notification.Timestamp,
+		})
+	}
+
+	return output, nil
+}
+
+func (nu *FindNotificationUseCase) MarkNotificationsAsRead(ctx context.Context, userId string, input MarkAsReadInputDTO) *internal_error.InternalError {
+	ctx, span := tracer.Start(ctx, "FindNotificationUseCase.MarkNotificationsAsRead")
+	defer span.End()
+
+	if err := nu.notificationRepository.MarkNotificationsAsRead(ctx, userId, input.Ids); err != nil {
+		return internal_error.FromError(err)
+	}
+
+	return nil
+}
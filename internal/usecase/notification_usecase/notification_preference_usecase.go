@@ -0,0 +1,95 @@
+package notification_usecase
+
+import (
+	"context"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+)
+
+type NotificationPreferenceInputDTO struct {
+	Locale          string                    `json:"locale" binding:"omitempty,oneof=pt-BR en"`
+	Email           string                    `json:"email" binding:"omitempty,email"`
+	Channels        []string                  `json:"channels" binding:"dive,oneof=email inbox log"`
+	MutedTypes      []entity.NotificationType `json:"muted_types" binding:"dive,oneof=outbid auction_won ending_soon payment_requested second_chance_offer"`
+	ReminderMinutes []int                     `json:"reminder_minutes" binding:"dive,min=1,max=60"`
+}
+
+type NotificationPreferenceOutputDTO struct {
+	UserId          string                    `json:"user_id"`
+	Locale          string                    `json:"locale"`
+	Email           string                    `json:"email"`
+	Channels        []string                  `json:"channels"`
+	MutedTypes      []entity.NotificationType `json:"muted_types"`
+	ReminderMinutes []int                     `json:"reminder_minutes"`
+}
+
+type NotificationPreferenceUseCase struct {
+	preferenceRepository entity.NotificationPreferenceRepositoryInterface
+}
+
+func NewNotificationPreferenceUseCase(preferenceRepository entity.NotificationPreferenceRepositoryInterface) *NotificationPreferenceUseCase {
+	return &NotificationPreferenceUseCase{
+		preferenceRepository: preferenceRepository,
+	}
+}
+
+func (pu *NotificationPreferenceUseCase) FindPreference(ctx context.Context, userId string) (*NotificationPreferenceOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "NotificationPreferenceUseCase.FindPreference")
+	defer span.End()
+
+	preference, err := pu.preferenceRepository.FindPreferenceByUserId(ctx, userId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	// Usuários sem preferências recebem os canais padrão
+	if preference == nil {
+		preference = &entity.NotificationPreference{
+			UserId:   userId,
+			Channels: entity.DefaultNotificationChannels,
+		}
+	}
+
+	return &NotificationPreferenceOutputDTO{
+		UserId:          preference.UserId,
+		Locale:          preference.Locale,
+		Email:           preference.Email,
+		Channels:        preference.Channels,
+		MutedTypes:      preference.MutedTypes,
+		ReminderMinutes: preference.Reminders(),
+	}, nil
+}
+
+func (pu *NotificationPreferenceUseCase) UpdatePreference(ctx context.Context, userId string, input NotificationPreferenceInputDTO) (*NotificationPreferenceOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "NotificationPreferenceUseCase.UpdatePreference")
+	defer span.End()
+
+	for _, channel := range input.Channels {
+		if channel == entity.EmailNotificationChannel && input.Email == "" {
+			return nil, internal_error.NewBadRequestError("email is required to enable the email channel")
+		}
+	}
+
+	preference := &entity.NotificationPreference{
+		UserId:          userId,
+		Locale:          input.Locale,
+		Email:           input.Email,
+		Channels:        input.Channels,
+		MutedTypes:      input.MutedTypes,
+		ReminderMinutes: input.ReminderMinutes,
+	}
+
+	if err := pu.preferenceRepository.UpsertPreference(ctx, preference); err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	return &NotificationPreferenceOutputDTO{
+		UserId:          preference.UserId,
+		Locale:          preference.Locale,
+		Email:           preference.Email,
+		Channels:        preference.Channels,
+		MutedTypes:      preference.MutedTypes,
+		ReminderMinutes: preference.Reminders(),
+	}, nil
+}
@@ -0,0 +1,382 @@
+package offer_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer abre os spans dos use cases, filhos do span da requisição
+var tracer = otel.Tracer("github.com/auction-goexpert/internal/usecase/offer_usecase")
+
+type CreateOffersInputDTO struct {
+	SellerId string   `json:"seller_id" binding:"required"`
+	UserIds  []string `json:"user_ids" binding:"required,min=1,max=20,dive,required"`
+	// ExpiresInMinutes é o prazo de resposta; sem ele vale entity.DefaultOfferExpiry
+	ExpiresInMinutes int `json:"expires_in_minutes" binding:"omitempty,min=1,max=10080"`
+}
+
+type OfferResponseInputDTO struct {
+	UserId string `json:"user_id" binding:"required"`
+}
+
+type RunnerUpOutputDTO struct {
+	UserId    string          `json:"user_id"`
+	BidId     string          `json:"bid_id"`
+	Amount    entity.Money    `json:"amount"`
+	Currency  entity.Currency `json:"currency"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+type OfferOutputDTO struct {
+	Id          string             `json:"id"`
+	AuctionId   string             `json:"auction_id"`
+	SellerId    string             `json:"seller_id"`
+	UserId      string             `json:"user_id"`
+	BidId       string             `json:"bid_id"`
+	Amount      entity.Money       `json:"amount"`
+	Currency    entity.Currency    `json:"currency"`
+	Status      entity.OfferStatus `json:"status"`
+	ExpiresAt   time.Time          `json:"expires_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+	RespondedAt *time.Time         `json:"responded_at,omitempty"`
+}
+
+// SettlementOpener abre uma nova tentativa de acerto com o autor do lance; é implementado
+// pelo use case de acerto, que reserva o valor na carteira do comprador e avisa os listeners
+type SettlementOpener interface {
+	OpenAttempt(ctx context.Context, sellerId string, bid entity.Bid) error
+}
+
+type OfferUseCase struct {
+	offerRepository      entity.OfferRepositoryInterface
+	auctionRepository    entity.AuctionRepositoryInterface
+	bidRepository        entity.BidRepositoryInterface
+	settlementRepository entity.SettlementRepositoryInterface
+	settlementOpener     SettlementOpener
+	offeredListeners     []entity.SecondChanceOfferedListener
+	logger               *slog.Logger
+}
+
+func NewOfferUseCase(
+	offerRepository entity.OfferRepositoryInterface,
+	auctionRepository entity.AuctionRepositoryInterface,
+	bidRepository entity.BidRepositoryInterface,
+	settlementRepository entity.SettlementRepositoryInterface,
+	settlementOpener SettlementOpener,
+	logger *slog.Logger,
+) *OfferUseCase {
+	return &OfferUseCase{
+		offerRepository:      offerRepository,
+		auctionRepository:    auctionRepository,
+		bidRepository:        bidRepository,
+		settlementRepository: settlementRepository,
+		settlementOpener:     settlementOpener,
+		logger:               logger,
+	}
+}
+
+// AddOfferedListener registra uma função chamada a cada oferta criada.
+// Deve ser chamado durante a inicialização.
+func (ou *OfferUseCase) AddOfferedListener(listener entity.SecondChanceOfferedListener) {
+	ou.offeredListeners = append(ou.offeredListeners, listener)
+}
+
+// FindRunnerUps lista o maior lance de cada usuário que ainda não teve uma tentativa de acerto
+func (ou *OfferUseCase) FindRunnerUps(ctx context.Context, auctionId string) ([]RunnerUpOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "OfferUseCase.FindRunnerUps", trace.WithAttributes(
+		attribute.String("auction.id", auctionId),
+	))
+	defer span.End()
+
+	auction, err := ou.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+	if auction == nil {
+		return nil, internal_error.FromError(entity.ErrAuctionNotFound)
+	}
+
+	settlements, err := ou.settlementRepository.FindSettlementsByAuctionId(ctx, auctionId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	runnerUps, err := ou.runnerUps(ctx, auctionId, settlements)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	output := []RunnerUpOutputDTO{}
+	for _, bid := range runnerUps {
+		output = append(output, RunnerUpOutputDTO{
+			UserId:    bid.UserId,
+			BidId:     bid.Id,
+			Amount:    bid.Amount,
+			Currency:  bid.Amount.Currency,
+			Timestamp: bid.Timestamp,
+		})
+	}
+	return output, nil
+}
+
+// CreateOffers oferece o item aos licitantes escolhidos pelo vendedor, cada um pelo valor do
+// seu maior lance. Só é possível depois que a última tentativa de acerto expirou ou quando o
+// leilão encerrou sem alcançar o preço de reserva; quem já tem uma oferta pendente não recebe
+// outra.
+func (ou *OfferUseCase) CreateOffers(ctx context.Context, auctionId string, input CreateOffersInputDTO) ([]OfferOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "OfferUseCase.CreateOffers", trace.WithAttributes(
+		attribute.String("auction.id", auctionId),
+		attribute.Int("offer.count", len(input.UserIds)),
+	))
+	defer span.End()
+
+	auction, err := ou.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+	if auction == nil {
+		return nil, internal_error.FromError(entity.ErrAuctionNotFound)
+	}
+	if auction.SellerId == "" || auction.SellerId != input.SellerId {
+		return nil, internal_error.FromError(entity.ErrNotAuctionSeller)
+	}
+
+	settlements, err := ou.settlementRepository.FindSettlementsByAuctionId(ctx, auctionId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+	if !secondChanceOpen(*auction, settlements) {
+		return nil, internal_error.FromError(entity.ErrSecondChanceNotOpen)
+	}
+
+	existing, err := ou.offerRepository.FindOffersByAuctionId(ctx, auctionId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	now := time.Now()
+	pendingUsers := make(map[string]bool)
+	for _, offer := range existing {
+		switch offer.StatusAt(now) {
+		case entity.OfferAccepted:
+			return nil, internal_error.FromError(entity.ErrOfferAlreadyAccepted)
+		case entity.OfferPending:
+			pendingUsers[offer.UserId] = true
+		}
+	}
+
+	runnerUps, err := ou.runnerUps(ctx, auctionId, settlements)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+	bidsByUser := make(map[string]entity.Bid, len(runnerUps))
+	for _, bid := range runnerUps {
+		bidsByUser[bid.UserId] = bid
+	}
+
+	expiresIn := entity.DefaultOfferExpiry
+	if input.ExpiresInMinutes > 0 {
+		expiresIn = time.Duration(input.ExpiresInMinutes) * time.Minute
+	}
+
+	var offers []entity.SecondChanceOffer
+	for _, userId := range input.UserIds {
+		bid, ok := bidsByUser[userId]
+		if !ok {
+			return nil, internal_error.FromError(entity.ErrNotRunnerUp.WithMessage("user %s is not a runner-up of this auction", userId))
+		}
+		if pendingUsers[userId] {
+			continue
+		}
+		pendingUsers[userId] = true
+		offers = append(offers, entity.NewSecondChanceOffer(auction.SellerId, bid, expiresIn))
+	}
+
+	if err := ou.offerRepository.CreateOffers(ctx, offers); err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	output := make([]OfferOutputDTO, 0, len(offers))
+	for _, offer := range offers {
+		for _, listener := range ou.offeredListeners {
+			listener(ctx, offer)
+		}
+		output = append(output, toOfferOutput(offer, now))
+	}
+	return output, nil
+}
+
+// FindOffers lista as ofertas do leilão, das mais recentes para as mais antigas
+func (ou *OfferUseCase) FindOffers(ctx context.Context, auctionId string) ([]OfferOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "OfferUseCase.FindOffers", trace.WithAttributes(
+		attribute.String("auction.id", auctionId),
+	))
+	defer span.End()
+
+	offers, err := ou.offerRepository.FindOffersByAuctionId(ctx, auctionId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	now := time.Now()
+	output := []OfferOutputDTO{}
+	for _, offer := range offers {
+		output = append(output, toOfferOutput(offer, now))
+	}
+	return output, nil
+}
+
+// AcceptOffer aceita a oferta e abre uma nova tentativa de acerto com quem aceitou, pelo
+// valor da oferta. As demais ofertas pendentes do leilão são retiradas. Se a tentativa não
+// puder ser aberta, a oferta volta a ficar pendente.
+//
+// Uma falha ao retirar as demais ofertas não desfaz o aceite, que já foi concluído: ela fica
+// no log, e as ofertas que continuarem pendentes são recusadas ao serem aceitas, pois só uma
+// oferta pode ser aceita por leilão.
+func (ou *OfferUseCase) AcceptOffer(ctx context.Context, auctionId, offerId string, input OfferResponseInputDTO) (*OfferOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "OfferUseCase.AcceptOffer", trace.WithAttributes(
+		attribute.String("auction.id", auctionId),
+		attribute.String("offer.id", offerId),
+	))
+	defer span.End()
+
+	offer, internalErr := ou.findOwnOffer(ctx, auctionId, offerId, input.UserId)
+	if internalErr != nil {
+		return nil, internalErr
+	}
+
+	now := time.Now()
+	if err := ou.offerRepository.AcceptOffer(ctx, offer.Id, now); err != nil {
+		return nil, internal_error.FromError(err)
+	}
+	offer.Status = entity.OfferAccepted
+	offer.RespondedAt = now.Truncate(time.Millisecond)
+
+	// O comprador passa a ser quem aceitou: a tentativa aberta reserva o valor na carteira
+	// dele e avisa os listeners de acerto, que reemitem as faturas e notificam o comprador
+	bid := entity.Bid{Id: offer.BidId, UserId: offer.UserId, AuctionId: offer.AuctionId, Amount: offer.Amount}
+	if err := ou.settlementOpener.OpenAttempt(ctx, offer.SellerId, bid); err != nil {
+		if reopenErr := ou.offerRepository.ReopenOffer(ctx, offer.Id); reopenErr != nil {
+			return nil, internal_error.FromError(errors.Join(err, fmt.Errorf("reopening offer %s: %w", offer.Id, reopenErr)))
+		}
+		return nil, internal_error.FromError(err)
+	}
+
+	if err := ou.offerRepository.WithdrawPendingOffers(ctx, auctionId, now); err != nil {
+		span.RecordError(err)
+		ou.logger.WarnContext(ctx, "Error withdrawing pending offers after acceptance",
+			slog.String("auction_id", auctionId), slog.String("offer_id", offer.Id), slog.Any("error", err))
+	}
+
+	output := toOfferOutput(*offer, now)
+	return &output, nil
+}
+
+// DeclineOffer recusa a oferta; o vendedor pode oferecer o item a outros licitantes
+func (ou *OfferUseCase) DeclineOffer(ctx context.Context, auctionId, offerId string, input OfferResponseInputDTO) (*OfferOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "OfferUseCase.DeclineOffer", trace.WithAttributes(
+		attribute.String("auction.id", auctionId),
+		attribute.String("offer.id", offerId),
+	))
+	defer span.End()
+
+	offer, internalErr := ou.findOwnOffer(ctx, auctionId, offerId, input.UserId)
+	if internalErr != nil {
+		return nil, internalErr
+	}
+
+	now := time.Now()
+	if err := ou.offerRepository.DeclineOffer(ctx, offer.Id, now); err != nil {
+		return nil, internal_error.FromError(err)
+	}
+	offer.Status = entity.OfferDeclined
+	offer.RespondedAt = now.Truncate(time.Millisecond)
+
+	output := toOfferOutput(*offer, now)
+	return &output, nil
+}
+
+// findOwnOffer busca a oferta pendente do leilão endereçada ao usuário
+func (ou *OfferUseCase) findOwnOffer(ctx context.Context, auctionId, offerId, userId string) (*entity.SecondChanceOffer, *internal_error.InternalError) {
+	offer, err := ou.offerRepository.FindOfferById(ctx, offerId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+	if offer == nil || offer.AuctionId != auctionId {
+		return nil, internal_error.FromError(entity.ErrOfferNotFound)
+	}
+	if offer.UserId != userId {
+		return nil, internal_error.FromError(entity.ErrNotOfferRecipient)
+	}
+	if offer.StatusAt(time.Now()) != entity.OfferPending {
+		return nil, internal_error.FromError(entity.ErrOfferNotPending)
+	}
+	return offer, nil
+}
+
+// secondChanceOpen indica se o vendedor pode fazer ofertas: a última tentativa de acerto
+// expirou e a passagem automática ao próximo licitante já terminou, ou o leilão encerrou com
+// a reserva não alcançada e ainda não tem acerto. Enquanto a expiração está pendente a
+// passagem automática ainda pode abrir a tentativa seguinte; depois dela, a última tentativa
+// só continua expirada se o limite de tentativas foi atingido ou se não havia outro licitante.
+func secondChanceOpen(auction entity.Auction, settlements []entity.Settlement) bool {
+	if len(settlements) == 0 {
+		return auction.Status == entity.Completed && !auction.ReserveMet()
+	}
+	last := settlements[len(settlements)-1]
+	return last.Status == entity.SettlementExpired && !last.ExpiryPending
+}
+
+// runnerUps percorre os lances do maior para o menor e mantém o maior de cada usuário,
+// sem os usuários que já tiveram uma tentativa de acerto
+func (ou *OfferUseCase) runnerUps(ctx context.Context, auctionId string, settlements []entity.Settlement) ([]entity.Bid, error) {
+	excludedUserIds := make([]string, 0, len(settlements))
+	for _, settlement := range settlements {
+		excludedUserIds = append(excludedUserIds, settlement.WinnerId)
+	}
+
+	var bids []entity.Bid
+	page := entity.PageRequest{Limit: entity.MaxPageLimit, SortBy: "amount", Order: entity.Descending}
+	for {
+		pageBids, nextCursor, err := ou.bidRepository.FindBidByAuctionId(ctx, auctionId, page)
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, pageBids...)
+		if nextCursor == "" {
+			break
+		}
+		page.Cursor = nextCursor
+	}
+
+	return entity.RunnerUps(bids, excludedUserIds), nil
+}
+
+func toOfferOutput(offer entity.SecondChanceOffer, now time.Time) OfferOutputDTO {
+	output := OfferOutputDTO{
+		Id:        offer.Id,
+		AuctionId: offer.AuctionId,
+		SellerId:  offer.SellerId,
+		UserId:    offer.UserId,
+		BidId:     offer.BidId,
+		Amount:    offer.Amount,
+		Currency:  offer.Amount.Currency,
+		Status:    offer.StatusAt(now),
+		ExpiresAt: offer.ExpiresAt,
+		CreatedAt: offer.CreatedAt,
+	}
+	if !offer.RespondedAt.IsZero() {
+		respondedAt := offer.RespondedAt
+		output.RespondedAt = &respondedAt
+	}
+	return output
+}
@@ -0,0 +1,274 @@
+package offer_usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// offerRepository guarda as ofertas em memória; como o índice único do Mongo, recusa um
+// segundo aceite no mesmo leilão. withdrawErr, se definido, é retornado por
+// WithdrawPendingOffers.
+type offerRepository struct {
+	entity.OfferRepositoryInterface
+	offers      []entity.SecondChanceOffer
+	withdrawErr error
+}
+
+func (r *offerRepository) CreateOffers(ctx context.Context, offers []entity.SecondChanceOffer) error {
+	r.offers = append(r.offers, offers...)
+	return nil
+}
+
+func (r *offerRepository) FindOffersByAuctionId(ctx context.Context, auctionId string) ([]entity.SecondChanceOffer, error) {
+	return append([]entity.SecondChanceOffer(nil), r.offers...), nil
+}
+
+func (r *offerRepository) FindOfferById(ctx context.Context, offerId string) (*entity.SecondChanceOffer, error) {
+	for _, offer := range r.offers {
+		if offer.Id == offerId {
+			return &offer, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *offerRepository) AcceptOffer(ctx context.Context, offerId string, at time.Time) error {
+	for _, offer := range r.offers {
+		if offer.Status == entity.OfferAccepted {
+			return entity.ErrOfferAlreadyAccepted
+		}
+	}
+	return r.respond(offerId, entity.OfferAccepted, at)
+}
+
+func (r *offerRepository) ReopenOffer(ctx context.Context, offerId string) error {
+	for i := range r.offers {
+		if r.offers[i].Id == offerId && r.offers[i].Status == entity.OfferAccepted {
+			r.offers[i].Status = entity.OfferPending
+			r.offers[i].RespondedAt = time.Time{}
+		}
+	}
+	return nil
+}
+
+func (r *offerRepository) WithdrawPendingOffers(ctx context.Context, auctionId string, at time.Time) error {
+	if r.withdrawErr != nil {
+		return r.withdrawErr
+	}
+	for i := range r.offers {
+		if r.offers[i].AuctionId == auctionId && r.offers[i].Status == entity.OfferPending {
+			r.offers[i].Status = entity.OfferWithdrawn
+			r.offers[i].RespondedAt = at
+		}
+	}
+	return nil
+}
+
+func (r *offerRepository) respond(offerId string, status entity.OfferStatus, at time.Time) error {
+	for i := range r.offers {
+		if r.offers[i].Id == offerId {
+			if r.offers[i].StatusAt(at) != entity.OfferPending {
+				return entity.ErrOfferNotPending
+			}
+			r.offers[i].Status = status
+			r.offers[i].RespondedAt = at
+			return nil
+		}
+	}
+	return entity.ErrOfferNotFound
+}
+
+type auctionRepository struct {
+	entity.AuctionRepositoryInterface
+	auction entity.Auction
+}
+
+func (r *auctionRepository) FindAuctionById(ctx context.Context, id string) (*entity.Auction, error) {
+	if id != r.auction.Id {
+		return nil, nil
+	}
+	auction := r.auction
+	return &auction, nil
+}
+
+// bidRepository devolve todos os lances numa página, já do maior para o menor
+type bidRepository struct {
+	entity.BidRepositoryInterface
+	bids []entity.Bid
+}
+
+func (r *bidRepository) FindBidByAuctionId(ctx context.Context, auctionId string, page entity.PageRequest) ([]entity.Bid, string, error) {
+	return r.bids, "", nil
+}
+
+type settlementRepository struct {
+	entity.SettlementRepositoryInterface
+	settlements []entity.Settlement
+}
+
+func (r *settlementRepository) FindSettlementsByAuctionId(ctx context.Context, auctionId string) ([]entity.Settlement, error) {
+	return r.settlements, nil
+}
+
+// settlementOpener guarda os lances das tentativas abertas; err, se definido, é retornado
+type settlementOpener struct {
+	opened []entity.Bid
+	err    error
+}
+
+func (o *settlementOpener) OpenAttempt(ctx context.Context, sellerId string, bid entity.Bid) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.opened = append(o.opened, bid)
+	return nil
+}
+
+// newTestUseCase monta o use case para um leilão cujo vencedor (winner) deixou a tentativa
+// expirar, com os lances de runner-1 e runner-2 ainda sem tentativa
+func newTestUseCase() (*OfferUseCase, *offerRepository, *settlementOpener) {
+	auction := entity.Auction{Id: "auction-1", SellerId: "seller", Status: entity.Completed, CurrentPrice: entity.NewMoney(9000, entity.BRL)}
+	bids := []entity.Bid{
+		{Id: "bid-3", UserId: "winner", AuctionId: "auction-1", Amount: entity.NewMoney(9000, entity.BRL)},
+		{Id: "bid-2", UserId: "runner-1", AuctionId: "auction-1", Amount: entity.NewMoney(8000, entity.BRL)},
+		{Id: "bid-1", UserId: "runner-2", AuctionId: "auction-1", Amount: entity.NewMoney(7000, entity.BRL)},
+	}
+	settlements := []entity.Settlement{{Id: "auction-1:1", AuctionId: "auction-1", Attempt: 1, WinnerId: "winner", Status: entity.SettlementExpired}}
+
+	offers := &offerRepository{}
+	opener := &settlementOpener{}
+	useCase := NewOfferUseCase(offers, &auctionRepository{auction: auction}, &bidRepository{bids: bids},
+		&settlementRepository{settlements: settlements}, opener, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return useCase, offers, opener
+}
+
+func TestCreateOffersSkipsUsersWithPendingOffers(t *testing.T) {
+	useCase, offers, _ := newTestUseCase()
+	ctx := context.Background()
+
+	created, internalErr := useCase.CreateOffers(ctx, "auction-1", CreateOffersInputDTO{SellerId: "seller", UserIds: []string{"runner-1"}})
+	require.Nil(t, internalErr)
+	require.Len(t, created, 1)
+	assert.Equal(t, int64(8000), created[0].Amount.Cents)
+
+	created, internalErr = useCase.CreateOffers(ctx, "auction-1", CreateOffersInputDTO{SellerId: "seller", UserIds: []string{"runner-1", "runner-2", "runner-2"}})
+	require.Nil(t, internalErr)
+	require.Len(t, created, 1)
+	assert.Equal(t, "runner-2", created[0].UserId)
+	assert.Len(t, offers.offers, 2)
+}
+
+func TestCreateOffersRejectsUsersThatAreNotRunnerUps(t *testing.T) {
+	useCase, offers, _ := newTestUseCase()
+	ctx := context.Background()
+
+	// O vencedor que não pagou já teve uma tentativa, e "stranger" não deu lances
+	for _, userId := range []string{"winner", "stranger"} {
+		_, internalErr := useCase.CreateOffers(ctx, "auction-1", CreateOffersInputDTO{SellerId: "seller", UserIds: []string{"runner-1", userId}})
+		require.NotNil(t, internalErr, userId)
+		assert.Equal(t, http.StatusBadRequest, internalErr.Code)
+		assert.Equal(t, "not_a_runner_up", internalErr.Err)
+	}
+	assert.Empty(t, offers.offers)
+
+	_, internalErr := useCase.CreateOffers(ctx, "auction-1", CreateOffersInputDTO{SellerId: "runner-1", UserIds: []string{"runner-2"}})
+	require.NotNil(t, internalErr)
+	assert.Equal(t, "not_auction_seller", internalErr.Err)
+}
+
+func TestAcceptOfferOpensAttemptAndWithdrawsOtherOffers(t *testing.T) {
+	useCase, offers, opener := newTestUseCase()
+	ctx := context.Background()
+
+	created, internalErr := useCase.CreateOffers(ctx, "auction-1", CreateOffersInputDTO{SellerId: "seller", UserIds: []string{"runner-1", "runner-2"}})
+	require.Nil(t, internalErr)
+	require.Len(t, created, 2)
+
+	accepted, internalErr := useCase.AcceptOffer(ctx, "auction-1", created[1].Id, OfferResponseInputDTO{UserId: "runner-2"})
+	require.Nil(t, internalErr)
+	assert.Equal(t, entity.OfferAccepted, accepted.Status)
+	require.Len(t, opener.opened, 1)
+	assert.Equal(t, "runner-2", opener.opened[0].UserId)
+	assert.Equal(t, int64(7000), opener.opened[0].Amount.Cents)
+
+	other, _ := offers.FindOfferById(ctx, created[0].Id)
+	assert.Equal(t, entity.OfferWithdrawn, other.Status)
+
+	// A oferta retirada não pode mais ser aceita
+	_, internalErr = useCase.AcceptOffer(ctx, "auction-1", created[0].Id, OfferResponseInputDTO{UserId: "runner-1"})
+	require.NotNil(t, internalErr)
+	assert.Equal(t, "offer_not_pending", internalErr.Err)
+	assert.Len(t, opener.opened, 1)
+}
+
+func TestSecondAcceptanceConflicts(t *testing.T) {
+	useCase, offers, opener := newTestUseCase()
+	ctx := context.Background()
+
+	created, internalErr := useCase.CreateOffers(ctx, "auction-1", CreateOffersInputDTO{SellerId: "seller", UserIds: []string{"runner-1", "runner-2"}})
+	require.Nil(t, internalErr)
+
+	// Sem a retirada, a outra oferta continua pendente; o aceite dela esbarra no primeiro
+	offers.withdrawErr = errors.New("database unavailable")
+	accepted, internalErr := useCase.AcceptOffer(ctx, "auction-1", created[0].Id, OfferResponseInputDTO{UserId: "runner-1"})
+	require.Nil(t, internalErr)
+	assert.Equal(t, entity.OfferAccepted, accepted.Status)
+
+	_, internalErr = useCase.AcceptOffer(ctx, "auction-1", created[1].Id, OfferResponseInputDTO{UserId: "runner-2"})
+	require.NotNil(t, internalErr)
+	assert.Equal(t, http.StatusConflict, internalErr.Code)
+	assert.Equal(t, "offer_already_accepted", internalErr.Err)
+	assert.Len(t, opener.opened, 1)
+}
+
+func TestAcceptOfferReopensWhenAttemptCannotBeOpened(t *testing.T) {
+	useCase, offers, opener := newTestUseCase()
+	ctx := context.Background()
+
+	created, internalErr := useCase.CreateOffers(ctx, "auction-1", CreateOffersInputDTO{SellerId: "seller", UserIds: []string{"runner-1", "runner-2"}})
+	require.Nil(t, internalErr)
+
+	opener.err = entity.ErrSettlementAttemptTaken
+	_, internalErr = useCase.AcceptOffer(ctx, "auction-1", created[0].Id, OfferResponseInputDTO{UserId: "runner-1"})
+	require.NotNil(t, internalErr)
+	assert.Equal(t, "settlement_attempt_taken", internalErr.Err)
+
+	// As duas ofertas continuam pendentes e a primeira pode ser aceita de novo
+	for _, offer := range offers.offers {
+		assert.Equal(t, entity.OfferPending, offer.Status)
+	}
+
+	opener.err = nil
+	accepted, internalErr := useCase.AcceptOffer(ctx, "auction-1", created[0].Id, OfferResponseInputDTO{UserId: "runner-1"})
+	require.Nil(t, internalErr)
+	assert.Equal(t, entity.OfferAccepted, accepted.Status)
+	require.Len(t, opener.opened, 1)
+}
+
+func TestSecondChanceOpensAfterTheFallbackFinishes(t *testing.T) {
+	auction := entity.Auction{Id: "auction-1", Status: entity.Completed, CurrentPrice: entity.NewMoney(9000, entity.BRL)}
+	expired := entity.Settlement{Id: "auction-1:1", Attempt: 1, Status: entity.SettlementExpired, ExpiryPending: true}
+
+	// Enquanto a expiração está pendente a passagem automática ainda pode abrir a tentativa 2
+	assert.False(t, secondChanceOpen(auction, []entity.Settlement{expired}))
+
+	expired.ExpiryPending = false
+	assert.True(t, secondChanceOpen(auction, []entity.Settlement{expired}))
+
+	fallback := entity.Settlement{Id: "auction-1:2", Attempt: 2, Status: entity.SettlementAwaitingPayment}
+	assert.False(t, secondChanceOpen(auction, []entity.Settlement{expired, fallback}))
+
+	// Sem acerto, só o leilão encerrado abaixo da reserva aceita ofertas
+	assert.False(t, secondChanceOpen(auction, nil))
+	auction.ReservePrice = entity.NewMoney(10000, entity.BRL)
+	assert.True(t, secondChanceOpen(auction, nil))
+}
@@ -0,0 +1,128 @@
+package report_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+	"go.opentelemetry.io/otel"
+)
+
+// tracer abre os spans dos use cases, filhos do span da requisição
+var tracer = otel.Tracer("github.com/auction-goexpert/internal/usecase/report_usecase")
+
+type SalesReportInputDTO struct {
+	BaseCurrency entity.Currency `form:"baseCurrency" binding:"omitempty,oneof=BRL USD"`
+	From         *time.Time      `form:"from" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	To           *time.Time      `form:"to" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type CurrencyTotalOutputDTO struct {
+	Currency       entity.Currency `json:"currency"`
+	AuctionsSold   int             `json:"auctions_sold"`
+	Total          entity.Money    `json:"total"`
+	ConvertedTotal entity.Money    `json:"converted_total"`
+}
+
+type SalesReportOutputDTO struct {
+	From            time.Time                `json:"from"`
+	To              time.Time                `json:"to"`
+	BaseCurrency    entity.Currency          `json:"base_currency"`
+	AuctionsSold    int                      `json:"auctions_sold"`
+	Totals          []CurrencyTotalOutputDTO `json:"totals"`
+	NormalizedTotal entity.Money             `json:"normalized_total"`
+}
+
+type SalesReportUseCase struct {
+	auctionRepository      entity.AuctionRepositoryInterface
+	exchangeRateRepository entity.ExchangeRateRepositoryInterface
+}
+
+func NewSalesReportUseCase(
+	auctionRepository entity.AuctionRepositoryInterface,
+	exchangeRateRepository entity.ExchangeRateRepositoryInterface,
+) *SalesReportUseCase {
+	return &SalesReportUseCase{
+		auctionRepository:      auctionRepository,
+		exchangeRateRepository: exchangeRateRepository,
+	}
+}
+
+// Execute soma o valor vendido dos leilões encerrados no período, por moeda e normalizado
+// para a moeda base. Cada venda é convertida pela cotação vigente no encerramento do leilão.
+func (ru *SalesReportUseCase) Execute(ctx context.Context, input SalesReportInputDTO) (*SalesReportOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "SalesReportUseCase.Execute")
+	defer span.End()
+
+	if input.From.After(*input.To) {
+		return nil, internal_error.NewBadRequestError("from must not be later than to")
+	}
+
+	baseCurrency := input.BaseCurrency.OrDefault()
+	completed := entity.Completed
+	minPrice := entity.NewMoney(1, "")
+
+	// Leilões sem lances têm preço zero e não entram no relatório
+	filter := entity.AuctionSearchFilter{
+		Status:       &completed,
+		MinPrice:     &minPrice,
+		EndingAfter:  input.From,
+		EndingBefore: input.To,
+	}
+	page := entity.PageRequest{Limit: entity.MaxPageLimit, SortBy: "expires_at", Order: entity.Ascending}
+
+	output := &SalesReportOutputDTO{
+		From:            *input.From,
+		To:              *input.To,
+		BaseCurrency:    baseCurrency,
+		Totals:          []CurrencyTotalOutputDTO{},
+		NormalizedTotal: entity.NewMoney(0, baseCurrency),
+	}
+	totals := make(map[entity.Currency]*CurrencyTotalOutputDTO)
+
+	for {
+		auctions, nextCursor, err := ru.auctionRepository.SearchAuctions(ctx, filter, page)
+		if err != nil {
+			return nil, internal_error.FromError(err)
+		}
+
+		for _, auction := range auctions {
+			rate, err := ru.exchangeRateRepository.FindEffectiveRate(ctx, auction.Currency, baseCurrency, auction.ExpiresAt)
+			if err != nil {
+				return nil, internal_error.FromError(err)
+			}
+
+			total, ok := totals[auction.Currency]
+			if !ok {
+				total = &CurrencyTotalOutputDTO{
+					Currency:       auction.Currency,
+					Total:          entity.NewMoney(0, auction.Currency),
+					ConvertedTotal: entity.NewMoney(0, baseCurrency),
+				}
+				totals[auction.Currency] = total
+			}
+
+			converted := rate.Convert(auction.CurrentPrice)
+			total.AuctionsSold++
+			total.Total = total.Total.Add(auction.CurrentPrice)
+			total.ConvertedTotal = total.ConvertedTotal.Add(converted)
+
+			output.AuctionsSold++
+			output.NormalizedTotal = output.NormalizedTotal.Add(converted)
+		}
+
+		if nextCursor == "" {
+			break
+		}
+		page.Cursor = nextCursor
+	}
+
+	for _, currency := range entity.SupportedCurrencies {
+		if total, ok := totals[currency]; ok {
+			output.Totals = append(output.Totals, *total)
+		}
+	}
+
+	return output, nil
+}
@@ -0,0 +1,464 @@
+package settlement_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer abre os spans dos use cases, filhos do span da requisição
+var tracer = otel.Tracer("github.com/auction-goexpert/internal/usecase/settlement_usecase")
+
+// pendingOfferGrace é quanto uma tentativa recém-aberta espera antes de ter a reserva e os
+// avisos retomados, para não concorrer com quem a abriu
+const pendingOfferGrace = time.Minute
+
+type SettlementActionInputDTO struct {
+	UserId string `json:"user_id" binding:"required"`
+}
+
+type ShipSettlementInputDTO struct {
+	UserId       string `json:"user_id" binding:"required"`
+	TrackingCode string `json:"tracking_code" binding:"max=100"`
+}
+
+type DisputeSettlementInputDTO struct {
+	UserId string `json:"user_id" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type SettlementOutputDTO struct {
+	Id              string                  `json:"id"`
+	AuctionId       string                  `json:"auction_id"`
+	Attempt         int                     `json:"attempt"`
+	SellerId        string                  `json:"seller_id,omitempty"`
+	WinnerId        string                  `json:"winner_id"`
+	BidId           string                  `json:"bid_id"`
+	Amount          entity.Money            `json:"amount"`
+	Currency        entity.Currency         `json:"currency"`
+	Status          entity.SettlementStatus `json:"status"`
+	PaymentDeadline time.Time               `json:"payment_deadline"`
+	TrackingCode    string                  `json:"tracking_code,omitempty"`
+	DisputeReason   string                  `json:"dispute_reason,omitempty"`
+	DisputedBy      string                  `json:"disputed_by,omitempty"`
+	CreatedAt       time.Time               `json:"created_at"`
+	UpdatedAt       time.Time               `json:"updated_at"`
+}
+
+// AuctionSettlementOutputDTO traz a tentativa atual e todas as tentativas do leilão, em ordem
+type AuctionSettlementOutputDTO struct {
+	AuctionId string                `json:"auction_id"`
+	Current   SettlementOutputDTO   `json:"current"`
+	Attempts  []SettlementOutputDTO `json:"attempts"`
+}
+
+type SettlementUseCase struct {
+	settlementRepository entity.SettlementRepositoryInterface
+	bidRepository        entity.BidRepositoryInterface
+	walletRepository     entity.WalletRepositoryInterface
+	paymentWindow        time.Duration
+	maxAttempts          int
+	offeredListeners     []entity.SettlementOfferedListener
+}
+
+func NewSettlementUseCase(
+	settlementRepository entity.SettlementRepositoryInterface,
+	bidRepository entity.BidRepositoryInterface,
+	walletRepository entity.WalletRepositoryInterface,
+	paymentWindow time.Duration,
+	maxAttempts int,
+) *SettlementUseCase {
+	return &SettlementUseCase{
+		settlementRepository: settlementRepository,
+		bidRepository:        bidRepository,
+		walletRepository:     walletRepository,
+		paymentWindow:        paymentWindow,
+		maxAttempts:          maxAttempts,
+	}
+}
+
+// AddOfferedListener registra uma função chamada a cada tentativa de acerto aberta.
+// Deve ser chamado durante a inicialização.
+func (su *SettlementUseCase) AddOfferedListener(listener entity.SettlementOfferedListener) {
+	su.offeredListeners = append(su.offeredListeners, listener)
+}
+
+// OnAuctionClosed é registrado como AuctionClosedListener: abre a primeira tentativa de
+// acerto com o autor do lance vencedor. Leilões sem lances ou com a reserva não alcançada
+// não têm acerto; o vendedor pode fazer ofertas de segunda chance.
+func (su *SettlementUseCase) OnAuctionClosed(ctx context.Context, auction entity.Auction) error {
+	ctx, span := tracer.Start(ctx, "SettlementUseCase.OnAuctionClosed", trace.WithAttributes(
+		attribute.String("auction.id", auction.Id),
+	))
+	defer span.End()
+
+	if !auction.ReserveMet() {
+		return nil
+	}
+
+	winningBid, err := su.bidRepository.FindWinningBidByAuctionId(ctx, auction.Id)
+	if err != nil {
+		return err
+	}
+	if winningBid == nil {
+		return nil
+	}
+
+	return su.offer(ctx, entity.NewSettlement(auction.SellerId, *winningBid, 1, su.paymentWindow))
+}
+
+// OpenAttempt abre a próxima tentativa de acerto do leilão com o autor do lance, como ao
+// aceitar uma oferta de segunda chance. É o único caminho, além do encerramento e da
+// expiração, que troca o comprador, então a reserva e os listeners seguem a tentativa nova.
+// Retorna ErrSettlementAttemptTaken se outra tentativa foi aberta com o mesmo número; depois
+// de aberta, uma falha na reserva não é erro, porque ela é retomada por ExpireOverduePayments.
+func (su *SettlementUseCase) OpenAttempt(ctx context.Context, sellerId string, bid entity.Bid) error {
+	ctx, span := tracer.Start(ctx, "SettlementUseCase.OpenAttempt", trace.WithAttributes(
+		attribute.String("auction.id", bid.AuctionId),
+	))
+	defer span.End()
+
+	settlements, err := su.settlementRepository.FindSettlementsByAuctionId(ctx, bid.AuctionId)
+	if err != nil {
+		return err
+	}
+
+	settlement := entity.NewSettlement(sellerId, bid, len(settlements)+1, su.paymentWindow)
+	created, err := su.settlementRepository.CreateSettlement(ctx, settlement)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return entity.ErrSettlementAttemptTaken
+	}
+
+	if err := su.completeOffer(ctx, *settlement); err != nil {
+		span.RecordError(err)
+	}
+	return nil
+}
+
+// FindSettlement retorna o acerto do leilão
+func (su *SettlementUseCase) FindSettlement(ctx context.Context, auctionId string) (*AuctionSettlementOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "SettlementUseCase.FindSettlement", trace.WithAttributes(
+		attribute.String("auction.id", auctionId),
+	))
+	defer span.End()
+
+	settlements, err := su.settlementRepository.FindSettlementsByAuctionId(ctx, auctionId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+	if len(settlements) == 0 {
+		return nil, internal_error.FromError(entity.ErrSettlementNotFound)
+	}
+
+	return toAuctionSettlementOutput(auctionId, settlements), nil
+}
+
+// Pay registra o pagamento do vencedor. A tentativa passa primeiro para capturing, o que
+// impede que a expiração a encerre durante a captura, e só então o valor é capturado na
+// carteira. Se a captura falha a tentativa volta a aguardar pagamento; sem saldo para cobrir
+// o valor, o pagamento é recusado com ErrInsufficientFunds. Se a tentativa não puder ser
+// gravada como paga depois da captura, o valor capturado é devolvido ao comprador.
+func (su *SettlementUseCase) Pay(ctx context.Context, auctionId string, input SettlementActionInputDTO) (*AuctionSettlementOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "SettlementUseCase.Pay", trace.WithAttributes(
+		attribute.String("auction.id", auctionId),
+	))
+	defer span.End()
+
+	settlements, internalErr := su.findSettlements(ctx, auctionId)
+	if internalErr != nil {
+		return nil, internalErr
+	}
+
+	current := &settlements[len(settlements)-1]
+	if internalErr := su.transition(ctx, current, func(settlement *entity.Settlement, now time.Time) error {
+		return settlement.StartPayment(input.UserId, now)
+	}); internalErr != nil {
+		return nil, internalErr
+	}
+
+	if err := su.walletRepository.CaptureSettlement(ctx, *current); err != nil {
+		// Nada foi capturado: o comprador pode tentar de novo dentro do prazo
+		if cancelErr := su.transition(ctx, current, func(settlement *entity.Settlement, now time.Time) error {
+			return settlement.CancelPayment(now)
+		}); cancelErr != nil {
+			return nil, internal_error.FromError(errors.Join(err, cancelErr))
+		}
+		return nil, internal_error.FromError(err)
+	}
+
+	if internalErr := su.transition(ctx, current, func(settlement *entity.Settlement, now time.Time) error {
+		return settlement.CompletePayment(now)
+	}); internalErr != nil {
+		// A expiração encerrou a tentativa durante a captura, ou a gravação falhou: o valor
+		// volta ao comprador, e a tentativa parada em capturing expira no prazo
+		if err := su.walletRepository.RefundSettlement(ctx, *current); err != nil {
+			return nil, internal_error.FromError(errors.Join(internalErr, fmt.Errorf("refunding user %s: %w", current.WinnerId, err)))
+		}
+		return nil, internalErr
+	}
+
+	return toAuctionSettlementOutput(auctionId, settlements), nil
+}
+
+// Ship registra o envio do item pelo vendedor
+func (su *SettlementUseCase) Ship(ctx context.Context, auctionId string, input ShipSettlementInputDTO) (*AuctionSettlementOutputDTO, *internal_error.InternalError) {
+	return su.advance(ctx, "SettlementUseCase.Ship", auctionId, func(settlement *entity.Settlement, now time.Time) error {
+		return settlement.Ship(input.UserId, input.TrackingCode, now)
+	})
+}
+
+// ConfirmDelivery registra o recebimento do item pelo vencedor
+func (su *SettlementUseCase) ConfirmDelivery(ctx context.Context, auctionId string, input SettlementActionInputDTO) (*AuctionSettlementOutputDTO, *internal_error.InternalError) {
+	return su.advance(ctx, "SettlementUseCase.ConfirmDelivery", auctionId, func(settlement *entity.Settlement, now time.Time) error {
+		return settlement.ConfirmDelivery(input.UserId, now)
+	})
+}
+
+// Dispute abre uma disputa do vencedor ou do vendedor
+func (su *SettlementUseCase) Dispute(ctx context.Context, auctionId string, input DisputeSettlementInputDTO) (*AuctionSettlementOutputDTO, *internal_error.InternalError) {
+	return su.advance(ctx, "SettlementUseCase.Dispute", auctionId, func(settlement *entity.Settlement, now time.Time) error {
+		return settlement.Dispute(input.UserId, input.Reason, now)
+	})
+}
+
+// ExpireOverduePayments expira as tentativas sem pagamento no prazo, liberando a reserva de
+// quem não pagou, e, até o limite de tentativas, oferece o item ao autor do maior lance entre
+// os usuários que ainda não tiveram uma tentativa. As tentativas expiradas em que o estorno ou
+// a passagem ao próximo licitante falhou, e as abertas em que a reserva ou os avisos falharam,
+// são retomadas a cada execução.
+func (su *SettlementUseCase) ExpireOverduePayments(ctx context.Context, now time.Time) error {
+	overdue, err := su.settlementRepository.FindOverdueSettlements(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, settlement := range overdue {
+		if err := su.expire(ctx, settlement, now); err != nil {
+			errs = append(errs, fmt.Errorf("auction %s, attempt %d: %w", settlement.AuctionId, settlement.Attempt, err))
+		}
+	}
+
+	pending, err := su.settlementRepository.FindPendingExpiries(ctx)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, settlement := range pending {
+		if err := su.completeExpiry(ctx, settlement, now); err != nil {
+			errs = append(errs, fmt.Errorf("auction %s, attempt %d: %w", settlement.AuctionId, settlement.Attempt, err))
+		}
+	}
+
+	offers, err := su.settlementRepository.FindPendingOffers(ctx, now.Add(-pendingOfferGrace))
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, settlement := range offers {
+		if err := su.completeOffer(ctx, settlement); err != nil {
+			errs = append(errs, fmt.Errorf("auction %s, attempt %d: %w", settlement.AuctionId, settlement.Attempt, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (su *SettlementUseCase) expire(ctx context.Context, settlement entity.Settlement, now time.Time) error {
+	previous := settlement.Status
+	if err := settlement.Expire(now); err != nil {
+		return err
+	}
+
+	// Se o vencedor pagou entre a busca e a atualização, a tentativa segue valendo
+	updated, err := su.settlementRepository.UpdateSettlement(ctx, &settlement, previous)
+	if err != nil || !updated {
+		return err
+	}
+
+	return su.completeExpiry(ctx, settlement, now)
+}
+
+// completeExpiry estorna quem não pagou e passa o item ao próximo licitante; só quando as
+// duas etapas terminam a tentativa deixa de ter a expiração pendente. As duas podem ser
+// repetidas: o estorno e a tentativa seguinte são gravados uma única vez.
+func (su *SettlementUseCase) completeExpiry(ctx context.Context, settlement entity.Settlement, now time.Time) error {
+	refundErr := su.walletRepository.RefundSettlement(ctx, settlement)
+	if refundErr != nil {
+		refundErr = fmt.Errorf("refunding user %s: %w", settlement.WinnerId, refundErr)
+	}
+
+	var offerErr error
+	if settlement.Attempt < su.maxAttempts {
+		offerErr = su.offerNextBidder(ctx, settlement)
+	}
+	if err := errors.Join(refundErr, offerErr); err != nil {
+		return err
+	}
+
+	if err := settlement.CompleteExpiry(now); err != nil {
+		return err
+	}
+	_, err := su.settlementRepository.UpdateSettlement(ctx, &settlement, entity.SettlementExpired)
+	return err
+}
+
+// offerNextBidder abre a tentativa seguinte com o maior lance entre os usuários que ainda não
+// tiveram uma tentativa
+func (su *SettlementUseCase) offerNextBidder(ctx context.Context, settlement entity.Settlement) error {
+	settlements, err := su.settlementRepository.FindSettlementsByAuctionId(ctx, settlement.AuctionId)
+	if err != nil {
+		return err
+	}
+
+	excludedUserIds := make([]string, 0, len(settlements))
+	for _, attempt := range settlements {
+		excludedUserIds = append(excludedUserIds, attempt.WinnerId)
+	}
+
+	nextBid, err := su.bidRepository.FindNextHighestBid(ctx, settlement.AuctionId, excludedUserIds)
+	if err != nil || nextBid == nil {
+		return err
+	}
+
+	return su.offer(ctx, entity.NewSettlement(settlement.SellerId, *nextBid, settlement.Attempt+1, su.paymentWindow))
+}
+
+// offer grava a tentativa, reserva o valor na carteira do comprador e avisa os listeners;
+// uma tentativa já criada não é oferecida de novo, e a que ficou pendente é retomada por
+// ExpireOverduePayments
+func (su *SettlementUseCase) offer(ctx context.Context, settlement *entity.Settlement) error {
+	created, err := su.settlementRepository.CreateSettlement(ctx, settlement)
+	if err != nil || !created {
+		return err
+	}
+	return su.completeOffer(ctx, *settlement)
+}
+
+// completeOffer reserva o valor e avisa os listeners da tentativa aberta; só quando os dois
+// terminam ela deixa de ter a oferta pendente. A reserva só é feita enquanto a tentativa
+// aguarda pagamento, para não prender o valor de quem já pagou ou foi estornado, e pode ser
+// repetida: é gravada uma única vez.
+func (su *SettlementUseCase) completeOffer(ctx context.Context, settlement entity.Settlement) error {
+	if settlement.Status == entity.SettlementAwaitingPayment {
+		if err := su.holdAndNotify(ctx, settlement); err != nil {
+			return err
+		}
+	}
+
+	// Se outra ação alterou a tentativa nesse meio tempo, a marca fica e é retomada depois
+	previous := settlement.Status
+	settlement.CompleteOffer(time.Now())
+	_, err := su.settlementRepository.UpdateSettlement(ctx, &settlement, previous)
+	return err
+}
+
+// holdAndNotify reserva o valor da tentativa recém-aberta e avisa os listeners. Sem saldo a
+// tentativa segue aberta: o comprador pode depositar e a reserva é refeita no pagamento.
+func (su *SettlementUseCase) holdAndNotify(ctx context.Context, settlement entity.Settlement) error {
+	errs := make([]error, 0, 1)
+	holdErr := su.walletRepository.HoldSettlement(ctx, settlement)
+	if holdErr != nil && !errors.Is(holdErr, entity.ErrInsufficientFunds) {
+		errs = append(errs, fmt.Errorf("holding funds of user %s: %w", settlement.WinnerId, holdErr))
+	}
+
+	for _, listener := range su.offeredListeners {
+		if err := listener(ctx, settlement); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// advance aplica a ação na tentativa atual do leilão e grava o resultado, desde que outra
+// ação não tenha alterado a tentativa nesse meio tempo
+func (su *SettlementUseCase) advance(
+	ctx context.Context,
+	spanName string,
+	auctionId string,
+	action func(settlement *entity.Settlement, now time.Time) error,
+) (*AuctionSettlementOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, spanName, trace.WithAttributes(
+		attribute.String("auction.id", auctionId),
+	))
+	defer span.End()
+
+	settlements, internalErr := su.findSettlements(ctx, auctionId)
+	if internalErr != nil {
+		return nil, internalErr
+	}
+
+	if internalErr := su.transition(ctx, &settlements[len(settlements)-1], action); internalErr != nil {
+		return nil, internalErr
+	}
+
+	return toAuctionSettlementOutput(auctionId, settlements), nil
+}
+
+// findSettlements busca as tentativas do leilão; sem nenhuma retorna ErrSettlementNotFound
+func (su *SettlementUseCase) findSettlements(ctx context.Context, auctionId string) ([]entity.Settlement, *internal_error.InternalError) {
+	settlements, err := su.settlementRepository.FindSettlementsByAuctionId(ctx, auctionId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+	if len(settlements) == 0 {
+		return nil, internal_error.FromError(entity.ErrSettlementNotFound)
+	}
+	return settlements, nil
+}
+
+// transition aplica a ação na tentativa e a grava somente se ela continua no status lido
+func (su *SettlementUseCase) transition(
+	ctx context.Context,
+	settlement *entity.Settlement,
+	action func(settlement *entity.Settlement, now time.Time) error,
+) *internal_error.InternalError {
+	previous := settlement.Status
+	if err := action(settlement, time.Now()); err != nil {
+		return internal_error.FromError(err)
+	}
+
+	updated, err := su.settlementRepository.UpdateSettlement(ctx, settlement, previous)
+	if err != nil {
+		return internal_error.FromError(err)
+	}
+	if !updated {
+		return internal_error.FromError(entity.ErrInvalidSettlementTransition.WithMessage("settlement was modified concurrently, reload it and retry"))
+	}
+	return nil
+}
+
+func toAuctionSettlementOutput(auctionId string, settlements []entity.Settlement) *AuctionSettlementOutputDTO {
+	output := &AuctionSettlementOutputDTO{
+		AuctionId: auctionId,
+		Attempts:  make([]SettlementOutputDTO, 0, len(settlements)),
+	}
+	for _, settlement := range settlements {
+		output.Attempts = append(output.Attempts, SettlementOutputDTO{
+			Id:              settlement.Id,
+			AuctionId:       settlement.AuctionId,
+			Attempt:         settlement.Attempt,
+			SellerId:        settlement.SellerId,
+			WinnerId:        settlement.WinnerId,
+			BidId:           settlement.BidId,
+			Amount:          settlement.Amount,
+			Currency:        settlement.Amount.Currency,
+			Status:          settlement.Status,
+			PaymentDeadline: settlement.PaymentDeadline,
+			TrackingCode:    settlement.TrackingCode,
+			DisputeReason:   settlement.DisputeReason,
+			DisputedBy:      settlement.DisputedBy,
+			CreatedAt:       settlement.CreatedAt,
+			UpdatedAt:       settlement.UpdatedAt,
+		})
+	}
+	output.Current = output.Attempts[len(output.Attempts)-1]
+	return output
+}
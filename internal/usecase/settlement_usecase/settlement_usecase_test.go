@@ -0,0 +1,368 @@
+package settlement_usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type settlementRepository struct {
+	entity.SettlementRepositoryInterface
+	settlements []entity.Settlement
+}
+
+func (r *settlementRepository) CreateSettlement(ctx context.Context, settlement *entity.Settlement) (bool, error) {
+	for _, existing := range r.settlements {
+		if existing.Id == settlement.Id {
+			return false, nil
+		}
+	}
+	r.settlements = append(r.settlements, *settlement)
+	return true, nil
+}
+
+func (r *settlementRepository) FindSettlementsByAuctionId(ctx context.Context, auctionId string) ([]entity.Settlement, error) {
+	return append([]entity.Settlement(nil), r.settlements...), nil
+}
+
+func (r *settlementRepository) UpdateSettlement(ctx context.Context, settlement *entity.Settlement, expectedStatus entity.SettlementStatus) (bool, error) {
+	for i, existing := range r.settlements {
+		if existing.Id == settlement.Id && existing.Status == expectedStatus {
+			r.settlements[i] = *settlement
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *settlementRepository) FindOverdueSettlements(ctx context.Context, now time.Time) ([]entity.Settlement, error) {
+	var overdue []entity.Settlement
+	for _, settlement := range r.settlements {
+		overdueStatus := settlement.Status == entity.SettlementAwaitingPayment || settlement.Status == entity.SettlementCapturing
+		if overdueStatus && now.After(settlement.PaymentDeadline) {
+			overdue = append(overdue, settlement)
+		}
+	}
+	return overdue, nil
+}
+
+func (r *settlementRepository) FindPendingExpiries(ctx context.Context) ([]entity.Settlement, error) {
+	var pending []entity.Settlement
+	for _, settlement := range r.settlements {
+		if settlement.Status == entity.SettlementExpired && settlement.ExpiryPending {
+			pending = append(pending, settlement)
+		}
+	}
+	return pending, nil
+}
+
+func (r *settlementRepository) FindPendingOffers(ctx context.Context, createdBefore time.Time) ([]entity.Settlement, error) {
+	var pending []entity.Settlement
+	for _, settlement := range r.settlements {
+		if settlement.OfferPending && !settlement.CreatedAt.After(createdBefore) {
+			pending = append(pending, settlement)
+		}
+	}
+	return pending, nil
+}
+
+// bidRepository devolve os lances na ordem; nextErr, se definido, é retornado pelas buscas
+type bidRepository struct {
+	entity.BidRepositoryInterface
+	bids    []entity.Bid
+	nextErr error
+}
+
+func (r *bidRepository) FindWinningBidByAuctionId(ctx context.Context, auctionId string) (*entity.Bid, error) {
+	return r.FindNextHighestBid(ctx, auctionId, nil)
+}
+
+// FindNextHighestBid considera os lances em ordem decrescente de valor
+func (r *bidRepository) FindNextHighestBid(ctx context.Context, auctionId string, excludedUserIds []string) (*entity.Bid, error) {
+	if r.nextErr != nil {
+		return nil, r.nextErr
+	}
+	for _, bid := range r.bids {
+		excluded := false
+		for _, userId := range excludedUserIds {
+			excluded = excluded || bid.UserId == userId
+		}
+		if !excluded {
+			return &bid, nil
+		}
+	}
+	return nil, nil
+}
+
+// walletRepository guarda a reserva de cada usuário no leilão e o total capturado;
+// afterCapture, se definido, é chamado depois de cada captura, e holdErr é retornado pelas
+// reservas
+type walletRepository struct {
+	entity.WalletRepositoryInterface
+	available    map[string]int64
+	holds        map[string]entity.Hold
+	captured     map[string]int64
+	afterCapture func()
+	holdErr      error
+}
+
+func (r *walletRepository) HoldSettlement(ctx context.Context, settlement entity.Settlement) error {
+	if r.holdErr != nil {
+		return r.holdErr
+	}
+	current := r.holds[settlement.WinnerId]
+	increase := settlement.Amount.Cents - current.Amount
+	if increase > r.available[settlement.WinnerId] {
+		return entity.ErrInsufficientFunds
+	}
+	r.available[settlement.WinnerId] -= increase
+	r.holds[settlement.WinnerId] = entity.Hold{BidId: settlement.BidId, Amount: settlement.Amount.Cents}
+	return nil
+}
+
+func (r *walletRepository) CaptureSettlement(ctx context.Context, settlement entity.Settlement) error {
+	if err := r.HoldSettlement(ctx, settlement); err != nil {
+		return err
+	}
+	r.captured[settlement.WinnerId] += r.holds[settlement.WinnerId].Amount
+	delete(r.holds, settlement.WinnerId)
+	if r.afterCapture != nil {
+		r.afterCapture()
+	}
+	return nil
+}
+
+func (r *walletRepository) RefundSettlement(ctx context.Context, settlement entity.Settlement) error {
+	if hold := r.holds[settlement.WinnerId]; hold.BidId == settlement.BidId {
+		r.available[settlement.WinnerId] += hold.Amount
+		delete(r.holds, settlement.WinnerId)
+	}
+	r.available[settlement.WinnerId] += r.captured[settlement.WinnerId]
+	delete(r.captured, settlement.WinnerId)
+	return nil
+}
+
+func TestDefaultedWinnerIsRefundedAndFallbackBidderPays(t *testing.T) {
+	auction := entity.Auction{Id: "auction-1", SellerId: "seller-1"}
+	bids := &bidRepository{bids: []entity.Bid{
+		{Id: "bid-2", UserId: "winner", AuctionId: auction.Id, Amount: entity.NewMoney(9000, entity.BRL)},
+		{Id: "bid-1", UserId: "runner-up", AuctionId: auction.Id, Amount: entity.NewMoney(8000, entity.BRL)},
+	}}
+	// O vencedor tem a reserva do lance vencedor; a do segundo colocado foi liberada quando
+	// ele foi superado
+	wallets := &walletRepository{
+		available: map[string]int64{"winner": 1000, "runner-up": 8500},
+		holds:     map[string]entity.Hold{"winner": {BidId: "bid-2", Amount: 9000}},
+		captured:  map[string]int64{},
+	}
+	settlements := &settlementRepository{}
+	useCase := NewSettlementUseCase(settlements, bids, wallets, time.Hour, 3)
+
+	var offered []string
+	useCase.AddOfferedListener(func(ctx context.Context, settlement entity.Settlement) error {
+		offered = append(offered, settlement.WinnerId)
+		return nil
+	})
+
+	ctx := context.Background()
+	require.NoError(t, useCase.OnAuctionClosed(ctx, auction))
+	assert.Equal(t, entity.Hold{BidId: "bid-2", Amount: 9000}, wallets.holds["winner"])
+
+	// O vencedor não paga: a reserva dele volta ao saldo e o segundo colocado recebe a
+	// tentativa 2, com o valor do lance dele reservado
+	require.NoError(t, useCase.ExpireOverduePayments(ctx, time.Now().Add(2*time.Hour)))
+	assert.Equal(t, int64(10000), wallets.available["winner"])
+	assert.NotContains(t, wallets.holds, "winner")
+	assert.Equal(t, entity.Hold{BidId: "bid-1", Amount: 8000}, wallets.holds["runner-up"])
+	assert.Equal(t, int64(500), wallets.available["runner-up"])
+	assert.Equal(t, []string{"winner", "runner-up"}, offered)
+
+	// O vencedor que desistiu não paga mais a tentativa encerrada
+	_, internalErr := useCase.Pay(ctx, auction.Id, SettlementActionInputDTO{UserId: "winner"})
+	require.NotNil(t, internalErr)
+	assert.Equal(t, http.StatusForbidden, internalErr.Code)
+
+	output, internalErr := useCase.Pay(ctx, auction.Id, SettlementActionInputDTO{UserId: "runner-up"})
+	require.Nil(t, internalErr)
+	assert.Equal(t, entity.SettlementPaid, output.Current.Status)
+	assert.Equal(t, 2, output.Current.Attempt)
+	assert.Equal(t, int64(8000), wallets.captured["runner-up"])
+	assert.Empty(t, wallets.captured["winner"])
+}
+
+func TestFailedFallbackIsRetriedOnTheNextRun(t *testing.T) {
+	auction := entity.Auction{Id: "auction-1", SellerId: "seller-1"}
+	bids := &bidRepository{bids: []entity.Bid{
+		{Id: "bid-2", UserId: "winner", AuctionId: auction.Id, Amount: entity.NewMoney(9000, entity.BRL)},
+		{Id: "bid-1", UserId: "runner-up", AuctionId: auction.Id, Amount: entity.NewMoney(8000, entity.BRL)},
+	}}
+	wallets := &walletRepository{available: map[string]int64{"winner": 9000, "runner-up": 8000}, holds: map[string]entity.Hold{}, captured: map[string]int64{}}
+	settlements := &settlementRepository{}
+	useCase := NewSettlementUseCase(settlements, bids, wallets, time.Hour, 3)
+
+	ctx := context.Background()
+	require.NoError(t, useCase.OnAuctionClosed(ctx, auction))
+
+	// A busca do próximo licitante falha: a tentativa expira, mas segue pendente
+	bids.nextErr = errors.New("connection reset")
+	later := time.Now().Add(2 * time.Hour)
+	require.Error(t, useCase.ExpireOverduePayments(ctx, later))
+	require.Len(t, settlements.settlements, 1)
+	assert.Equal(t, entity.SettlementExpired, settlements.settlements[0].Status)
+	assert.True(t, settlements.settlements[0].ExpiryPending)
+
+	// A execução seguinte abre a tentativa com o segundo colocado e encerra a pendência
+	bids.nextErr = nil
+	require.NoError(t, useCase.ExpireOverduePayments(ctx, later))
+	require.Len(t, settlements.settlements, 2)
+	assert.False(t, settlements.settlements[0].ExpiryPending)
+	assert.Equal(t, "runner-up", settlements.settlements[1].WinnerId)
+	assert.Equal(t, entity.Hold{BidId: "bid-1", Amount: 8000}, wallets.holds["runner-up"])
+}
+
+func TestFailedOfferIsRetriedOnTheNextRun(t *testing.T) {
+	auction := entity.Auction{Id: "auction-1", SellerId: "seller-1"}
+	bids := &bidRepository{bids: []entity.Bid{
+		{Id: "bid-1", UserId: "winner", AuctionId: auction.Id, Amount: entity.NewMoney(9000, entity.BRL)},
+	}}
+	wallets := &walletRepository{available: map[string]int64{"winner": 9000}, holds: map[string]entity.Hold{}, captured: map[string]int64{}}
+	settlements := &settlementRepository{}
+	useCase := NewSettlementUseCase(settlements, bids, wallets, time.Hour, 3)
+
+	var offered []string
+	var invoiceErr error
+	useCase.AddOfferedListener(func(ctx context.Context, settlement entity.Settlement) error {
+		offered = append(offered, settlement.WinnerId)
+		return invoiceErr
+	})
+
+	// A reserva e a fatura falham: a tentativa fica aberta com a oferta pendente, e o
+	// encerramento retomado não a abre de novo
+	ctx := context.Background()
+	wallets.holdErr = errors.New("connection reset")
+	invoiceErr = errors.New("connection reset")
+	require.Error(t, useCase.OnAuctionClosed(ctx, auction))
+	require.NoError(t, useCase.OnAuctionClosed(ctx, auction))
+	require.Len(t, settlements.settlements, 1)
+	assert.True(t, settlements.settlements[0].OfferPending)
+
+	// Recém-aberta, a tentativa ainda não é retomada
+	wallets.holdErr = nil
+	invoiceErr = nil
+	require.NoError(t, useCase.ExpireOverduePayments(ctx, time.Now()))
+	assert.True(t, settlements.settlements[0].OfferPending)
+
+	require.NoError(t, useCase.ExpireOverduePayments(ctx, time.Now().Add(2*pendingOfferGrace)))
+	assert.False(t, settlements.settlements[0].OfferPending)
+	assert.Equal(t, entity.Hold{BidId: "bid-1", Amount: 9000}, wallets.holds["winner"])
+	assert.Equal(t, []string{"winner", "winner"}, offered)
+}
+
+func TestPayWithoutFundsKeepsSettlementAwaitingPayment(t *testing.T) {
+	auction := entity.Auction{Id: "auction-1", SellerId: "seller-1"}
+	bids := &bidRepository{bids: []entity.Bid{
+		{Id: "bid-1", UserId: "winner", AuctionId: auction.Id, Amount: entity.NewMoney(9000, entity.BRL)},
+	}}
+	wallets := &walletRepository{available: map[string]int64{}, holds: map[string]entity.Hold{}, captured: map[string]int64{}}
+	settlements := &settlementRepository{}
+	useCase := NewSettlementUseCase(settlements, bids, wallets, time.Hour, 3)
+
+	// Sem saldo a tentativa é aberta mesmo assim, e o pagamento é recusado até o depósito
+	ctx := context.Background()
+	require.NoError(t, useCase.OnAuctionClosed(ctx, auction))
+
+	_, internalErr := useCase.Pay(ctx, auction.Id, SettlementActionInputDTO{UserId: "winner"})
+	require.NotNil(t, internalErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, internalErr.Code)
+	assert.Equal(t, entity.SettlementAwaitingPayment, settlements.settlements[0].Status)
+
+	wallets.available["winner"] = 9000
+	output, internalErr := useCase.Pay(ctx, auction.Id, SettlementActionInputDTO{UserId: "winner"})
+	require.Nil(t, internalErr)
+	assert.Equal(t, entity.SettlementPaid, output.Current.Status)
+	assert.Equal(t, int64(9000), wallets.captured["winner"])
+}
+
+func TestPayRefundsTheCaptureWhenTheSettlementExpiredMeanwhile(t *testing.T) {
+	auction := entity.Auction{Id: "auction-1", SellerId: "seller-1"}
+	bids := &bidRepository{bids: []entity.Bid{
+		{Id: "bid-1", UserId: "winner", AuctionId: auction.Id, Amount: entity.NewMoney(9000, entity.BRL)},
+	}}
+	wallets := &walletRepository{available: map[string]int64{"winner": 9000}, holds: map[string]entity.Hold{}, captured: map[string]int64{}}
+	settlements := &settlementRepository{}
+	useCase := NewSettlementUseCase(settlements, bids, wallets, time.Hour, 1)
+
+	ctx := context.Background()
+	require.NoError(t, useCase.OnAuctionClosed(ctx, auction))
+
+	// Enquanto o valor é capturado a tentativa parada em capturing passa do prazo e o
+	// agendador a expira, então ela não pode mais ser gravada como paga
+	wallets.afterCapture = func() {
+		assert.Equal(t, entity.SettlementCapturing, settlements.settlements[0].Status)
+		require.NoError(t, useCase.ExpireOverduePayments(ctx, time.Now().Add(2*time.Hour)))
+	}
+
+	_, internalErr := useCase.Pay(ctx, auction.Id, SettlementActionInputDTO{UserId: "winner"})
+	require.NotNil(t, internalErr)
+	assert.Equal(t, http.StatusConflict, internalErr.Code)
+	assert.Equal(t, entity.SettlementExpired, settlements.settlements[0].Status)
+	assert.Empty(t, wallets.captured["winner"])
+	assert.Equal(t, int64(9000), wallets.available["winner"])
+}
+
+func TestOpenAttemptMovesTheBuyerToTheAcceptedOffer(t *testing.T) {
+	auction := entity.Auction{Id: "auction-1", SellerId: "seller-1"}
+	wallets := &walletRepository{available: map[string]int64{"runner-up": 7000}, holds: map[string]entity.Hold{}, captured: map[string]int64{}}
+	settlements := &settlementRepository{settlements: []entity.Settlement{
+		{Id: "auction-1:1", AuctionId: auction.Id, Attempt: 1, SellerId: "seller-1", WinnerId: "winner", Status: entity.SettlementExpired},
+	}}
+	useCase := NewSettlementUseCase(settlements, &bidRepository{}, wallets, time.Hour, 3)
+
+	var offered []entity.Settlement
+	useCase.AddOfferedListener(func(ctx context.Context, settlement entity.Settlement) error {
+		offered = append(offered, settlement)
+		return nil
+	})
+
+	bid := entity.Bid{Id: "bid-1", UserId: "runner-up", AuctionId: auction.Id, Amount: entity.NewMoney(6000, entity.BRL)}
+	require.NoError(t, useCase.OpenAttempt(context.Background(), auction.SellerId, bid))
+
+	require.Len(t, offered, 1)
+	assert.Equal(t, 2, offered[0].Attempt)
+	assert.Equal(t, "runner-up", offered[0].WinnerId)
+	assert.Equal(t, entity.Hold{BidId: "bid-1", Amount: 6000}, wallets.holds["runner-up"])
+	assert.Equal(t, int64(1000), wallets.available["runner-up"])
+}
+
+// staleSettlementRepository devolve as tentativas lidas antes de outra ser aberta
+type staleSettlementRepository struct {
+	*settlementRepository
+	stale []entity.Settlement
+}
+
+func (r *staleSettlementRepository) FindSettlementsByAuctionId(ctx context.Context, auctionId string) ([]entity.Settlement, error) {
+	return r.stale, nil
+}
+
+func TestOpenAttemptFailsWhenTheAttemptWasTaken(t *testing.T) {
+	expired := entity.Settlement{Id: "auction-1:1", AuctionId: "auction-1", Attempt: 1, WinnerId: "winner", Status: entity.SettlementExpired}
+	fallback := entity.Settlement{Id: "auction-1:2", AuctionId: "auction-1", Attempt: 2, WinnerId: "runner-up", Status: entity.SettlementAwaitingPayment}
+	settlements := &staleSettlementRepository{
+		settlementRepository: &settlementRepository{settlements: []entity.Settlement{expired, fallback}},
+		stale:                []entity.Settlement{expired},
+	}
+	wallets := &walletRepository{available: map[string]int64{}, holds: map[string]entity.Hold{}, captured: map[string]int64{}}
+	useCase := NewSettlementUseCase(settlements, &bidRepository{}, wallets, time.Hour, 3)
+
+	// A passagem automática abriu a tentativa 2 depois da leitura: o aceite não a sobrescreve
+	bid := entity.Bid{Id: "bid-0", UserId: "third", AuctionId: "auction-1", Amount: entity.NewMoney(5000, entity.BRL)}
+	err := useCase.OpenAttempt(context.Background(), "seller-1", bid)
+	assert.ErrorIs(t, err, entity.ErrSettlementAttemptTaken)
+	assert.Equal(t, "runner-up", settlements.settlements[1].WinnerId)
+}
@@ -0,0 +1,144 @@
+package wallet_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer abre os spans dos use cases, filhos do span da requisição
+var tracer = otel.Tracer("github.com/auction-goexpert/internal/usecase/wallet_usecase")
+
+// ledgerLimit é o número de transações mais recentes retornadas no extrato
+const ledgerLimit = 100
+
+type DepositInputDTO struct {
+	Amount   entity.Money    `json:"amount"`
+	Currency entity.Currency `json:"currency" binding:"omitempty,oneof=BRL USD"`
+}
+
+type BalanceOutputDTO struct {
+	Currency  entity.Currency `json:"currency"`
+	Available entity.Money    `json:"available"`
+	Held      entity.Money    `json:"held"`
+}
+
+type WalletOutputDTO struct {
+	UserId   string             `json:"user_id"`
+	Balances []BalanceOutputDTO `json:"balances"`
+}
+
+type LedgerEntryOutputDTO struct {
+	Account string       `json:"account"`
+	Amount  entity.Money `json:"amount"`
+}
+
+type LedgerTransactionOutputDTO struct {
+	Id        string                       `json:"id"`
+	Type      entity.LedgerTransactionType `json:"type"`
+	Currency  entity.Currency              `json:"currency"`
+	Sequence  int64                        `json:"sequence"`
+	AuctionId string                       `json:"auction_id,omitempty"`
+	BidId     string                       `json:"bid_id,omitempty"`
+	Entries   []LedgerEntryOutputDTO       `json:"entries"`
+	Available entity.Money                 `json:"available"`
+	Held      entity.Money                 `json:"held"`
+	CreatedAt time.Time                    `json:"created_at"`
+}
+
+type WalletUseCase struct {
+	walletRepository entity.WalletRepositoryInterface
+}
+
+func NewWalletUseCase(walletRepository entity.WalletRepositoryInterface) *WalletUseCase {
+	return &WalletUseCase{
+		walletRepository: walletRepository,
+	}
+}
+
+// Deposit credita o valor no saldo disponível do usuário; sem moeda informada usa a padrão
+func (wu *WalletUseCase) Deposit(ctx context.Context, userId string, input DepositInputDTO) (*LedgerTransactionOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "WalletUseCase.Deposit", trace.WithAttributes(
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	amount := entity.NewMoney(input.Amount.Cents, input.Currency.OrDefault())
+	transaction, err := wu.walletRepository.Deposit(ctx, userId, amount)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	output := toLedgerTransactionOutput(*transaction)
+	return &output, nil
+}
+
+// FindWallet retorna os saldos disponível e reservado do usuário em cada moeda
+func (wu *WalletUseCase) FindWallet(ctx context.Context, userId string) (*WalletOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "WalletUseCase.FindWallet", trace.WithAttributes(
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	wallets, err := wu.walletRepository.FindWallets(ctx, userId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	output := &WalletOutputDTO{UserId: userId, Balances: make([]BalanceOutputDTO, 0, len(wallets))}
+	for _, wallet := range wallets {
+		output.Balances = append(output.Balances, BalanceOutputDTO{
+			Currency:  wallet.Currency,
+			Available: wallet.Available,
+			Held:      wallet.Held,
+		})
+	}
+	return output, nil
+}
+
+// FindLedger retorna as transações mais recentes do usuário, da mais nova para a mais antiga
+func (wu *WalletUseCase) FindLedger(ctx context.Context, userId string) ([]LedgerTransactionOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "WalletUseCase.FindLedger", trace.WithAttributes(
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	transactions, err := wu.walletRepository.FindLedger(ctx, userId, ledgerLimit)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	output := make([]LedgerTransactionOutputDTO, 0, len(transactions))
+	for _, transaction := range transactions {
+		output = append(output, toLedgerTransactionOutput(transaction))
+	}
+	return output, nil
+}
+
+func toLedgerTransactionOutput(transaction entity.LedgerTransaction) LedgerTransactionOutputDTO {
+	entries := make([]LedgerEntryOutputDTO, 0, len(transaction.Entries))
+	for _, entry := range transaction.Entries {
+		entries = append(entries, LedgerEntryOutputDTO{
+			Account: entry.Account,
+			Amount:  entity.NewMoney(entry.Amount, transaction.Currency),
+		})
+	}
+
+	return LedgerTransactionOutputDTO{
+		Id:        transaction.Id,
+		Type:      transaction.Type,
+		Currency:  transaction.Currency,
+		Sequence:  transaction.Sequence,
+		AuctionId: transaction.AuctionId,
+		BidId:     transaction.BidId,
+		Entries:   entries,
+		Available: transaction.Available,
+		Held:      transaction.Held,
+		CreatedAt: transaction.CreatedAt,
+	}
+}
@@ -0,0 +1,149 @@
+package watchlist_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/auction-goexpert/internal/entity"
+	"github.com/auction-goexpert/internal/internal_error"
+	"go.opentelemetry.io/otel"
+)
+
+// tracer abre os spans dos use cases, filhos do span da requisição
+var tracer = otel.Tracer("github.com/auction-goexpert/internal/usecase/watchlist_usecase")
+
+type WatchlistItemOutputDTO struct {
+	AuctionId       string                  `json:"auction_id"`
+	ProductName     string                  `json:"product_name"`
+	Category        string                  `json:"category"`
+	Condition       entity.ProductCondition `json:"condition"`
+	Status          entity.AuctionStatus    `json:"status"`
+	ExpiresAt       time.Time               `json:"expires_at"`
+	TimeLeftSeconds int64                   `json:"time_left_seconds"`
+	HighestBid      *entity.Money           `json:"highest_bid"`
+	WatchedSince    time.Time               `json:"watched_since"`
+}
+
+type WatchlistUseCase struct {
+	watchlistRepository entity.WatchlistRepositoryInterface
+	auctionRepository   entity.AuctionRepositoryInterface
+	bidRepository       entity.BidRepositoryInterface
+}
+
+func NewWatchlistUseCase(
+	watchlistRepository entity.WatchlistRepositoryInterface,
+	auctionRepository entity.AuctionRepositoryInterface,
+	bidRepository entity.BidRepositoryInterface,
+) *WatchlistUseCase {
+	return &WatchlistUseCase{
+		watchlistRepository: watchlistRepository,
+		auctionRepository:   auctionRepository,
+		bidRepository:       bidRepository,
+	}
+}
+
+func (wu *WatchlistUseCase) AddToWatchlist(ctx context.Context, userId, auctionId string) *internal_error.InternalError {
+	ctx, span := tracer.Start(ctx, "WatchlistUseCase.AddToWatchlist")
+	defer span.End()
+
+	auction, err := wu.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return internal_error.FromError(err)
+	}
+
+	if auction == nil {
+		return internal_error.FromError(entity.ErrAuctionNotFound)
+	}
+
+	item, err := entity.CreateWatchlistItem(userId, auctionId)
+	if err != nil {
+		return internal_error.FromError(err)
+	}
+
+	if err := wu.watchlistRepository.AddToWatchlist(ctx, item); err != nil {
+		return internal_error.FromError(err)
+	}
+
+	return nil
+}
+
+func (wu *WatchlistUseCase) RemoveFromWatchlist(ctx context.Context, userId, auctionId string) *internal_error.InternalError {
+	ctx, span := tracer.Start(ctx, "WatchlistUseCase.RemoveFromWatchlist")
+	defer span.End()
+
+	removed, err := wu.watchlistRepository.RemoveFromWatchlist(ctx, userId, auctionId)
+	if err != nil {
+		return internal_error.FromError(err)
+	}
+
+	if !removed {
+		return internal_error.NewNotFoundError("auction is not in the watchlist")
+	}
+
+	return nil
+}
+
+// FindWatchlist lista os leilões acompanhados com o maior lance atual e o tempo restante
+func (wu *WatchlistUseCase) FindWatchlist(ctx context.Context, userId string) ([]WatchlistItemOutputDTO, *internal_error.InternalError) {
+	ctx, span := tracer.Start(ctx, "WatchlistUseCase.FindWatchlist")
+	defer span.End()
+
+	items, err := wu.watchlistRepository.FindWatchlistByUserId(ctx, userId)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	auctionIds := make([]string, 0, len(items))
+	for _, item := range items {
+		auctionIds = append(auctionIds, item.AuctionId)
+	}
+
+	auctions, err := wu.auctionRepository.FindAuctionsByIds(ctx, auctionIds)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	highestBids, err := wu.bidRepository.FindHighestBidsByAuctionIds(ctx, auctionIds)
+	if err != nil {
+		return nil, internal_error.FromError(err)
+	}
+
+	auctionsById := make(map[string]entity.Auction, len(auctions))
+	for _, auction := range auctions {
+		auctionsById[auction.Id] = auction
+	}
+
+	now := time.Now()
+	output := []WatchlistItemOutputDTO{}
+	for _, item := range items {
+		auction, ok := auctionsById[item.AuctionId]
+		if !ok {
+			continue
+		}
+
+		timeLeft := auction.ExpiresAt.Sub(now)
+		if timeLeft < 0 || auction.Status != entity.Active {
+			timeLeft = 0
+		}
+
+		var highestBid *entity.Money
+		if bid, ok := highestBids[auction.Id]; ok {
+			amount := bid.Amount
+			highestBid = &amount
+		}
+
+		output = append(output, WatchlistItemOutputDTO{
+			AuctionId:       auction.Id,
+			ProductName:     auction.ProductName,
+			Category:        auction.Category,
+			Condition:       auction.Condition,
+			Status:          auction.Status,
+			ExpiresAt:       auction.ExpiresAt,
+			TimeLeftSeconds: int64(timeLeft.Seconds()),
+			HighestBid:      highestBid,
+			WatchedSince:    item.Timestamp,
+		})
+	}
+
+	return output, nil
+}
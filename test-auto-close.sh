@@ -13,6 +13,8 @@ NC='\033[0m' # No Color
 
 # URL da API
 API_URL="http://localhost:8080"
+# Token de operador das rotas /admin (o mesmo do docker-compose)
+ADMIN_TOKEN="${ADMIN_TOKEN:-dev-admin-token-change-me}"
 
 echo -e "${BLUE}1. Criando um leilão...${NC}"
 RESPONSE=$(curl -s -X POST "$API_URL/auction" \
@@ -42,6 +44,14 @@ curl -s "$API_URL/auction/$AUCTION_ID" | jq '.'
 echo ""
 
 echo -e "${BLUE}3. Criando alguns lances...${NC}"
+# Os lances reservam saldo na carteira, então cada usuário deposita antes
+for USER_ID in user-001 user-002 user-003; do
+  curl -s -X POST "$API_URL/admin/wallets/$USER_ID/deposits" \
+    -H "Authorization: Bearer $ADMIN_TOKEN" \
+    -H "Content-Type: application/json" \
+    -d '{"amount": "1000.00"}' > /dev/null
+done
+
 curl -s -X POST "$API_URL/bid" \
   -H "Content-Type: application/json" \
   -d "{